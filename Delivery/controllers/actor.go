@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/gin-gonic/gin"
+)
+
+func actorFromContext(ctx *gin.Context) domain.Actor {
+	return domain.Actor{
+		UserID: ctx.GetString("user_id"),
+		Role: ctx.GetString("role"),
+		OrganizationID: ctx.GetString("organization_id"),
+		OrganizationRole: ctx.GetString("organization_role"),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type APITokenInput struct {
+	Name string `json:"name"`
+	Scopes []string `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type APITokenController struct {
+	APITokenUsecase usecases.APITokenUsecase
+}
+
+func (ac *APITokenController) Create(ctx *gin.Context) {
+	var input APITokenInput
+
+	userID := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var callerScopes []string
+	if scopes, ok := ctx.Get("scopes"); ok {
+		callerScopes = scopes.([]string)
+	}
+
+	rawToken, token, err := ac.APITokenUsecase.Create(userID, input.Name, input.Scopes, input.ExpiresAt, callerScopes)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"token": rawToken, "api_token": token})
+}
+
+func (ac *APITokenController) FetchAll(ctx *gin.Context) {
+	userID := ctx.Param("id")
+
+	tokens, err := ac.APITokenUsecase.FetchAll(userID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"api_tokens": tokens})
+}
+
+func (ac *APITokenController) Revoke(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	tokenID := ctx.Param("token_id")
+
+	err := ac.APITokenUsecase.Revoke(userID, tokenID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+const multipartOverhead = 1 << 20
+
+type AttachmentController struct {
+	AttachmentUsecase usecases.AttachmentUsecase
+}
+
+func (ac *AttachmentController) Upload(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, ac.AttachmentUsecase.MaxBytes()+multipartOverhead)
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing file"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unable to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := ac.AttachmentUsecase.Upload(actorFromContext(ctx), taskID, header.Filename, header.Size, file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, attachment)
+}
+
+func (ac *AttachmentController) FetchAll(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+
+	attachments, err := ac.AttachmentUsecase.FetchAll(actorFromContext(ctx), taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+func (ac *AttachmentController) Download(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+	id := ctx.Param("attachment_id")
+
+	attachment, content, err := ac.AttachmentUsecase.Download(actorFromContext(ctx), taskID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (ac *AttachmentController) Remove(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+	id := ctx.Param("attachment_id")
+
+	err := ac.AttachmentUsecase.Remove(actorFromContext(ctx), taskID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarFeedInput struct {
+	Name string `json:"name"`
+}
+
+type CalendarFeedController struct {
+	CalendarFeedUsecase usecases.CalendarFeedUsecase
+}
+
+func (cc *CalendarFeedController) Create(ctx *gin.Context) {
+	var input CalendarFeedInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rawToken, feed, err := cc.CalendarFeedUsecase.Create(actorFromContext(ctx), input.Name)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"url": scheme + "://" + ctx.Request.Host + "/calendar/" + rawToken + ".ics", "feed": feed})
+}
+
+func (cc *CalendarFeedController) FetchAll(ctx *gin.Context) {
+	feeds, err := cc.CalendarFeedUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"feeds": feeds})
+}
+
+func (cc *CalendarFeedController) Revoke(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := cc.CalendarFeedUsecase.Revoke(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (cc *CalendarFeedController) Feed(ctx *gin.Context) {
+	rawToken := strings.TrimSuffix(ctx.Param("token"), ".ics")
+
+	calendar, err := cc.CalendarFeedUsecase.Render(rawToken, ctx.Query("component"))
+	if err != nil {
+		status := http.StatusNotFound
+		if err.Error() != "feed not found" {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("ETag", calendar.ETag)
+	ctx.Header("Last-Modified", calendar.LastModified.Format(http.TimeFormat))
+	ctx.Header("Cache-Control", "private, no-cache")
+
+	if match := ctx.GetHeader("If-None-Match"); match != "" {
+		if match == calendar.ETag || match == "*" {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := time.Parse(http.TimeFormat, ctx.GetHeader("If-Modified-Since")); err == nil && !calendar.LastModified.After(since) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar.Body))
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentInput struct {
+	Body string `json:"body"`
+}
+
+type CommentController struct {
+	CommentUsecase usecases.CommentUsecase
+}
+
+func (cc *CommentController) Create(ctx *gin.Context) {
+	var input CommentInput
+
+	taskID := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := cc.CommentUsecase.Create(actorFromContext(ctx), taskID, input.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+func (cc *CommentController) FetchAll(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+
+	comments, err := cc.CommentUsecase.FetchAll(actorFromContext(ctx), taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+func (cc *CommentController) Update(ctx *gin.Context) {
+	var input CommentInput
+
+	taskID := ctx.Param("id")
+	id := ctx.Param("comment_id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := cc.CommentUsecase.Update(actorFromContext(ctx), taskID, id, input.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
+
+func (cc *CommentController) Remove(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+	id := ctx.Param("comment_id")
+
+	err := cc.CommentUsecase.Remove(actorFromContext(ctx), taskID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
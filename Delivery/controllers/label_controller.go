@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type LabelInput struct {
+	Name string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TaskLabelInput struct {
+	LabelID string `json:"label_id"`
+}
+
+type LabelController struct {
+	LabelUsecase usecases.LabelUsecase
+}
+
+func (lc *LabelController) Create(ctx *gin.Context) {
+	var input LabelInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	newLabel := domain.Label{
+		Name: input.Name,
+		Color: input.Color,
+	}
+
+	label, err := lc.LabelUsecase.Create(actorFromContext(ctx), &newLabel)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, label)
+}
+
+func (lc *LabelController) FetchAll(ctx *gin.Context) {
+	labels, err := lc.LabelUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"labels": labels})
+}
+
+func (lc *LabelController) Update(ctx *gin.Context) {
+	var input LabelInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := lc.LabelUsecase.Update(actorFromContext(ctx), id, domain.Label{Name: input.Name, Color: input.Color})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, label)
+}
+
+func (lc *LabelController) Remove(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := lc.LabelUsecase.Remove(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (lc *LabelController) Assign(ctx *gin.Context) {
+	var input TaskLabelInput
+
+	taskID := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := lc.LabelUsecase.Assign(actorFromContext(ctx), taskID, input.LabelID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (lc *LabelController) Unassign(ctx *gin.Context) {
+	taskID := ctx.Param("id")
+	labelID := ctx.Param("label_id")
+
+	task, err := lc.LabelUsecase.Unassign(actorFromContext(ctx), taskID, labelID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
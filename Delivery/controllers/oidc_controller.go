@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+const oidcSessionCookie = "oidc_session"
+
+type OIDCController struct {
+	OIDCUsecase usecases.OIDCUsecase
+}
+
+func (oc *OIDCController) Begin(ctx *gin.Context) {
+	authURL, sessionToken, err := oc.OIDCUsecase.Begin()
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcSessionCookie, sessionToken, 600, "/login/oidc", "", ctx.Request.TLS != nil, true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+func (oc *OIDCController) Callback(ctx *gin.Context) {
+	if providerError := ctx.Query("error"); providerError != "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": providerError})
+		return
+	}
+
+	sessionToken, err := ctx.Cookie(oidcSessionCookie)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "sign in session expired"})
+		return
+	}
+	ctx.SetCookie(oidcSessionCookie, "", -1, "/login/oidc", "", ctx.Request.TLS != nil, true)
+
+	result, err := oc.OIDCUsecase.Callback(sessionToken, ctx.Query("state"), ctx.Query("code"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationInput struct {
+	Name string `json:"name"`
+}
+
+type OrganizationMemberInput struct {
+	UserID string `json:"user_id"`
+	Role string `json:"role"`
+}
+
+type OrganizationController struct {
+	OrganizationUsecase usecases.OrganizationUsecase
+}
+
+func (oc *OrganizationController) Create(ctx *gin.Context) {
+	var input OrganizationInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := oc.OrganizationUsecase.Create(actorFromContext(ctx), &domain.Organization{Name: input.Name})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, organization)
+}
+
+func (oc *OrganizationController) FetchAll(ctx *gin.Context) {
+	organizations, err := oc.OrganizationUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"organizations": organizations})
+}
+
+func (oc *OrganizationController) SetMember(ctx *gin.Context) {
+	var input OrganizationMemberInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := oc.OrganizationUsecase.SetMember(actorFromContext(ctx), input.UserID, input.Role)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (oc *OrganizationController) RemoveMember(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	err := oc.OrganizationUsecase.RemoveMember(actorFromContext(ctx), userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectInput struct {
+	Name string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ProjectController struct {
+	ProjectUsecase usecases.ProjectUsecase
+}
+
+func (pc *ProjectController) Create(ctx *gin.Context) {
+	var input ProjectInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	newProject := domain.Project{
+		Name: input.Name,
+		Description: input.Description,
+	}
+
+	project, err := pc.ProjectUsecase.Create(actorFromContext(ctx), &newProject)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, project)
+}
+
+func (pc *ProjectController) FetchAll(ctx *gin.Context) {
+	projects, err := pc.ProjectUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+func (pc *ProjectController) Fetch(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	project, err := pc.ProjectUsecase.Fetch(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, project)
+}
+
+func (pc *ProjectController) Update(ctx *gin.Context) {
+	var input ProjectInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedProject := domain.Project{
+		Name: input.Name,
+		Description: input.Description,
+	}
+
+	project, err := pc.ProjectUsecase.Update(actorFromContext(ctx), id, updatedProject)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, project)
+}
+
+func (pc *ProjectController) Remove(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := pc.ProjectUsecase.Remove(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (pc *ProjectController) SetMember(ctx *gin.Context) {
+	var member domain.ProjectMember
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := pc.ProjectUsecase.SetMember(actorFromContext(ctx), id, member)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, project)
+}
+
+func (pc *ProjectController) RemoveMember(ctx *gin.Context) {
+	id := ctx.Param("id")
+	userID := ctx.Param("user_id")
+
+	project, err := pc.ProjectUsecase.RemoveMember(actorFromContext(ctx), id, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, project)
+}
+
+func (pc *ProjectController) FetchTasks(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	tasks, err := pc.ProjectUsecase.FetchTasks(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
\ No newline at end of file
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskBulkController struct {
+	TaskBulkUsecase usecases.TaskBulkUsecase
+}
+
+func (bc *TaskBulkController) Apply(ctx *gin.Context) {
+	var request domain.TaskBulkRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := bc.TaskBulkUsecase.Apply(actorFromContext(ctx), request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !response.Committed {
+		ctx.JSON(http.StatusConflict, response)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
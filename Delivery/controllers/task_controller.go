@@ -1,15 +1,38 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Usecases"
 	"github.com/gin-gonic/gin"
 )
 
+type ChecklistItemInput struct {
+	Title string `json:"title"`
+}
+
+type DependencyInput struct {
+	BlockedBy string `json:"blocked_by"`
+}
+
+const maxImportBytes = 10 << 20
+
+var exportContentTypes = map[string]string{
+	"csv": "text/csv; charset=utf-8",
+	"json": "application/json; charset=utf-8",
+	"ndjson": "application/x-ndjson",
+}
+
 type TaskController struct {
 	TaskUsecase usecases.TaskUsecase
+	TaskViewUsecase usecases.TaskViewUsecase
 }
 
 func (tc *TaskController) Create(ctx *gin.Context) {
@@ -20,17 +43,129 @@ func (tc *TaskController) Create(ctx *gin.Context) {
 		return
 	}
 	
-	task, err := tc.TaskUsecase.Create(&newTask)
+	task, err := tc.TaskUsecase.Create(actorFromContext(ctx), &newTask)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, err.Error())
+		return
 	}
 	ctx.JSON(http.StatusCreated, task)
 }
 
 func (tc *TaskController) FetchAll(ctx *gin.Context) {
-	tasks, err := tc.TaskUsecase.FetchAll()
+	if viewID := ctx.Query("view"); viewID != "" {
+		if ctx.Query("labels") != "" || ctx.Query("label_match") != "" || ctx.Query("query") != "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "view cannot be combined with other filters"})
+			return
+		}
+
+		tasks, view, err := tc.TaskViewUsecase.FetchTasks(actorFromContext(ctx), viewID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"tasks": tasks, "view": view})
+		return
+	}
+
+	query := domain.TaskQuery{LabelMatch: ctx.Query("label_match"), Expression: ctx.Query("query")}
+	if labels := ctx.Query("labels"); labels != "" {
+		query.Labels = strings.Split(labels, ",")
+	}
+
+	tasks, err := tc.TaskUsecase.FetchAll(actorFromContext(ctx), query)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
+func (tc *TaskController) Export(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "csv")
+	query := domain.TaskQuery{LabelMatch: ctx.Query("label_match"), Expression: ctx.Query("query")}
+	if labels := ctx.Query("labels"); labels != "" {
+		query.Labels = strings.Split(labels, ",")
+	}
+
+	write, err := tc.TaskUsecase.Export(actorFromContext(ctx), query, format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", exportContentTypes[format])
+	ctx.Header("Content-Disposition", `attachment; filename="tasks.`+format+`"`)
+	ctx.Status(http.StatusOK)
+	if err := write(ctx.Writer); err != nil {
+		log.Println("exporting tasks failed:", err)
+	}
+}
+
+func (tc *TaskController) Import(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportBytes)
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing file"})
+		return
+	}
+
+	format := ctx.PostForm("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	mapping := map[string]string{}
+	if mappingStr := ctx.PostForm("mapping"); mappingStr != "" {
+		if err := json.Unmarshal([]byte(mappingStr), &mapping); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of column names to task fields"})
+			return
+		}
+	}
+
+	dryRun := false
+	if dryRunStr := ctx.PostForm("dry_run"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unable to read file"})
+		return
+	}
+	defer file.Close()
+
+	report, err := tc.TaskUsecase.Import(actorFromContext(ctx), format, file, mapping, dryRun)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (tc *TaskController) NextUp(ctx *gin.Context) {
+	limit := 0
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	tasks, err := tc.TaskUsecase.NextUp(actorFromContext(ctx), limit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
@@ -39,7 +174,7 @@ func (tc *TaskController) FetchAll(ctx *gin.Context) {
 func (tc *TaskController) Fetch(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	task, err := tc.TaskUsecase.Fetch(id)
+	task, err := tc.TaskUsecase.Fetch(actorFromContext(ctx), id)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -58,7 +193,7 @@ func (tc *TaskController) Update(ctx *gin.Context) {
 		return
 	}
 
-	task, err := tc.TaskUsecase.Update(id, updatedTask)
+	task, err := tc.TaskUsecase.Update(actorFromContext(ctx), id, updatedTask)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -66,14 +201,146 @@ func (tc *TaskController) Update(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, task)
 }
 
+func (tc *TaskController) UpdateSeries(ctx *gin.Context) {
+	var update domain.Task
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.TaskUsecase.UpdateSeries(actorFromContext(ctx), id, update)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) EndSeries(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	task, err := tc.TaskUsecase.EndSeries(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
 func (tc *TaskController) Remove(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	err := tc.TaskUsecase.Remove(id)
+	err := tc.TaskUsecase.Remove(actorFromContext(ctx), id)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusNoContent, nil)
-}
\ No newline at end of file
+}
+func (tc *TaskController) FetchSubtasks(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	subtasks, err := tc.TaskUsecase.FetchSubtasks(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tasks": subtasks})
+}
+
+func (tc *TaskController) AddChecklistItem(ctx *gin.Context) {
+	var input ChecklistItemInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.TaskUsecase.AddChecklistItem(actorFromContext(ctx), id, input.Title)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, task)
+}
+
+func (tc *TaskController) UpdateChecklistItem(ctx *gin.Context) {
+	var update domain.ChecklistItemUpdate
+
+	id := ctx.Param("id")
+	itemID := ctx.Param("item_id")
+
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.TaskUsecase.UpdateChecklistItem(actorFromContext(ctx), id, itemID, update)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) RemoveChecklistItem(ctx *gin.Context) {
+	id := ctx.Param("id")
+	itemID := ctx.Param("item_id")
+
+	task, err := tc.TaskUsecase.RemoveChecklistItem(actorFromContext(ctx), id, itemID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) AddDependency(ctx *gin.Context) {
+	var input DependencyInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.TaskUsecase.AddDependency(actorFromContext(ctx), id, input.BlockedBy)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) RemoveDependency(ctx *gin.Context) {
+	id := ctx.Param("id")
+	blockerID := ctx.Param("blocker_id")
+
+	task, err := tc.TaskUsecase.RemoveDependency(actorFromContext(ctx), id, blockerID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) DependencyGraph(ctx *gin.Context) {
+	var ids []string
+	if idList := ctx.Query("ids"); idList != "" {
+		ids = strings.Split(idList, ",")
+	}
+
+	graph, err := tc.TaskUsecase.DependencyGraph(actorFromContext(ctx), ids)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, graph)
+}
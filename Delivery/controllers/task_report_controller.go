@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskReportController struct {
+	TaskReportUsecase usecases.TaskReportUsecase
+}
+
+func (rc *TaskReportController) Report(ctx *gin.Context) {
+	query := domain.TaskQuery{LabelMatch: ctx.Query("label_match"), Expression: ctx.Query("query")}
+	if labels := ctx.Query("labels"); labels != "" {
+		query.Labels = strings.Split(labels, ",")
+	}
+
+	report, err := rc.TaskReportUsecase.Report(actorFromContext(ctx), query, ctx.Query("from"), ctx.Query("to"), ctx.Query("bucket"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
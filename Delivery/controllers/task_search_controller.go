@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskSearchController struct {
+	TaskSearchUsecase usecases.TaskSearchUsecase
+}
+
+func (sc *TaskSearchController) Search(ctx *gin.Context) {
+	limit := 0
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	results, err := sc.TaskSearchUsecase.Search(actorFromContext(ctx), ctx.Query("q"), ctx.Query("query"), limit)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+const streamHeartbeat = time.Second * 25
+
+type TaskStreamController struct {
+	TaskStreamUsecase usecases.TaskStreamUsecase
+}
+
+func (sc *TaskStreamController) Stream(ctx *gin.Context) {
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+
+	changes, cancel, err := sc.TaskStreamUsecase.Stream(actorFromContext(ctx), lastEventID)
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	defer cancel()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	fmt.Fprint(ctx.Writer, "retry: 3000\n\n")
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			return true
+		case change, ok := <-changes:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(change)
+			if err != nil {
+				return true
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", change.ID, change.Type, data)
+			return true
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskViewOrderInput struct {
+	IDs []string `json:"ids"`
+}
+
+type TaskViewController struct {
+	TaskViewUsecase usecases.TaskViewUsecase
+}
+
+func (vc *TaskViewController) Create(ctx *gin.Context) {
+	var newView domain.TaskView
+
+	if err := ctx.ShouldBindJSON(&newView); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := vc.TaskViewUsecase.Create(actorFromContext(ctx), &newView)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, view)
+}
+
+func (vc *TaskViewController) FetchAll(ctx *gin.Context) {
+	views, err := vc.TaskViewUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+func (vc *TaskViewController) Fetch(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	view, err := vc.TaskViewUsecase.Fetch(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, view)
+}
+
+func (vc *TaskViewController) Update(ctx *gin.Context) {
+	var update domain.TaskViewUpdate
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := vc.TaskViewUsecase.Update(actorFromContext(ctx), id, update)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, view)
+}
+
+func (vc *TaskViewController) Remove(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := vc.TaskViewUsecase.Remove(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (vc *TaskViewController) Reorder(ctx *gin.Context) {
+	var input TaskViewOrderInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	views, err := vc.TaskViewUsecase.Reorder(actorFromContext(ctx), input.IDs)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"views": views})
+}
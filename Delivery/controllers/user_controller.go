@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	domain "github.com/abeni-al7/task_manager/Domain"
@@ -12,6 +13,7 @@ type UserInput struct {
 	Username string `json:"username"`
 	Email string `json:"email"`
 	Password string `json:"password"`
+	Organization string `json:"organization"`
 }
 
 type PasswordInput struct {
@@ -19,6 +21,16 @@ type PasswordInput struct {
 	NewPassword string `json:"new_password"`
 }
 
+type TwoFactorLoginInput struct {
+	Challenge string `json:"challenge"`
+	Code string `json:"code"`
+}
+
+type TwoFactorInput struct {
+	Password string `json:"password"`
+	Code string `json:"code"`
+}
+
 type UserController struct {
 	UserUsecase usecases.UserUsecase
 }
@@ -38,7 +50,7 @@ func (uc *UserController) Register(ctx *gin.Context) {
 		Email: newUser.Email,
 	}
 	
-	user, err := uc.UserUsecase.Register(&userToRegister)
+	user, err := uc.UserUsecase.Register(&userToRegister, newUser.Organization)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, err.Error())
 		return
@@ -54,12 +66,93 @@ func (uc *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := uc.UserUsecase.Login(user.Username, user.Password)
+	result, err := uc.UserUsecase.Login(user.Username, user.Password)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"token": token})
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (uc *UserController) LoginTwoFactor(ctx *gin.Context) {
+	var input TwoFactorLoginInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := uc.UserUsecase.LoginTwoFactor(input.Challenge, input.Code)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (uc *UserController) EnrollTwoFactor(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	enrollment, err := uc.UserUsecase.EnrollTwoFactor(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+func (uc *UserController) ConfirmTwoFactor(ctx *gin.Context) {
+	var input TwoFactorInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := uc.UserUsecase.ConfirmTwoFactor(id, input.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+func (uc *UserController) DisableTwoFactor(ctx *gin.Context) {
+	var input TwoFactorInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := uc.UserUsecase.DisableTwoFactor(id, input.Password, input.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+func (uc *UserController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var input TwoFactorInput
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := uc.UserUsecase.RegenerateRecoveryCodes(id, input.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
 }
 
 func (uc *UserController) Promote(ctx *gin.Context) {
@@ -75,7 +168,7 @@ func (uc *UserController) Promote(ctx *gin.Context) {
 }
 
 func (uc *UserController) FetchAll(ctx *gin.Context) {
-	users, err := uc.UserUsecase.FetchAll()
+	users, err := uc.UserUsecase.FetchAll(actorFromContext(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -96,18 +189,20 @@ func (uc *UserController) Fetch(ctx *gin.Context) {
 }
 
 func (uc *UserController) Update(ctx *gin.Context) {
-	var updatedUser domain.User
+	var profileUpdate domain.ProfileUpdate
 
 	idStr := ctx.Param("id")
 
-	if err := ctx.ShouldBindJSON(&updatedUser); err != nil {
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&profileUpdate); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid fields"})
 		return
 	}
 
-	user, err := uc.UserUsecase.Update(idStr, updatedUser)
+	user, err := uc.UserUsecase.Update(idStr, profileUpdate)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, user)
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookInput struct {
+	URL string `json:"url"`
+	Events []string `json:"events"`
+}
+
+type WebhookController struct {
+	WebhookUsecase usecases.WebhookUsecase
+}
+
+func (wc *WebhookController) Create(ctx *gin.Context) {
+	var input WebhookInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	newWebhook := domain.Webhook{
+		URL: input.URL,
+		Events: input.Events,
+	}
+
+	webhook, err := wc.WebhookUsecase.Create(actorFromContext(ctx), &newWebhook)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+func (wc *WebhookController) FetchAll(ctx *gin.Context) {
+	webhooks, err := wc.WebhookUsecase.FetchAll(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+func (wc *WebhookController) Fetch(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	webhook, err := wc.WebhookUsecase.Fetch(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (wc *WebhookController) Update(ctx *gin.Context) {
+	var update domain.WebhookUpdate
+
+	id := ctx.Param("id")
+
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := wc.WebhookUsecase.Update(actorFromContext(ctx), id, update)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (wc *WebhookController) Remove(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := wc.WebhookUsecase.Remove(actorFromContext(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (wc *WebhookController) Deliveries(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	limit := 0
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	deliveries, err := wc.WebhookUsecase.Deliveries(actorFromContext(ctx), id, limit)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (wc *WebhookController) DeadLetters(ctx *gin.Context) {
+	deliveries, err := wc.WebhookUsecase.DeadLetters(actorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (wc *WebhookController) Retry(ctx *gin.Context) {
+	id := ctx.Param("id")
+	deliveryID := ctx.Param("delivery_id")
+
+	delivery, err := wc.WebhookUsecase.Retry(actorFromContext(ctx), id, deliveryID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, delivery)
+}
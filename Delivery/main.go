@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/abeni-al7/task_manager/Delivery/router"
+	"github.com/abeni-al7/task_manager/Infrastructure"
 	"github.com/abeni-al7/task_manager/Repositories"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -16,6 +17,13 @@ func main() {
 		log.Fatal("failed to load .env")
 	}
 	repositories.ConnectToMongoDB()
-	routers := router.Init(gin.Default())
+	infrastructure.LoadSigningKeys()
+	engine := gin.New()
+	engine.Use(infrastructure.RequestLogger(), gin.Recovery())
+	routers := router.Init(engine)
+	go router.StartRecurrenceRollover(nil)
+	go router.StartReminderScheduler(nil)
+	go router.StartEventRelay(nil)
+	go router.StartWebhookDelivery(nil)
 	routers.Run(os.Getenv("HOST_URL"))
 }
\ No newline at end of file
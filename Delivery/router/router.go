@@ -1,80 +1,478 @@
 package router
 
 import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/abeni-al7/task_manager/Delivery/controllers"
+	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Infrastructure"
 	"github.com/abeni-al7/task_manager/Repositories"
 	"github.com/abeni-al7/task_manager/Usecases"
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	eventBusOnce sync.Once
+	eventBus *usecases.EventBus
+	taskChangeHub *usecases.TaskChangeHub
+	taskSearchIndex *usecases.TaskSearchIndex
+)
+
 func Init(gin *gin.Engine) *gin.Engine {
+	apiTokens := usecases.NewAPITokenUsecase(
+		repositories.NewAPITokenRepository(repositories.APITokenCollection),
+		repositories.NewUserRepository(repositories.UserCollection),
+		new(infrastructure.Infrastructure),
+	)
+
+	organizations := usecases.NewOrganizationUsecase(
+		repositories.NewOrganizationRepository(repositories.OrganizationCollection),
+		repositories.NewUserRepository(repositories.UserCollection),
+	)
+
 	freeRoutes := gin.Group("")
 	regularRoutes := gin.Group("")
+	tenantRoutes := gin.Group("")
+	streamRoutes := gin.Group("")
+	organizationAdminRoutes := gin.Group("")
 	adminRoutes := gin.Group("")
 	ownerRoutes := gin.Group("")
 
-	regularRoutes.Use(infrastructure.AuthMiddleware())
-	adminRoutes.Use(infrastructure.AuthMiddleware(), infrastructure.IsAdminMiddleware())
-	ownerRoutes.Use(infrastructure.AuthMiddleware(), infrastructure.IsOwnerMiddleware())
+	regularRoutes.Use(infrastructure.AuthMiddleware(apiTokens))
+	tenantRoutes.Use(infrastructure.AuthMiddleware(apiTokens), infrastructure.TenantMiddleware(organizations))
+	streamRoutes.Use(infrastructure.StreamAuthMiddleware(apiTokens), infrastructure.TenantMiddleware(organizations))
+	organizationAdminRoutes.Use(infrastructure.AuthMiddleware(apiTokens), infrastructure.TenantMiddleware(organizations), infrastructure.IsOrganizationAdminMiddleware())
+	adminRoutes.Use(infrastructure.AuthMiddleware(apiTokens), infrastructure.IsAdminMiddleware())
+	ownerRoutes.Use(infrastructure.AuthMiddleware(apiTokens), infrastructure.IsOwnerMiddleware())
 
 	AuthRouter(freeRoutes)
-	TaskAccessRouter(regularRoutes)
-	TaskManipulationRouter(adminRoutes)
-	UserControlRouter(adminRoutes)
+	if os.Getenv("OIDC_ISSUER_URL") != "" {
+		OIDCRouter(freeRoutes)
+	}
+	TaskAccessRouter(tenantRoutes)
+	TaskStreamRouter(streamRoutes, tenantRoutes)
+	TaskSearchRouter(tenantRoutes)
+	TaskViewRouter(tenantRoutes)
+	TaskBulkRouter(tenantRoutes)
+	TaskReportRouter(tenantRoutes)
+	CalendarFeedRouter(freeRoutes, tenantRoutes)
+	CommentRouter(tenantRoutes)
+	AttachmentRouter(tenantRoutes)
+	LabelRouter(tenantRoutes, organizationAdminRoutes)
+	WebhookRouter(organizationAdminRoutes)
+	ProjectRouter(tenantRoutes)
+	OrganizationRouter(regularRoutes, organizationAdminRoutes, organizations)
+	UserControlRouter(organizationAdminRoutes, adminRoutes)
 	AccountControlRouter(ownerRoutes)
+	APITokenRouter(ownerRoutes, apiTokens)
 	return gin
 }
 
 func AuthRouter(group *gin.RouterGroup) {
 	ur := repositories.NewUserRepository(repositories.UserCollection)
 	uc := &controllers.UserController{
-		UserUsecase: *usecases.NewUserUsecase(ur, new(infrastructure.Infrastructure)),
+		UserUsecase: *usecases.NewUserUsecase(ur, repositories.NewOrganizationRepository(repositories.OrganizationCollection), new(infrastructure.Infrastructure), events()),
 	}
 
 	group.POST("/register", uc.Register)
 	group.POST("/login", uc.Login)
+	group.POST("/login/2fa", uc.LoginTwoFactor)
+	group.GET("/.well-known/jwks.json", infrastructure.JWKSHandler())
+}
+
+func OIDCRouter(group *gin.RouterGroup) {
+	ur := repositories.NewUserRepository(repositories.UserCollection)
+	provider := infrastructure.NewOIDCProvider(infrastructure.OIDCConfigFromEnv(), nil)
+	oc := &controllers.OIDCController{
+		OIDCUsecase: *usecases.NewOIDCUsecase(provider, ur, new(infrastructure.Infrastructure), events()),
+	}
+
+	group.GET("/login/oidc", oc.Begin)
+	group.GET("/login/oidc/callback", oc.Callback)
 }
 
 func TaskAccessRouter(group *gin.RouterGroup) {
 	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	vr := repositories.NewTaskViewRepository(repositories.TaskViewCollection)
+	tu := usecases.NewTaskUsecase(tr, pr, lr, events())
 	tc := &controllers.TaskController{
-		TaskUsecase: *usecases.NewTaskUsecase(tr),
+		TaskUsecase: *tu,
+		TaskViewUsecase: *usecases.NewTaskViewUsecase(vr, tu),
 	}
 
-	group.GET("/tasks", tc.FetchAll)
-	group.GET("/tasks/:id", tc.Fetch)
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.GET("/tasks", read, tc.FetchAll)
+	group.GET("/tasks/:id", read, tc.Fetch)
+	group.PUT("/tasks/:id", write, tc.Update)
+	group.DELETE("/tasks/:id", write, tc.Remove)
+	group.POST("/tasks", write, tc.Create)
+	group.GET("/tasks/dependencies", read, tc.DependencyGraph)
+	group.GET("/tasks/next", read, tc.NextUp)
+	group.GET("/tasks/export", read, tc.Export)
+	group.POST("/tasks/import", write, tc.Import)
+	group.GET("/tasks/:id/subtasks", read, tc.FetchSubtasks)
+	group.PUT("/tasks/:id/series", write, tc.UpdateSeries)
+	group.DELETE("/tasks/:id/series", write, tc.EndSeries)
+	group.POST("/tasks/:id/dependencies", write, tc.AddDependency)
+	group.DELETE("/tasks/:id/dependencies/:blocker_id", write, tc.RemoveDependency)
+	group.POST("/tasks/:id/checklist", write, tc.AddChecklistItem)
+	group.PUT("/tasks/:id/checklist/:item_id", write, tc.UpdateChecklistItem)
+	group.DELETE("/tasks/:id/checklist/:item_id", write, tc.RemoveChecklistItem)
 }
 
-func TaskManipulationRouter(group *gin.RouterGroup) {
+func CalendarFeedRouter(group *gin.RouterGroup, tenantGroup *gin.RouterGroup) {
 	tr := repositories.NewTaskRepository(repositories.TaskCollection)
-	tc := &controllers.TaskController{
-		TaskUsecase: *usecases.NewTaskUsecase(tr),
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	cc := &controllers.CalendarFeedController{
+		CalendarFeedUsecase: *usecases.NewCalendarFeedUsecase(
+			repositories.NewCalendarFeedRepository(repositories.CalendarFeedCollection),
+			repositories.NewUserRepository(repositories.UserCollection),
+			new(infrastructure.Infrastructure),
+			usecases.NewTaskUsecase(tr, pr, lr, events()),
+		),
 	}
 
-	group.PUT("/tasks/:id", tc.Update)
-	group.DELETE("/tasks/:id", tc.Remove)
-	group.POST("/tasks", tc.Create)
+	account := infrastructure.RequireScope("account")
+
+	group.GET("/calendar/:token", cc.Feed)
+	tenantGroup.POST("/calendar/feeds", account, cc.Create)
+	tenantGroup.GET("/calendar/feeds", account, cc.FetchAll)
+	tenantGroup.DELETE("/calendar/feeds/:id", account, cc.Revoke)
 }
 
-func UserControlRouter(group *gin.RouterGroup) {
+func TaskBulkRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	bc := &controllers.TaskBulkController{
+		TaskBulkUsecase: *usecases.NewTaskBulkUsecase(tr, usecases.NewTaskUsecase(tr, pr, lr, events())),
+	}
+
+	group.POST("/tasks/bulk", infrastructure.RequireScope("tasks:write"), bc.Apply)
+}
+
+func TaskViewRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	vr := repositories.NewTaskViewRepository(repositories.TaskViewCollection)
+	vc := &controllers.TaskViewController{
+		TaskViewUsecase: *usecases.NewTaskViewUsecase(vr, usecases.NewTaskUsecase(tr, pr, lr, events())),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.GET("/views", read, vc.FetchAll)
+	group.POST("/views", write, vc.Create)
+	group.PUT("/views/order", write, vc.Reorder)
+	group.GET("/views/:id", read, vc.Fetch)
+	group.PUT("/views/:id", write, vc.Update)
+	group.DELETE("/views/:id", write, vc.Remove)
+}
+
+func TaskStreamRouter(group *gin.RouterGroup, tenantGroup *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	sc := &controllers.TaskStreamController{
+		TaskStreamUsecase: *newTaskStreamUsecase(usecases.NewTaskUsecase(tr, pr, lr, events())),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+
+	group.GET("/tasks/stream", read, sc.Stream)
+	tenantGroup.POST("/tasks/stream/ticket", read, infrastructure.StreamTicketHandler())
+}
+
+func newTaskStreamUsecase(tasks *usecases.TaskUsecase) *usecases.TaskStreamUsecase {
+	if repositories.ChangeStreamsSupported && os.Getenv("TASK_STREAM_SOURCE") != "memory" {
+		return usecases.NewTaskStreamUsecase(repositories.NewTaskChangeStream(repositories.TaskCollection), tasks)
+	}
+	events()
+	return usecases.NewTaskStreamUsecase(taskChangeHub, tasks)
+}
+
+func TaskSearchRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	sc := &controllers.TaskSearchController{
+		TaskSearchUsecase: *newTaskSearchUsecase(tr, usecases.NewTaskUsecase(tr, pr, lr, events())),
+	}
+
+	group.GET("/tasks/search", infrastructure.RequireScope("tasks:read"), sc.Search)
+}
+
+func newTaskSearchUsecase(tr *repositories.TaskRepository, tasks *usecases.TaskUsecase) *usecases.TaskSearchUsecase {
+	if events(); taskSearchIndex != nil {
+		return usecases.NewTaskSearchUsecase(taskSearchIndex, tasks)
+	}
+	return usecases.NewTaskSearchUsecase(tr, tasks)
+}
+
+func TaskReportRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	rc := &controllers.TaskReportController{
+		TaskReportUsecase: *newTaskReportUsecase(tr, usecases.NewTaskUsecase(tr, pr, lr, events())),
+	}
+
+	group.GET("/reports", infrastructure.RequireScope("tasks:read"), rc.Report)
+}
+
+func newTaskReportUsecase(tr *repositories.TaskRepository, tasks *usecases.TaskUsecase) *usecases.TaskReportUsecase {
+	if os.Getenv("TASK_REPORT_BACKEND") == "memory" {
+		return usecases.NewTaskReportUsecase(usecases.NewTaskReportAggregator(tr), tasks)
+	}
+	return usecases.NewTaskReportUsecase(tr, tasks)
+}
+
+func StartRecurrenceRollover(stop <-chan struct{}) {
+	tasks := usecases.NewTaskUsecase(
+		repositories.NewTaskRepository(repositories.TaskCollection),
+		repositories.NewProjectRepository(repositories.ProjectCollection),
+		repositories.NewLabelRepository(repositories.LabelCollection),
+		events(),
+	)
+	tasks.StartRecurrenceRollover(stop)
+}
+
+func StartReminderScheduler(stop <-chan struct{}) {
+	scheduler := usecases.NewReminderScheduler(
+		repositories.NewTaskRepository(repositories.TaskCollection),
+		repositories.NewProjectRepository(repositories.ProjectCollection),
+		repositories.NewUserRepository(repositories.UserCollection),
+		repositories.NewLockRepository(repositories.LockCollection),
+		new(infrastructure.LogNotifier),
+		reminderConfigFromEnv(),
+	)
+	scheduler.Start(stop)
+}
+
+func reminderConfigFromEnv() usecases.ReminderConfig {
+	config := usecases.DefaultReminderConfig()
+	if interval, err := time.ParseDuration(os.Getenv("REMINDER_INTERVAL")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if window, err := time.ParseDuration(os.Getenv("REMINDER_DUE_SOON_WINDOW")); err == nil && window >= 0 {
+		config.DueSoonWindow = window
+	}
+	config.AutoFlagOverdue, _ = strconv.ParseBool(os.Getenv("REMINDER_AUTO_FLAG_OVERDUE"))
+	if hostname, err := os.Hostname(); err == nil {
+		config.Owner = hostname + ":" + strconv.Itoa(os.Getpid())
+	}
+	return config
+}
+
+func CommentRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	cc := &controllers.CommentController{
+		CommentUsecase: *usecases.NewCommentUsecase(
+			repositories.NewCommentRepository(repositories.CommentCollection),
+			usecases.NewTaskUsecase(tr, pr, lr, events()),
+			repositories.NewUserRepository(repositories.UserCollection),
+			new(infrastructure.Infrastructure),
+		),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.GET("/tasks/:id/comments", read, cc.FetchAll)
+	group.POST("/tasks/:id/comments", write, cc.Create)
+	group.PUT("/tasks/:id/comments/:comment_id", write, cc.Update)
+	group.DELETE("/tasks/:id/comments/:comment_id", write, cc.Remove)
+}
+
+func AttachmentRouter(group *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	ac := &controllers.AttachmentController{
+		AttachmentUsecase: *usecases.NewAttachmentUsecase(
+			tr,
+			usecases.NewTaskUsecase(tr, pr, lr, events()),
+			infrastructure.BlobStorageFromEnv(),
+			attachmentLimitsFromEnv(),
+		),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.GET("/tasks/:id/attachments", read, ac.FetchAll)
+	group.POST("/tasks/:id/attachments", write, ac.Upload)
+	group.GET("/tasks/:id/attachments/:attachment_id", read, ac.Download)
+	group.DELETE("/tasks/:id/attachments/:attachment_id", write, ac.Remove)
+}
+
+func LabelRouter(group *gin.RouterGroup, adminGroup *gin.RouterGroup) {
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	lr := repositories.NewLabelRepository(repositories.LabelCollection)
+	lc := &controllers.LabelController{
+		LabelUsecase: *usecases.NewLabelUsecase(
+			lr,
+			tr,
+			usecases.NewTaskUsecase(tr, pr, lr, events()),
+		),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.GET("/labels", read, lc.FetchAll)
+	adminGroup.POST("/labels", write, lc.Create)
+	adminGroup.PUT("/labels/:id", write, lc.Update)
+	adminGroup.DELETE("/labels/:id", write, lc.Remove)
+	group.POST("/tasks/:id/labels", write, lc.Assign)
+	group.DELETE("/tasks/:id/labels/:label_id", write, lc.Unassign)
+}
+
+func attachmentLimitsFromEnv() usecases.AttachmentLimits {
+	limits := usecases.DefaultAttachmentLimits()
+	if maxBytes, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES"), 10, 64); err == nil && maxBytes > 0 {
+		limits.MaxBytes = maxBytes
+	}
+	if allowedTypes := os.Getenv("ATTACHMENT_ALLOWED_TYPES"); allowedTypes != "" {
+		limits.AllowedTypes = strings.Split(allowedTypes, ",")
+	}
+	return limits
+}
+
+func WebhookRouter(group *gin.RouterGroup) {
+	wc := &controllers.WebhookController{
+		WebhookUsecase: *newWebhookUsecase(),
+	}
+
+	scope := infrastructure.RequireScope("webhooks")
+
+	group.POST("/webhooks", scope, wc.Create)
+	group.GET("/webhooks", scope, wc.FetchAll)
+	group.GET("/webhooks/dead-letters", scope, wc.DeadLetters)
+	group.GET("/webhooks/:id", scope, wc.Fetch)
+	group.PUT("/webhooks/:id", scope, wc.Update)
+	group.DELETE("/webhooks/:id", scope, wc.Remove)
+	group.GET("/webhooks/:id/deliveries", scope, wc.Deliveries)
+	group.POST("/webhooks/:id/deliveries/:delivery_id/retry", scope, wc.Retry)
+}
+
+func StartWebhookDelivery(stop <-chan struct{}) {
+	newWebhookUsecase().StartDelivery(stop)
+}
+
+func StartEventRelay(stop <-chan struct{}) {
+	events().StartRelay(stop)
+}
+
+func events() *usecases.EventBus {
+	eventBusOnce.Do(func() {
+		eventBus = usecases.NewEventBus(repositories.NewOutboxRepository(repositories.OutboxCollection))
+		eventBus.SubscribeAsync("webhooks", "*", newWebhookUsecase().HandleEvent)
+		comments := usecases.NewCommentUsecase(repositories.NewCommentRepository(repositories.CommentCollection), nil, nil, nil)
+		eventBus.SubscribeAsync("comments", domain.EventTaskDeleted, usecases.Typed(comments.HandleTaskDeleted))
+
+		taskChangeHub = usecases.NewTaskChangeHub()
+		eventBus.Subscribe("*", taskChangeHub.HandleEvent)
+
+		if os.Getenv("TASK_SEARCH_BACKEND") == "memory" {
+			taskSearchIndex = usecases.NewTaskSearchIndex(repositories.NewTaskRepository(repositories.TaskCollection))
+			eventBus.Subscribe("*", taskSearchIndex.HandleEvent)
+		}
+	})
+	return eventBus
+}
+
+func newWebhookUsecase() *usecases.WebhookUsecase {
+	return usecases.NewWebhookUsecase(
+		repositories.NewWebhookRepository(repositories.WebhookCollection),
+		repositories.NewWebhookDeliveryRepository(repositories.WebhookDeliveryCollection),
+		infrastructure.NewWebhookSender(nil),
+		new(infrastructure.Infrastructure),
+	)
+}
+
+func ProjectRouter(group *gin.RouterGroup) {
+	pr := repositories.NewProjectRepository(repositories.ProjectCollection)
+	tr := repositories.NewTaskRepository(repositories.TaskCollection)
+	ur := repositories.NewUserRepository(repositories.UserCollection)
+	pc := &controllers.ProjectController{
+		ProjectUsecase: *usecases.NewProjectUsecase(pr, tr, ur),
+	}
+
+	read := infrastructure.RequireScope("tasks:read")
+	write := infrastructure.RequireScope("tasks:write")
+
+	group.POST("/projects", write, pc.Create)
+	group.GET("/projects", read, pc.FetchAll)
+	group.GET("/projects/:id", read, pc.Fetch)
+	group.PUT("/projects/:id", write, pc.Update)
+	group.DELETE("/projects/:id", write, pc.Remove)
+	group.PUT("/projects/:id/members", write, pc.SetMember)
+	group.DELETE("/projects/:id/members/:user_id", write, pc.RemoveMember)
+	group.GET("/projects/:id/tasks", read, pc.FetchTasks)
+}
+
+func OrganizationRouter(group *gin.RouterGroup, adminGroup *gin.RouterGroup, organizations *usecases.OrganizationUsecase) {
+	oc := &controllers.OrganizationController{
+		OrganizationUsecase: *organizations,
+	}
+
+	group.POST("/organizations", infrastructure.RequireScope("account"), oc.Create)
+	group.GET("/organizations", infrastructure.RequireScope("users:read"), oc.FetchAll)
+	adminGroup.PUT("/organizations/:org_id/members", infrastructure.RequireScope("users:write"), oc.SetMember)
+	adminGroup.DELETE("/organizations/:org_id/members/:user_id", infrastructure.RequireScope("users:write"), oc.RemoveMember)
+}
+
+func UserControlRouter(organizationGroup *gin.RouterGroup, group *gin.RouterGroup) {
 	ur := repositories.NewUserRepository(repositories.UserCollection)
 	uc := &controllers.UserController{
-		UserUsecase: *usecases.NewUserUsecase(ur, new(infrastructure.Infrastructure)),
+		UserUsecase: *usecases.NewUserUsecase(ur, repositories.NewOrganizationRepository(repositories.OrganizationCollection), new(infrastructure.Infrastructure), events()),
 	}
 
-	group.GET("/users", uc.FetchAll)
-	group.PUT("/promote/:id", uc.Promote)
-	group.DELETE("/users/:id", uc.Remove)
+	organizationGroup.GET("/users", infrastructure.RequireScope("users:read"), uc.FetchAll)
+	group.PUT("/promote/:id", infrastructure.RequireScope("users:write"), uc.Promote)
+	group.DELETE("/users/:id", infrastructure.RequireScope("users:write"), uc.Remove)
 }
 
 func AccountControlRouter(group *gin.RouterGroup) {
 	ur := repositories.NewUserRepository(repositories.UserCollection)
 	uc := &controllers.UserController{
-		UserUsecase: *usecases.NewUserUsecase(ur, new(infrastructure.Infrastructure)),
+		UserUsecase: *usecases.NewUserUsecase(ur, repositories.NewOrganizationRepository(repositories.OrganizationCollection), new(infrastructure.Infrastructure), events()),
 	}
 
-	group.GET("/users/:id", uc.Fetch)
-	group.PUT("/users/:id", uc.Update)
-	group.PUT("/users/:id/change-password", uc.ChangePassword)
+	account := infrastructure.RequireScope("account")
+
+	group.GET("/users/:id", account, uc.Fetch)
+	group.PUT("/users/:id", account, uc.Update)
+	group.PUT("/users/:id/change-password", account, uc.ChangePassword)
+	group.POST("/users/:id/2fa/enroll", account, uc.EnrollTwoFactor)
+	group.POST("/users/:id/2fa/confirm", account, uc.ConfirmTwoFactor)
+	group.POST("/users/:id/2fa/disable", account, uc.DisableTwoFactor)
+	group.POST("/users/:id/2fa/recovery-codes", account, uc.RegenerateRecoveryCodes)
+}
+
+func APITokenRouter(group *gin.RouterGroup, apiTokens *usecases.APITokenUsecase) {
+	ac := &controllers.APITokenController{
+		APITokenUsecase: *apiTokens,
+	}
+
+	account := infrastructure.RequireScope("account")
+
+	group.POST("/users/:id/tokens", account, ac.Create)
+	group.GET("/users/:id/tokens", account, ac.FetchAll)
+	group.DELETE("/users/:id/tokens/:token_id", account, ac.Revoke)
 }
\ No newline at end of file
@@ -6,22 +6,448 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted = "task.deleted"
+	EventUserRegistered = "user.registered"
+)
+
 type Task struct {
 	ID primitive.ObjectID `bson:"_id" json:"id"`
+	ExternalID string `bson:"external_id,omitempty" json:"external_id,omitempty"`
 	Title string `bson:"title" json:"title"`
 	Description string `bson:"description" json:"description"`
 	DueDate time.Time `bson:"due_date" json:"due_date"`
 	Status string `bson:"status" json:"status"`
+	Priority string `bson:"priority" json:"priority"`
+	Rank *int `bson:"rank,omitempty" json:"rank,omitempty"`
+	ProjectID primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitzero"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	ParentID primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitzero"`
+	AutoComplete *bool `bson:"auto_complete,omitempty" json:"auto_complete,omitempty"`
+	Checklist []ChecklistItem `bson:"checklist" json:"checklist"`
+	BlockedBy []primitive.ObjectID `bson:"blocked_by" json:"blocked_by"`
+	Attachments []Attachment `bson:"attachments" json:"attachments"`
+	Labels []primitive.ObjectID `bson:"labels" json:"labels"`
+	Recurrence *Recurrence `bson:"recurrence,omitempty" json:"recurrence,omitempty"`
+	SeriesID primitive.ObjectID `bson:"series_id,omitempty" json:"series_id,omitzero"`
+	NextGenerated bool `bson:"next_generated,omitempty" json:"-"`
+	Overdue bool `bson:"overdue,omitempty" json:"overdue"`
+	DueSoonNotified bool `bson:"due_soon_notified,omitempty" json:"-"`
+	OverdueNotified bool `bson:"overdue_notified,omitempty" json:"-"`
+	Progress *TaskProgress `bson:"-" json:"progress,omitempty"`
+	Score *float64 `bson:"-" json:"score,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitzero"`
+	StatusChangedAt time.Time `bson:"status_changed_at,omitempty" json:"status_changed_at,omitzero"`
+	CompletedAt time.Time `bson:"completed_at,omitempty" json:"completed_at,omitzero"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Attachment struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	Filename string `bson:"filename" json:"filename"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Size int64 `bson:"size" json:"size"`
+	StorageKey string `bson:"storage_key" json:"-"`
+	UploadedBy primitive.ObjectID `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+type Recurrence struct {
+	Rule string `bson:"rule" json:"rule"`
+	Start time.Time `bson:"start" json:"start"`
+}
+
+type ChecklistItem struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	Title string `bson:"title" json:"title"`
+	Done bool `bson:"done" json:"done"`
+}
+
+type ChecklistItemUpdate struct {
+	Title *string `json:"title"`
+	Done *bool `json:"done"`
+}
+
+type TaskProgress struct {
+	CompletedSubtasks int `json:"completed_subtasks"`
+	TotalSubtasks int `json:"total_subtasks"`
+	CompletedChecklistItems int `json:"completed_checklist_items"`
+	TotalChecklistItems int `json:"total_checklist_items"`
+}
+
+type Comment struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	TaskID primitive.ObjectID `bson:"task_id" json:"task_id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	AuthorID primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Body string `bson:"body" json:"body"`
+	BodyHTML string `bson:"-" json:"body_html"`
+	Mentions []primitive.ObjectID `bson:"mentions" json:"mentions"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Notification struct {
+	Kind string `json:"kind"`
+	Task Task `json:"task"`
+	Recipients []User `json:"recipients"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Webhook struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	URL string `bson:"url" json:"url"`
+	Events []string `bson:"events" json:"events"`
+	Secret string `bson:"secret" json:"secret,omitempty"`
+	Active bool `bson:"active" json:"active"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type WebhookUpdate struct {
+	URL *string `json:"url"`
+	Events []string `json:"events"`
+	Active *bool `json:"active"`
+}
+
+type Event struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	Type string `bson:"type" json:"type"`
+	OrganizationID primitive.ObjectID `bson:"organization_id,omitempty" json:"organization_id,omitzero"`
+	Payload string `bson:"payload" json:"payload"`
+	OccurredAt time.Time `bson:"occurred_at" json:"occurred_at"`
+	Status string `bson:"status" json:"status"`
+	Handled []string `bson:"handled" json:"handled"`
+	Attempts int `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError string `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	PublishedAt *time.Time `bson:"published_at,omitempty" json:"published_at,omitempty"`
+}
+
+type TaskChange struct {
+	ID string `json:"-"`
+	Type string `json:"type"`
+	Task Task `json:"task"`
+}
+
+type TaskStatusChange struct {
+	Task Task `json:"task"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+type WebhookDelivery struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	WebhookID primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	Event string `bson:"event" json:"event"`
+	Payload string `bson:"payload" json:"payload"`
+	Status string `bson:"status" json:"status"`
+	Attempts []WebhookAttempt `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time `bson:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type WebhookAttempt struct {
+	At time.Time `bson:"at" json:"at"`
+	ResponseStatus int `bson:"response_status,omitempty" json:"response_status,omitempty"`
+	Error string `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+type DependencyEdge struct {
+	BlockerID primitive.ObjectID `json:"blocker_id"`
+	BlockedID primitive.ObjectID `json:"blocked_id"`
+}
+
+type DependencyGraph struct {
+	Tasks []Task `json:"tasks"`
+	Edges []DependencyEdge `json:"edges"`
+	Order []primitive.ObjectID `json:"order"`
+}
+
+type TaskQuery struct {
+	Labels []string `bson:"labels,omitempty" json:"labels,omitempty"`
+	LabelMatch string `bson:"label_match,omitempty" json:"label_match,omitempty"`
+	Expression string `bson:"query,omitempty" json:"query,omitempty"`
+}
+
+type TaskExpression struct {
+	Op string
+	Field string
+	Value interface{}
+	Children []TaskExpression
+}
+
+type TaskFilter struct {
+	IDs []primitive.ObjectID
+	ExternalIDs []string
+	ProjectID primitive.ObjectID
+	ParentID primitive.ObjectID
+	ParentIDs []primitive.ObjectID
+	SeriesID primitive.ObjectID
+	Statuses []string
+	Labels []primitive.ObjectID
+	MatchAllLabels bool
+	Restricted bool
+	VisibleProjectIDs []primitive.ObjectID
+	Expression *TaskExpression
+}
+
+type TaskView struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	OwnerID primitive.ObjectID `bson:"owner_id" json:"owner_id"`
+	Name string `bson:"name" json:"name"`
+	Visibility string `bson:"visibility" json:"visibility"`
+	Query TaskQuery `bson:"query" json:"query"`
+	Sort []string `bson:"sort" json:"sort"`
+	Columns []string `bson:"columns" json:"columns"`
+	Positions map[string]int `bson:"positions,omitempty" json:"-"`
+	Position *int `bson:"-" json:"position,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type TaskViewUpdate struct {
+	Name *string `json:"name"`
+	Visibility *string `json:"visibility"`
+	Query *TaskQuery `json:"query"`
+	Sort []string `json:"sort"`
+	Columns []string `json:"columns"`
+}
+
+type TaskBulkOperation struct {
+	Op string `json:"op"`
+	ID string `json:"id"`
+	Task Task `json:"task"`
+}
+
+type TaskBulkRequest struct {
+	Mode string `json:"mode"`
+	Operations []TaskBulkOperation `json:"operations"`
+	Filter *TaskQuery `json:"filter"`
+	Patch *Task `json:"patch"`
+}
+
+type TaskBulkResult struct {
+	Index int `json:"index"`
+	Op string `json:"op"`
+	ID string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Task *Task `json:"task,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type TaskBulkResponse struct {
+	Mode string `json:"mode"`
+	Committed bool `json:"committed"`
+	Succeeded int `json:"succeeded"`
+	Failed int `json:"failed"`
+	Results []TaskBulkResult `json:"results"`
+}
+
+type TaskImportRow struct {
+	Row int `json:"row"`
+	Status string `json:"status"`
+	ID string `json:"id,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type TaskImportReport struct {
+	DryRun bool `json:"dry_run"`
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed int `json:"failed"`
+	Rows []TaskImportRow `json:"rows"`
+}
+
+type TaskSearch struct {
+	Terms []string
+	Phrases []string
+	Excluded []string
+}
+
+type TaskSearchResult struct {
+	Task Task `json:"task"`
+	Score float64 `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+type Label struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	Name string `bson:"name" json:"name"`
+	Color string `bson:"color" json:"color"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Project struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	Name string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+	Members []ProjectMember `bson:"members" json:"members"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 }
 
+type ProjectMember struct {
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role string `bson:"role" json:"role"`
+}
+
+type Organization struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	Name string `bson:"name" json:"name"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Membership struct {
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	Role string `bson:"role" json:"role"`
+}
+
+type Actor struct {
+	UserID string
+	Role string
+	OrganizationID string
+	OrganizationRole string
+}
+
 type User struct {
 	ID primitive.ObjectID `bson:"_id" json:"id"`
 	Username string `bson:"username" json:"username"`
 	Role string `bson:"role" json:"role"`
 	Email string `bson:"email" json:"email"`
+	DisplayName string `bson:"display_name" json:"display_name"`
+	AvatarURL string `bson:"avatar_url" json:"avatar_url"`
+	Timezone string `bson:"timezone" json:"timezone"`
+	Locale string `bson:"locale" json:"locale"`
+	NotificationPreferences NotificationPreferences `bson:"notification_preferences" json:"notification_preferences"`
 	Password string `bson:"password" json:"-"`
+	TwoFactorEnabled bool `bson:"two_factor_enabled" json:"two_factor_enabled"`
+	TotpSecret string `bson:"totp_secret" json:"-"`
+	PendingTotpSecret string `bson:"pending_totp_secret" json:"-"`
+	TotpLastCounter int64 `bson:"totp_last_counter" json:"-"`
+	RecoveryCodes []string `bson:"recovery_codes" json:"-"`
+	ExternalIdentities []ExternalIdentity `bson:"external_identities" json:"-"`
+	Memberships []Membership `bson:"memberships" json:"memberships"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
-}
\ No newline at end of file
+}
+
+type NotificationPreferences struct {
+	EmailOnAssignment bool `bson:"email_on_assignment" json:"email_on_assignment"`
+	EmailOnDueSoon bool `bson:"email_on_due_soon" json:"email_on_due_soon"`
+	EmailOnOverdue bool `bson:"email_on_overdue" json:"email_on_overdue"`
+	EmailOnComment bool `bson:"email_on_comment" json:"email_on_comment"`
+}
+
+type ProfileUpdate struct {
+	Username *string `json:"username"`
+	Email *string `json:"email"`
+	DisplayName *string `json:"display_name"`
+	AvatarURL *string `json:"avatar_url"`
+	Timezone *string `json:"timezone"`
+	Locale *string `json:"locale"`
+	NotificationPreferences *NotificationPreferences `json:"notification_preferences"`
+}
+
+type ExternalIdentity struct {
+	Issuer string `bson:"issuer" json:"issuer"`
+	Subject string `bson:"subject" json:"subject"`
+}
+
+type OIDCIdentity struct {
+	Issuer string
+	Subject string
+	Email string
+	Username string
+	Role string
+}
+
+type LoginResult struct {
+	Token string `json:"token,omitempty"`
+	TwoFactorRequired bool `json:"two_factor_required"`
+	Challenge string `json:"challenge,omitempty"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type CalendarFeed struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	Name string `bson:"name" json:"name"`
+	Prefix string `bson:"prefix" json:"prefix"`
+	TokenHash string `bson:"token_hash" json:"-"`
+	LastUsedAt time.Time `bson:"last_used_at" json:"last_used_at"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+type Calendar struct {
+	Body string
+	ETag string
+	LastModified time.Time
+}
+
+type APIToken struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name string `bson:"name" json:"name"`
+	Prefix string `bson:"prefix" json:"prefix"`
+	TokenHash string `bson:"token_hash" json:"-"`
+	Scopes []string `bson:"scopes" json:"scopes"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	LastUsedAt time.Time `bson:"last_used_at" json:"last_used_at"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+type TaskReportQuery struct {
+	From time.Time
+	To time.Time
+	Bucket string
+	Now time.Time
+}
+
+type TaskReport struct {
+	From time.Time `json:"from"`
+	To time.Time `json:"to"`
+	Bucket string `json:"bucket"`
+	Total int `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	Overdue int `json:"overdue"`
+	Throughput []TaskReportBucket `json:"throughput"`
+	CycleTime TaskCycleTime `json:"cycle_time"`
+	Users []TaskUserReport `json:"users"`
+}
+
+type TaskReportBucket struct {
+	Start time.Time `json:"start"`
+	Completed int `json:"completed"`
+}
+
+type TaskCycleTime struct {
+	Completed int `json:"completed"`
+	AverageHours float64 `json:"average_hours"`
+}
+
+type TaskUserReport struct {
+	UserID primitive.ObjectID `json:"user_id"`
+	Total int `json:"total"`
+	Overdue int `json:"overdue"`
+	Completed int `json:"completed"`
+	AverageCycleHours float64 `json:"average_cycle_hours"`
+}
@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+const APITokenPrefix = "tm_pat_"
+
+const FeedTokenPrefix = "tm_cal_"
+
+func (infra *Infrastructure) GenerateAPIToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("unable to generate token")
+	}
+	return APITokenPrefix + hex.EncodeToString(raw), nil
+}
+
+func (infra *Infrastructure) GenerateFeedToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("unable to generate token")
+	}
+	return FeedTokenPrefix + hex.EncodeToString(raw), nil
+}
+
+func (infra *Infrastructure) HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
\ No newline at end of file
@@ -2,16 +2,42 @@ package infrastructure
 
 import (
 	"net/http"
+	"os"
+	"strings"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/gin-gonic/gin"
 )
 
 
-func AuthMiddleware() gin.HandlerFunc {
+type APITokenAuthenticator interface {
+	Authenticate(rawToken string) (domain.User, domain.APIToken, error)
+}
+
+type TenantResolver interface {
+	ResolveTenant(userID string, role string, requested string) (string, string, error)
+}
+
+func AuthMiddleware(apiTokens APITokenAuthenticator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
+		if rawToken, ok := bearerAPIToken(authHeader); ok {
+			user, apiToken, err := apiTokens.Authenticate(rawToken)
+			if err != nil {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				ctx.Abort()
+				return
+			}
+
+			setIdentity(ctx, user.ID.Hex(), user.Role, user.TwoFactorEnabled)
+			ctx.Set("scopes", apiToken.Scopes)
+
+			ctx.Next()
+			return
+		}
+
 		token, err := ValidateJwtToken(authHeader)
 		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err})
@@ -26,18 +52,51 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		userID := claims["user_id"]
-		role := claims["role"]
+		userID, _ := claims["user_id"].(string)
+		role, _ := claims["role"].(string)
+		twoFactor, _ := claims["two_factor"].(bool)
 
-		ctx.Set("user_id", userID)
-		ctx.Set("role", role)
+		setIdentity(ctx, userID, role, twoFactor)
 
 		ctx.Next()
 	}
 }
 
+func JWKSHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", "public, max-age=300")
+		ctx.JSON(http.StatusOK, currentSigningKeys().JWKS())
+	}
+}
+
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, ok := ctx.Get("scopes")
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		for _, granted := range scopes.([]string) {
+			if granted == scope {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "token is missing the " + scope + " scope"})
+		ctx.Abort()
+	}
+}
+
 func IsAdminMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if ctx.GetBool("admin_requires_2fa") {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "two-factor authentication is required for admin accounts"})
+			ctx.Abort()
+			return
+		}
+
 		role, ok := ctx.Get("role")
 		if !ok || role != "admin" {
 			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized to access this route"})
@@ -49,6 +108,79 @@ func IsAdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+func TenantMiddleware(tenants TenantResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requested := ctx.Param("org_id")
+		if requested == "" {
+			requested = ctx.GetHeader("X-Organization-ID")
+		}
+
+		tenantID, tenantRole, err := tenants.ResolveTenant(ctx.GetString("user_id"), ctx.GetString("role"), requested)
+		if err != nil {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("organization_id", tenantID)
+		ctx.Set("organization_role", tenantRole)
+
+		ctx.Next()
+	}
+}
+
+func StreamAuthMiddleware(apiTokens APITokenAuthenticator) gin.HandlerFunc {
+	authenticate := AuthMiddleware(apiTokens)
+	return func(ctx *gin.Context) {
+		ticket := ctx.Query("ticket")
+		if ticket == "" || ctx.GetHeader("Authorization") != "" {
+			authenticate(ctx)
+			return
+		}
+
+		claims, err := ValidateStreamTicket(ticket)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			ctx.Abort()
+			return
+		}
+
+		userID, _ := claims["user_id"].(string)
+		role, _ := claims["role"].(string)
+		twoFactor, _ := claims["two_factor"].(bool)
+		organizationID, _ := claims["organization_id"].(string)
+
+		setIdentity(ctx, userID, role, twoFactor)
+		ctx.Request.Header.Set("X-Organization-ID", organizationID)
+
+		ctx.Next()
+	}
+}
+
+func StreamTicketHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ticket, err := GenerateStreamTicket(ctx.GetString("user_id"), ctx.GetString("role"), ctx.GetBool("two_factor"), ctx.GetString("organization_id"))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expires_in": int(streamTicketLifetime.Seconds())})
+	}
+}
+
+func IsOrganizationAdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("role") != "admin" && ctx.GetString("organization_role") != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized to access this route"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 func IsOwnerMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		id := ctx.Param("id")
@@ -62,4 +194,26 @@ func IsOwnerMiddleware() gin.HandlerFunc {
 
 		ctx.Next()
 	}
+}
+
+func setIdentity(ctx *gin.Context, userID string, role string, twoFactor bool) {
+	if role == "admin" && !twoFactor && os.Getenv("REQUIRE_ADMIN_2FA") == "true" {
+		role = "regular"
+		ctx.Set("admin_requires_2fa", true)
+	}
+
+	ctx.Set("user_id", userID)
+	ctx.Set("role", role)
+	ctx.Set("two_factor", twoFactor)
+}
+
+func bearerAPIToken(authHeader string) (string, bool) {
+	authParts := strings.Split(authHeader, " ")
+	if len(authParts) != 2 || strings.ToLower(authParts[0]) != "bearer" {
+		return "", false
+	}
+	if !strings.HasPrefix(authParts[1], APITokenPrefix) {
+		return "", false
+	}
+	return authParts[1], true
 }
\ No newline at end of file
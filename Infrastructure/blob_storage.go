@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type BlobStorage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+func BlobStorageFromEnv() BlobStorage {
+	if os.Getenv("BLOB_STORAGE") == "s3" {
+		return NewS3BlobStorage(S3ConfigFromEnv(), nil)
+	}
+
+	dir := os.Getenv("BLOB_DIR")
+	if dir == "" {
+		dir = "./uploads"
+	}
+	return NewLocalBlobStorage(dir)
+}
+
+type LocalBlobStorage struct {
+	dir string
+}
+
+func NewLocalBlobStorage(dir string) *LocalBlobStorage {
+	return &LocalBlobStorage{dir: dir}
+}
+
+func (ls *LocalBlobStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.New("unable to store file")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return errors.New("unable to store file")
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.New("unable to store file")
+	}
+	if written != size {
+		return errors.New("uploaded file size does not match")
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.New("unable to store file")
+	}
+	return nil
+}
+
+func (ls *LocalBlobStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("file not found")
+	}
+	return file, nil
+}
+
+func (ls *LocalBlobStorage) Delete(key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.New("unable to delete file")
+	}
+	return nil
+}
+
+func (ls *LocalBlobStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", errors.New("invalid storage key")
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errors.New("invalid storage key")
+		}
+	}
+	return filepath.Join(ls.dir, filepath.FromSlash(key)), nil
+}
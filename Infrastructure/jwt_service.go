@@ -2,24 +2,28 @@ package infrastructure
 
 import (
 	"errors"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/abeni-al7/task_manager/Domain"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	challengePurpose = "2fa_challenge"
+	streamTicketPurpose = "stream_ticket"
+	streamTicketLifetime = time.Minute
 )
 
 func (infra *Infrastructure) GenerateJwtToken(user *domain.User) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	jwtToken, err := currentSigningKeys().Sign(jwt.MapClaims{
 		"user_id": user.ID,
 		"email": user.Email,
 		"username": user.Username,
 		"role": user.Role,
-		"exp": time.Now().Add(time.Hour * 24).Unix(),
+		"two_factor": user.TwoFactorEnabled,
+		"exp": time.Now().Add(tokenLifetime).Unix(),
 	})
-
-	jwtToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
 		return "", errors.New("unable to generate token")
 	}
@@ -27,6 +31,66 @@ func (infra *Infrastructure) GenerateJwtToken(user *domain.User) (string, error)
 	return jwtToken, nil
 }
 
+func (infra *Infrastructure) GenerateChallengeToken(user *domain.User) (string, error) {
+	challenge, err := currentSigningKeys().Sign(jwt.MapClaims{
+		"user_id": user.ID.Hex(),
+		"purpose": challengePurpose,
+		"exp": time.Now().Add(time.Minute * 5).Unix(),
+	})
+	if err != nil {
+		return "", errors.New("unable to generate challenge")
+	}
+
+	return challenge, nil
+}
+
+func (infra *Infrastructure) ValidateChallengeToken(challenge string) (string, error) {
+	token, err := parseJwtToken(challenge)
+	if err != nil {
+		return "", errors.New("invalid or expired challenge")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != challengePurpose {
+		return "", errors.New("invalid or expired challenge")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", errors.New("invalid or expired challenge")
+	}
+	return userID, nil
+}
+
+func GenerateStreamTicket(userID string, role string, twoFactor bool, organizationID string) (string, error) {
+	ticket, err := currentSigningKeys().Sign(jwt.MapClaims{
+		"user_id": userID,
+		"role": role,
+		"two_factor": twoFactor,
+		"organization_id": organizationID,
+		"purpose": streamTicketPurpose,
+		"exp": time.Now().Add(streamTicketLifetime).Unix(),
+	})
+	if err != nil {
+		return "", errors.New("unable to generate stream ticket")
+	}
+
+	return ticket, nil
+}
+
+func ValidateStreamTicket(ticket string) (jwt.MapClaims, error) {
+	token, err := parseJwtToken(ticket)
+	if err != nil {
+		return nil, errors.New("invalid or expired stream ticket")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != streamTicketPurpose {
+		return nil, errors.New("invalid or expired stream ticket")
+	}
+	return claims, nil
+}
+
 func ValidateJwtToken(authHeader string) (*jwt.Token, error) {
 	if authHeader == "" {
 		return &jwt.Token{}, errors.New("log in inorder to access this route")
@@ -37,15 +101,21 @@ func ValidateJwtToken(authHeader string) (*jwt.Token, error) {
 		return &jwt.Token{}, errors.New("invalid authorization header")
 	}
 
-	token, err := jwt.Parse(authParts[1], func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	token, err := parseJwtToken(authParts[1])
+	if err != nil {
+		return &jwt.Token{}, errors.New("invalid token")
+	}
 
-	if err != nil || !token.Valid {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && claims["purpose"] != nil {
 		return &jwt.Token{}, errors.New("invalid token")
 	}
 	return token, nil
+}
+
+func parseJwtToken(tokenStr string) (*jwt.Token, error) {
+	token, err := currentSigningKeys().Parse(tokenStr)
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return token, nil
 }
\ No newline at end of file
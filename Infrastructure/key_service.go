@@ -0,0 +1,401 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const tokenLifetime = time.Hour * 24
+
+type KeyConfig struct {
+	Dir string
+	Algorithm string
+	RotationInterval time.Duration
+	TokenLifetime time.Duration
+	Now func() time.Time
+}
+
+type KeyManager struct {
+	config KeyConfig
+
+	mu sync.RWMutex
+	keys []*signingKey
+	reloadedAt time.Time
+}
+
+type signingKey struct {
+	kid string
+	method jwt.SigningMethod
+	private crypto.Signer
+	createdAt time.Time
+}
+
+var (
+	signingKeys *KeyManager
+	signingKeysMu sync.Mutex
+)
+
+func KeyConfigFromEnv() KeyConfig {
+	config := KeyConfig{
+		Dir: os.Getenv("JWT_KEY_DIR"),
+		Algorithm: os.Getenv("JWT_SIGNING_ALG"),
+		RotationInterval: time.Hour * 24 * 30,
+	}
+	if interval, err := time.ParseDuration(os.Getenv("JWT_KEY_ROTATION_INTERVAL")); err == nil && interval > 0 {
+		config.RotationInterval = interval
+	}
+	return config
+}
+
+func LoadSigningKeys() *KeyManager {
+	config := KeyConfigFromEnv()
+	if config.Dir == "" {
+		log.Fatal("JWT_KEY_DIR must be set, tokens signed with in-memory keys stop working on restart and on other replicas")
+	}
+
+	km, err := NewKeyManager(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	signingKeysMu.Lock()
+	signingKeys = km
+	signingKeysMu.Unlock()
+
+	go km.StartRotation(nil)
+	return km
+}
+
+func currentSigningKeys() *KeyManager {
+	signingKeysMu.Lock()
+	defer signingKeysMu.Unlock()
+
+	if signingKeys == nil {
+		log.Println("WARNING: signing keys were not loaded, using an in-memory key that is lost on restart")
+		km, err := NewKeyManager(KeyConfig{Algorithm: os.Getenv("JWT_SIGNING_ALG")})
+		if err != nil {
+			log.Fatal(err)
+		}
+		signingKeys = km
+	}
+	return signingKeys
+}
+
+func NewKeyManager(config KeyConfig) (*KeyManager, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = "RS256"
+	}
+	if config.Algorithm != "RS256" && config.Algorithm != "EdDSA" {
+		return nil, errors.New("unsupported signing algorithm " + config.Algorithm)
+	}
+	if config.RotationInterval <= 0 {
+		config.RotationInterval = time.Hour * 24 * 30
+	}
+	if config.TokenLifetime <= 0 {
+		config.TokenLifetime = tokenLifetime
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	km := &KeyManager{config: config}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0700); err != nil {
+			return nil, errors.New("unable to create key directory")
+		}
+		keys, err := km.loadKeys()
+		if err != nil {
+			return nil, err
+		}
+		km.keys = keys
+		km.reloadedAt = config.Now()
+	}
+
+	if len(km.keys) == 0 {
+		if err := km.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return km, nil
+}
+
+func (km *KeyManager) Sign(claims jwt.MapClaims) (string, error) {
+	km.mu.RLock()
+	active := km.keys[len(km.keys)-1]
+	km.mu.RUnlock()
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.kid
+	return token.SignedString(active.private)
+}
+
+func (km *KeyManager) Parse(tokenStr string) (*jwt.Token, error) {
+	return jwt.Parse(tokenStr, km.keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(km.config.Now),
+	)
+}
+
+func (km *KeyManager) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key := km.findKey(kid)
+	if key == nil && km.config.Dir != "" && km.config.Now().Sub(km.lastReload()) > time.Minute {
+		if err := km.Reload(); err == nil {
+			key = km.findKey(kid)
+		}
+	}
+	if key == nil {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("signing method mismatch")
+	}
+	return key.private.Public(), nil
+}
+
+func (km *KeyManager) findKey(kid string) *signingKey {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	for _, key := range km.keys {
+		if key.kid == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+func (km *KeyManager) lastReload() time.Time {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.reloadedAt
+}
+
+func (km *KeyManager) Reload() error {
+	keys, err := km.loadKeys()
+	if err != nil {
+		return err
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if len(keys) > 0 {
+		km.keys = keys
+	}
+	km.reloadedAt = km.config.Now()
+	return nil
+}
+
+func (km *KeyManager) Rotate() error {
+	key, err := generateSigningKey(km.config.Algorithm, km.config.Now())
+	if err != nil {
+		return err
+	}
+
+	if km.config.Dir != "" {
+		if err := km.saveKey(key); err != nil {
+			return err
+		}
+	}
+
+	km.mu.Lock()
+	km.keys = append(km.keys, key)
+	km.mu.Unlock()
+
+	km.prune()
+	return nil
+}
+
+func (km *KeyManager) RotateIfDue() error {
+	km.mu.RLock()
+	active := km.keys[len(km.keys)-1]
+	km.mu.RUnlock()
+
+	if km.config.Now().Sub(active.createdAt) < km.config.RotationInterval {
+		return nil
+	}
+	return km.Rotate()
+}
+
+func (km *KeyManager) StartRotation(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if km.config.Dir != "" {
+				km.Reload()
+			}
+			if err := km.RotateIfDue(); err != nil {
+				log.Println("signing key rotation failed:", err)
+			}
+		}
+	}
+}
+
+func (km *KeyManager) prune() {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	now := km.config.Now()
+	kept := []*signingKey{}
+	for i, key := range km.keys {
+		if i < len(km.keys)-1 && now.Sub(km.keys[i+1].createdAt) > km.config.TokenLifetime {
+			if km.config.Dir != "" {
+				os.Remove(filepath.Join(km.config.Dir, key.kid+".pem"))
+			}
+			continue
+		}
+		kept = append(kept, key)
+	}
+	km.keys = kept
+}
+
+func (km *KeyManager) JWKS() map[string]interface{} {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	keys := []map[string]string{}
+	for _, key := range km.keys {
+		jwk := map[string]string{
+			"kid": key.kid,
+			"use": "sig",
+			"alg": key.method.Alg(),
+		}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public)
+		}
+		keys = append(keys, jwk)
+	}
+	return map[string]interface{}{"keys": keys}
+}
+
+func (km *KeyManager) loadKeys() ([]*signingKey, error) {
+	paths, err := filepath.Glob(filepath.Join(km.config.Dir, "*.pem"))
+	if err != nil {
+		return nil, errors.New("unable to read key directory")
+	}
+
+	keys := []*signingKey{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.New("unable to read signing key " + path)
+		}
+
+		block, _ := pem.Decode(content)
+		if block == nil || block.Type != "PRIVATE KEY" {
+			return nil, errors.New("invalid signing key " + path)
+		}
+
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.New("invalid signing key " + path)
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, block.Headers["Created-At"])
+		if err != nil {
+			return nil, errors.New("signing key is missing its creation time " + path)
+		}
+
+		key := &signingKey{
+			kid: strings.TrimSuffix(filepath.Base(path), ".pem"),
+			createdAt: createdAt,
+		}
+		switch signer := private.(type) {
+		case *rsa.PrivateKey:
+			key.method = jwt.SigningMethodRS256
+			key.private = signer
+		case ed25519.PrivateKey:
+			key.method = jwt.SigningMethodEdDSA
+			key.private = signer
+		default:
+			return nil, errors.New("unsupported signing key " + path)
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+	return keys, nil
+}
+
+func (km *KeyManager) saveKey(key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return errors.New("unable to encode signing key")
+	}
+
+	block := &pem.Block{
+		Type: "PRIVATE KEY",
+		Headers: map[string]string{"Created-At": key.createdAt.UTC().Format(time.RFC3339)},
+		Bytes: der,
+	}
+
+	path := filepath.Join(km.config.Dir, key.kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return errors.New("unable to store signing key")
+	}
+	return nil
+}
+
+func generateSigningKey(algorithm string, createdAt time.Time) (*signingKey, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, errors.New("unable to generate key id")
+	}
+
+	key := &signingKey{
+		kid: createdAt.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(raw),
+		createdAt: createdAt,
+	}
+
+	switch algorithm {
+	case "EdDSA":
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, errors.New("unable to generate signing key")
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.private = private
+	default:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, errors.New("unable to generate signing key")
+		}
+		key.method = jwt.SigningMethodRS256
+		key.private = private
+	}
+	return key, nil
+}
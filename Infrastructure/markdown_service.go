@@ -0,0 +1,22 @@
+package infrastructure
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	markdownPolicy = bluemonday.UGCPolicy()
+)
+
+func (infra *Infrastructure) RenderMarkdown(source string) string {
+	var rendered bytes.Buffer
+	if err := markdown.Convert([]byte(source), &rendered); err != nil {
+		return markdownPolicy.Sanitize(source)
+	}
+	return markdownPolicy.SanitizeReader(&rendered).String()
+}
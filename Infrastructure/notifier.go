@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"log"
+
+	"github.com/abeni-al7/task_manager/Domain"
+)
+
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+func (ln *LogNotifier) Notify(notification domain.Notification) error {
+	logger := ln.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	recipients := make([]string, 0, len(notification.Recipients))
+	for _, user := range notification.Recipients {
+		recipients = append(recipients, user.Username)
+	}
+	logger.Printf("%s: task %s %q due %s, recipients %v",
+		notification.Kind,
+		notification.Task.ID.Hex(),
+		notification.Task.Title,
+		notification.Task.DueDate.Format("2006-01-02T15:04:05Z07:00"),
+		recipients,
+	)
+	return nil
+}
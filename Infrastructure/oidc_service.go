@@ -0,0 +1,370 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const oidcSessionPurpose = "oidc_session"
+
+type OIDCConfig struct {
+	IssuerURL string
+	ClientID string
+	ClientSecret string
+	RedirectURL string
+	Scopes []string
+	RoleClaim string
+	AdminRoleValues []string
+}
+
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu sync.Mutex
+	discovery *oidcDiscovery
+	keys map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JwksURI string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N string `json:"n"`
+	E string `json:"e"`
+}
+
+func OIDCConfigFromEnv() OIDCConfig {
+	config := OIDCConfig{
+		IssuerURL: os.Getenv("OIDC_ISSUER_URL"),
+		ClientID: os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL: os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes: []string{"openid", "profile", "email"},
+		RoleClaim: os.Getenv("OIDC_ROLE_CLAIM"),
+	}
+	if values := os.Getenv("OIDC_ADMIN_ROLE_VALUES"); values != "" {
+		config.AdminRoleValues = strings.Split(values, ",")
+	}
+	return config
+}
+
+func NewOIDCProvider(config OIDCConfig, client *http.Client) *OIDCProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCProvider{
+		config: config,
+		client: client,
+		keys: map[string]*rsa.PublicKey{},
+	}
+}
+
+func (op *OIDCProvider) BeginAuth() (string, string, error) {
+	discovery, err := op.fetchDiscovery()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomURLString(24)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomURLString(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLString(48)
+	if err != nil {
+		return "", "", err
+	}
+
+	sessionToken, err := currentSigningKeys().Sign(jwt.MapClaims{
+		"purpose": oidcSessionPurpose,
+		"state": state,
+		"nonce": nonce,
+		"code_verifier": verifier,
+		"exp": time.Now().Add(time.Minute * 10).Unix(),
+	})
+	if err != nil {
+		return "", "", errors.New("unable to start sign in")
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", op.config.ClientID)
+	query.Set("redirect_uri", op.config.RedirectURL)
+	query.Set("scope", strings.Join(op.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", pkceChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), sessionToken, nil
+}
+
+func (op *OIDCProvider) CompleteAuth(sessionToken string, state string, code string) (domain.OIDCIdentity, error) {
+	session, err := parseJwtToken(sessionToken)
+	if err != nil {
+		return domain.OIDCIdentity{}, errors.New("sign in session expired")
+	}
+
+	sessionClaims, ok := session.Claims.(jwt.MapClaims)
+	if !ok || sessionClaims["purpose"] != oidcSessionPurpose {
+		return domain.OIDCIdentity{}, errors.New("sign in session expired")
+	}
+
+	if state == "" || sessionClaims["state"] != state {
+		return domain.OIDCIdentity{}, errors.New("state mismatch")
+	}
+
+	verifier, _ := sessionClaims["code_verifier"].(string)
+	nonce, _ := sessionClaims["nonce"].(string)
+
+	idToken, err := op.exchangeCode(code, verifier)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+	return op.ValidateIDToken(idToken, nonce)
+}
+
+func (op *OIDCProvider) ValidateIDToken(idToken string, nonce string) (domain.OIDCIdentity, error) {
+	discovery, err := op.fetchDiscovery()
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return op.publicKey(discovery.JwksURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(op.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid {
+		return domain.OIDCIdentity{}, errors.New("invalid id token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return domain.OIDCIdentity{}, errors.New("invalid id token")
+	}
+
+	if claims["nonce"] != nonce {
+		return domain.OIDCIdentity{}, errors.New("id token nonce mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return domain.OIDCIdentity{}, errors.New("id token has no subject")
+	}
+
+	identity := domain.OIDCIdentity{
+		Issuer: discovery.Issuer,
+		Subject: subject,
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Username, _ = claims["preferred_username"].(string)
+	identity.Role = op.mapRole(claims)
+	return identity, nil
+}
+
+func (op *OIDCProvider) mapRole(claims jwt.MapClaims) string {
+	if op.config.RoleClaim == "" {
+		return ""
+	}
+
+	var values []string
+	switch claim := claims[op.config.RoleClaim].(type) {
+	case string:
+		values = []string{claim}
+	case []interface{}:
+		for _, value := range claim {
+			if str, ok := value.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+
+	for _, value := range values {
+		for _, adminValue := range op.config.AdminRoleValues {
+			if value == strings.TrimSpace(adminValue) {
+				return "admin"
+			}
+		}
+	}
+	return "regular"
+}
+
+func (op *OIDCProvider) exchangeCode(code string, verifier string) (string, error) {
+	discovery, err := op.fetchDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", op.config.RedirectURL)
+	form.Set("client_id", op.config.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.New("unable to exchange authorization code")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if op.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(op.config.ClientID), url.QueryEscape(op.config.ClientSecret))
+	}
+
+	resp, err := op.client.Do(req)
+	if err != nil {
+		return "", errors.New("unable to exchange authorization code")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("identity provider rejected the authorization code")
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil || tokens.IDToken == "" {
+		return "", errors.New("identity provider did not return an id token")
+	}
+	return tokens.IDToken, nil
+}
+
+func (op *OIDCProvider) fetchDiscovery() (*oidcDiscovery, error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.discovery != nil {
+		return op.discovery, nil
+	}
+
+	discoveryURL := strings.TrimRight(op.config.IssuerURL, "/") + "/.well-known/openid-configuration"
+
+	var discovery oidcDiscovery
+	if err := op.getJSON(discoveryURL, &discovery); err != nil {
+		return nil, errors.New("unable to reach identity provider")
+	}
+	if discovery.Issuer != strings.TrimRight(op.config.IssuerURL, "/") {
+		return nil, errors.New("identity provider issuer mismatch")
+	}
+
+	op.discovery = &discovery
+	return op.discovery, nil
+}
+
+func (op *OIDCProvider) publicKey(jwksURI string, kid string) (*rsa.PublicKey, error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if key, ok := op.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(op.keysFetchedAt) < time.Minute {
+		return nil, errors.New("unknown signing key")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := op.getJSON(jwksURI, &jwks); err != nil {
+		return nil, errors.New("unable to fetch signing keys")
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		publicKey, err := rsaPublicKeyFromJWK(key)
+		if err != nil {
+			continue
+		}
+		keys[key.Kid] = publicKey
+	}
+	op.keys = keys
+	op.keysFetchedAt = time.Now()
+
+	key, ok := op.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	return key, nil
+}
+
+func (op *OIDCProvider) getJSON(url string, target interface{}) error {
+	resp, err := op.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func rsaPublicKeyFromJWK(key jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLString(size int) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("unable to generate random value")
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var redactedQueryParams = []string{"ticket", "access_token"}
+
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(params gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			params.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			params.StatusCode,
+			params.Latency,
+			params.ClientIP,
+			params.Method,
+			RedactQuery(params.Path),
+			params.ErrorMessage,
+		)
+	})
+}
+
+func RedactQuery(path string) string {
+	base, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base
+	}
+	for _, name := range redactedQueryParams {
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+		}
+	}
+	return base + "?" + query.Encode()
+}
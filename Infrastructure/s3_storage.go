@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type S3Config struct {
+	Endpoint string
+	Bucket string
+	Region string
+	AccessKeyID string
+	SecretAccessKey string
+}
+
+type S3BlobStorage struct {
+	config S3Config
+	client *http.Client
+	now func() time.Time
+}
+
+func S3ConfigFromEnv() S3Config {
+	config := S3Config{
+		Endpoint: os.Getenv("S3_ENDPOINT"),
+		Bucket: os.Getenv("S3_BUCKET"),
+		Region: os.Getenv("S3_REGION"),
+		AccessKeyID: os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	return config
+}
+
+func NewS3BlobStorage(config S3Config, client *http.Client) *S3BlobStorage {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &S3BlobStorage{
+		config: config,
+		client: client,
+		now: time.Now,
+	}
+}
+
+func (ss *S3BlobStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	req, err := ss.request(http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := ss.do(req)
+	if err != nil {
+		return errors.New("unable to store file")
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (ss *S3BlobStorage) Get(key string) (io.ReadCloser, error) {
+	req, err := ss.request(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ss.do(req)
+	if err != nil {
+		return nil, errors.New("file not found")
+	}
+	return resp.Body, nil
+}
+
+func (ss *S3BlobStorage) Delete(key string) error {
+	req, err := ss.request(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ss.do(req)
+	if err != nil {
+		return errors.New("unable to delete file")
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (ss *S3BlobStorage) request(method string, key string, body io.Reader) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, errors.New("invalid storage key")
+	}
+
+	objectURL := strings.TrimRight(ss.config.Endpoint, "/") + "/" + s3URIEncode(ss.config.Bucket, false) + "/" + s3URIEncode(key, true)
+	req, err := http.NewRequest(method, objectURL, body)
+	if err != nil {
+		return nil, errors.New("invalid storage endpoint")
+	}
+	return req, nil
+}
+
+func (ss *S3BlobStorage) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	SignS3Request(req, ss.config.AccessKeyID, ss.config.SecretAccessKey, ss.config.Region, unsignedPayload, ss.now())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, errors.New(resp.Status)
+	}
+	return resp, nil
+}
+
+func SignS3Request(req *http.Request, accessKeyID string, secretAccessKey string, region string, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3URIEncode(key, false)+"="+s3URIEncode(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func s3URIEncode(value string, keepSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && keepSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer = "TaskManager"
+	totpDigits = 6
+	totpPeriod = 30
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (infra *Infrastructure) GenerateTotpSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.New("unable to generate secret")
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+func (infra *Infrastructure) TotpProvisioningURI(secret string, accountName string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(totpIssuer + ":" + accountName)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func (infra *Infrastructure) ValidateTotpCode(secret string, code string) bool {
+	return ValidateTotpCodeAt(secret, code, time.Now())
+}
+
+func (infra *Infrastructure) MatchTotpCode(secret string, code string) (int64, bool) {
+	return MatchTotpCodeAt(secret, code, time.Now())
+}
+
+func (infra *Infrastructure) GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return []string{}, errors.New("unable to generate recovery codes")
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		codes = append(codes, code[:8]+"-"+code[8:])
+	}
+	return codes, nil
+}
+
+func GenerateTotpCode(secret string, at time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", errors.New("invalid totp secret")
+	}
+	return hotp(key, uint64(at.Unix()/totpPeriod)), nil
+}
+
+func ValidateTotpCodeAt(secret string, code string, at time.Time) bool {
+	_, ok := MatchTotpCodeAt(secret, code, at)
+	return ok
+}
+
+func MatchTotpCodeAt(secret string, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	counter := at.Unix() / totpPeriod
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		expected := hotp(key, uint64(counter+offset))
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return counter + offset, true
+		}
+	}
+	return 0, false
+}
+
+func hotp(key []byte, counter uint64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo)
+}
@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const webhookResponseLimit = 1 << 16
+
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func (infra *Infrastructure) GenerateWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("unable to generate webhook secret")
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
+
+func (infra *Infrastructure) SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: time.Second * 5}
+		if os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") != "true" {
+			dialer.Control = rejectPrivateWebhookTarget
+		}
+		client = &http.Client{
+			Timeout: time.Second * 10,
+			Transport: &http.Transport{
+				DialContext: dialer.DialContext,
+				TLSHandshakeTimeout: time.Second * 5,
+				MaxIdleConns: 100,
+				IdleConnTimeout: time.Second * 90,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &WebhookSender{client: client}
+}
+
+func (ws *WebhookSender) Send(url string, headers map[string]string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, errors.New("invalid webhook url")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseLimit))
+
+	return resp.StatusCode, nil
+}
+
+func rejectPrivateWebhookTarget(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("webhook target %s is not a public address", host)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APITokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAPITokenRepository(collection *mongo.Collection) *APITokenRepository {
+	return &APITokenRepository{
+		collection: collection,
+	}
+}
+
+func (ar *APITokenRepository) Create(token *domain.APIToken) (domain.APIToken, error) {
+	token.ID = primitive.NewObjectID()
+
+	_, err := ar.collection.InsertOne(context.TODO(), token)
+	if err != nil {
+		return domain.APIToken{}, errors.New("cannot insert token to database")
+	}
+	return *token, nil
+}
+
+func (ar *APITokenRepository) FetchByUser(userIDStr string) ([]domain.APIToken, error) {
+	tokens := []domain.APIToken{}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return []domain.APIToken{}, errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cur, err := ar.collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return []domain.APIToken{}, errors.New("cannot retrieve tokens")
+	}
+
+	err = cur.All(context.TODO(), &tokens)
+	if err != nil {
+		return []domain.APIToken{}, errors.New("cannot retrieve tokens")
+	}
+
+	return tokens, nil
+}
+
+func (ar *APITokenRepository) FetchByHash(tokenHash string) (domain.APIToken, error) {
+	var token domain.APIToken
+
+	filter := bson.D{{Key: "token_hash", Value: tokenHash}}
+
+	err := ar.collection.FindOne(context.TODO(), filter).Decode(&token)
+	if err != nil {
+		return domain.APIToken{}, errors.New("token not found")
+	}
+	return token, nil
+}
+
+func (ar *APITokenRepository) UpdateLastUsed(idStr string, lastUsedAt time.Time) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "last_used_at", Value: lastUsedAt},
+	}}}
+
+	_, err = ar.collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return errors.New("cannot update token")
+	}
+	return nil
+}
+
+func (ar *APITokenRepository) Remove(userIDStr string, idStr string) error {
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: userID}}
+
+	result, err := ar.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CalendarFeedRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCalendarFeedRepository(collection *mongo.Collection) *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		collection: collection,
+	}
+}
+
+func (cr *CalendarFeedRepository) Create(feed *domain.CalendarFeed) (domain.CalendarFeed, error) {
+	feed.ID = primitive.NewObjectID()
+
+	_, err := cr.collection.InsertOne(context.TODO(), feed)
+	if err != nil {
+		return domain.CalendarFeed{}, errors.New("cannot insert feed to database")
+	}
+	return *feed, nil
+}
+
+func (cr *CalendarFeedRepository) FetchByUser(tenantIDStr string, userIDStr string) ([]domain.CalendarFeed, error) {
+	feeds := []domain.CalendarFeed{}
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.CalendarFeed{}, err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return []domain.CalendarFeed{}, errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "organization_id", Value: tenantID}, {Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cur, err := cr.collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return []domain.CalendarFeed{}, errors.New("cannot retrieve feeds")
+	}
+
+	err = cur.All(context.TODO(), &feeds)
+	if err != nil {
+		return []domain.CalendarFeed{}, errors.New("cannot retrieve feeds")
+	}
+
+	return feeds, nil
+}
+
+func (cr *CalendarFeedRepository) FetchByHash(tokenHash string) (domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+
+	filter := bson.D{{Key: "token_hash", Value: tokenHash}}
+
+	err := cr.collection.FindOne(context.TODO(), filter).Decode(&feed)
+	if err != nil {
+		return domain.CalendarFeed{}, errors.New("feed not found")
+	}
+	return feed, nil
+}
+
+func (cr *CalendarFeedRepository) UpdateLastUsed(idStr string, lastUsedAt time.Time) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "last_used_at", Value: lastUsedAt},
+	}}}
+
+	_, err = cr.collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return errors.New("cannot update feed")
+	}
+	return nil
+}
+
+func (cr *CalendarFeedRepository) Remove(tenantIDStr string, userIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	filter = append(filter, bson.E{Key: "user_id", Value: userID})
+
+	result, err := cr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("feed not found")
+	}
+	return nil
+}
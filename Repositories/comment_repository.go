@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(collection *mongo.Collection) *CommentRepository {
+	return &CommentRepository{
+		collection: collection,
+	}
+}
+
+func (cr *CommentRepository) Create(tenantIDStr string, comment *domain.Comment) (domain.Comment, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	comment.ID = primitive.NewObjectID()
+	comment.OrganizationID = tenantID
+	if comment.Mentions == nil {
+		comment.Mentions = []primitive.ObjectID{}
+	}
+
+	_, err = cr.collection.InsertOne(context.TODO(), comment)
+	if err != nil {
+		return domain.Comment{}, errors.New("cannot insert comment to database")
+	}
+	return *comment, nil
+}
+
+func (cr *CommentRepository) FetchByTask(tenantIDStr string, taskIDStr string) ([]domain.Comment, error) {
+	comments := []domain.Comment{}
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Comment{}, err
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(taskIDStr)
+	if err != nil {
+		return []domain.Comment{}, errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "task_id", Value: taskID},
+	}
+
+	cur, err := cr.collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return []domain.Comment{}, errors.New("cannot retrieve comments")
+	}
+
+	err = cur.All(context.TODO(), &comments)
+	if err != nil {
+		return []domain.Comment{}, errors.New("cannot retrieve comments")
+	}
+	return comments, nil
+}
+
+func (cr *CommentRepository) Fetch(tenantIDStr string, idStr string) (domain.Comment, error) {
+	var comment domain.Comment
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	err = cr.collection.FindOne(context.TODO(), filter).Decode(&comment)
+	if err != nil {
+		return domain.Comment{}, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+func (cr *CommentRepository) Update(tenantIDStr string, idStr string, body string, mentions []primitive.ObjectID) (domain.Comment, error) {
+	var comment domain.Comment
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	if mentions == nil {
+		mentions = []primitive.ObjectID{}
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "body", Value: body},
+		{Key: "mentions", Value: mentions},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	err = cr.collection.FindOneAndUpdate(context.TODO(), filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&comment)
+	if err != nil {
+		return domain.Comment{}, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+func (cr *CommentRepository) Remove(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := cr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+func (cr *CommentRepository) RemoveByTask(tenantIDStr string, taskIDStr string) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(taskIDStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "task_id", Value: taskID},
+	}
+
+	_, err = cr.collection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return errors.New("cannot remove comments")
+	}
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -12,6 +15,19 @@ import (
 var (
 	TaskCollection *mongo.Collection
 	UserCollection *mongo.Collection
+	APITokenCollection *mongo.Collection
+	ProjectCollection *mongo.Collection
+	OrganizationCollection *mongo.Collection
+	CommentCollection *mongo.Collection
+	LabelCollection *mongo.Collection
+	LockCollection *mongo.Collection
+	WebhookCollection *mongo.Collection
+	WebhookDeliveryCollection *mongo.Collection
+	OutboxCollection *mongo.Collection
+	TaskViewCollection *mongo.Collection
+	CalendarFeedCollection *mongo.Collection
+	ChangeStreamsSupported bool
+	TransactionsSupported bool
 )
 
 func ConnectToMongoDB() {
@@ -28,4 +44,307 @@ func ConnectToMongoDB() {
 	db := mongoClient.Database("task_manager")
 	TaskCollection = db.Collection("tasks")
 	UserCollection = db.Collection("users")
-}
\ No newline at end of file
+	APITokenCollection = db.Collection("api_tokens")
+	ProjectCollection = db.Collection("projects")
+	OrganizationCollection = db.Collection("organizations")
+	CommentCollection = db.Collection("comments")
+	LabelCollection = db.Collection("labels")
+	LockCollection = db.Collection("locks")
+	WebhookCollection = db.Collection("webhooks")
+	WebhookDeliveryCollection = db.Collection("webhook_deliveries")
+	OutboxCollection = db.Collection("outbox")
+	TaskViewCollection = db.Collection("task_views")
+	CalendarFeedCollection = db.Collection("calendar_feeds")
+
+	createIndexes()
+	detectChangeStreams(db)
+	backfillDefaultOrganization()
+	backfillTaskStatusTimes()
+}
+
+func backfillTaskStatusTimes() {
+	_, err := TaskCollection.UpdateMany(context.TODO(),
+		bson.D{{Key: "status_changed_at", Value: bson.D{{Key: "$exists", Value: false}}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "status_changed_at", Value: "$updated_at"}}}}},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = TaskCollection.UpdateMany(context.TODO(),
+		bson.D{
+			{Key: "status", Value: "completed"},
+			{Key: "completed_at", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "completed_at", Value: "$updated_at"}}}}},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func backfillDefaultOrganization() {
+	legacy := bson.D{{Key: "organization_id", Value: bson.D{{Key: "$exists", Value: false}}}}
+	withoutMemberships := bson.D{{Key: "memberships.0", Value: bson.D{{Key: "$exists", Value: false}}}}
+	collections := []*mongo.Collection{TaskCollection, ProjectCollection, LabelCollection, CommentCollection}
+
+	pending := false
+	for _, collection := range collections {
+		count, err := collection.CountDocuments(context.TODO(), legacy, options.Count().SetLimit(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		pending = pending || count > 0
+	}
+	if !pending {
+		organizations, err := OrganizationCollection.CountDocuments(context.TODO(), bson.D{}, options.Count().SetLimit(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		users, err := UserCollection.CountDocuments(context.TODO(), withoutMemberships, options.Count().SetLimit(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		pending = organizations == 0 && users > 0
+	}
+	if !pending {
+		return
+	}
+
+	now := time.Now()
+	marker := bson.D{{Key: "legacy_default", Value: true}}
+	result, err := OrganizationCollection.UpdateOne(context.TODO(), marker, bson.D{
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "name", Value: "Default"},
+			{Key: "created_at", Value: now},
+			{Key: "updated_at", Value: now},
+		}},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var organization struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := OrganizationCollection.FindOne(context.TODO(), marker).Decode(&organization); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, collection := range collections {
+		_, err := collection.UpdateMany(context.TODO(), legacy, bson.D{{Key: "$set", Value: bson.D{{Key: "organization_id", Value: organization.ID}}}})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if result.UpsertedCount == 1 {
+		roles := []struct {
+			filter bson.E
+			role string
+		}{
+			{bson.E{Key: "role", Value: "admin"}, "admin"},
+			{bson.E{Key: "role", Value: bson.D{{Key: "$ne", Value: "admin"}}}, "member"},
+		}
+		for _, r := range roles {
+			membership := bson.A{bson.D{{Key: "organization_id", Value: organization.ID}, {Key: "role", Value: r.role}}}
+			_, err := UserCollection.UpdateMany(context.TODO(), append(withoutMemberships, r.filter), bson.D{{Key: "$set", Value: bson.D{{Key: "memberships", Value: membership}}}})
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	log.Println("assigned data created before organizations to the Default organization", organization.ID.Hex())
+}
+
+func detectChangeStreams(db *mongo.Database) {
+	var hello bson.M
+	if err := db.RunCommand(context.TODO(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		log.Println("cannot detect change stream support:", err)
+		return
+	}
+	_, replicaSet := hello["setName"]
+	ChangeStreamsSupported = replicaSet || hello["msg"] == "isdbgrid"
+	TransactionsSupported = ChangeStreamsSupported
+	if !ChangeStreamsSupported {
+		log.Println("warning: MongoDB is not a replica set, events are written to the outbox outside the transaction and can be lost if the server stops between the two writes")
+		return
+	}
+
+	err := db.RunCommand(context.TODO(), bson.D{
+		{Key: "collMod", Value: "tasks"},
+		{Key: "changeStreamPreAndPostImages", Value: bson.D{{Key: "enabled", Value: true}}},
+	}).Err()
+	if err != nil {
+		log.Println("task deletions will not be streamed, cannot enable change stream pre-images:", err)
+	}
+}
+
+func createIndexes() {
+	_, err := APITokenCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = UserCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{
+			{Key: "external_identities.issuer", Value: 1},
+			{Key: "external_identities.subject", Value: 1},
+		}},
+		{Keys: bson.D{{Key: "memberships.organization_id", Value: 1}}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = ProjectCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "members.user_id", Value: 1},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = TaskCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "project_id", Value: 1},
+		}},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "parent_id", Value: 1},
+		}},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "labels", Value: 1},
+		}},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "series_id", Value: 1},
+		}},
+		{
+			Keys: bson.D{{Key: "due_date", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.D{
+				{Key: "recurrence", Value: bson.D{{Key: "$exists", Value: true}}},
+			}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "due_date", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "organization_id", Value: 1},
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: options.Index().SetName("task_text").SetWeights(bson.D{
+				{Key: "title", Value: 5},
+				{Key: "description", Value: 1},
+			}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "organization_id", Value: 1},
+				{Key: "external_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{
+				{Key: "external_id", Value: bson.D{{Key: "$type", Value: "string"}}},
+			}),
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = CommentCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "task_id", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = LabelCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = WebhookCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "organization_id", Value: 1}}},
+		{Keys: bson.D{{Key: "events", Value: 1}}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = WebhookDeliveryCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "next_attempt_at", Value: 1},
+		}},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "webhook_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = OutboxCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "next_attempt_at", Value: 1},
+		}},
+		{
+			Keys: bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(60 * 60 * 24 * 7),
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = TaskViewCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "owner_id", Value: 1},
+		}},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "visibility", Value: 1},
+		}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = CalendarFeedCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{
+			{Key: "organization_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LabelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLabelRepository(collection *mongo.Collection) *LabelRepository {
+	return &LabelRepository{
+		collection: collection,
+	}
+}
+
+func (lr *LabelRepository) Create(tenantIDStr string, label *domain.Label) (domain.Label, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.Label{}, err
+	}
+
+	label.ID = primitive.NewObjectID()
+	label.OrganizationID = tenantID
+
+	_, err = lr.collection.InsertOne(context.TODO(), label)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Label{}, errors.New("label with this name already exists")
+	}
+	if err != nil {
+		return domain.Label{}, errors.New("cannot insert label to database")
+	}
+	return *label, nil
+}
+
+func (lr *LabelRepository) FetchAll(tenantIDStr string) ([]domain.Label, error) {
+	labels := []domain.Label{}
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Label{}, err
+	}
+
+	filter := bson.D{{Key: "organization_id", Value: tenantID}}
+
+	cur, err := lr.collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return []domain.Label{}, errors.New("cannot retrieve labels")
+	}
+
+	err = cur.All(context.TODO(), &labels)
+	if err != nil {
+		return []domain.Label{}, errors.New("cannot retrieve labels")
+	}
+	return labels, nil
+}
+
+func (lr *LabelRepository) Fetch(tenantIDStr string, idStr string) (domain.Label, error) {
+	var label domain.Label
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Label{}, err
+	}
+
+	err = lr.collection.FindOne(context.TODO(), filter).Decode(&label)
+	if err != nil {
+		return domain.Label{}, errors.New("label not found")
+	}
+	return label, nil
+}
+
+func (lr *LabelRepository) Update(tenantIDStr string, idStr string, label domain.Label) (domain.Label, error) {
+	var updatedLabel domain.Label
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Label{}, err
+	}
+
+	fields := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if label.Name != "" {
+		fields = append(fields, bson.E{Key: "name", Value: label.Name})
+	}
+	if label.Color != "" {
+		fields = append(fields, bson.E{Key: "color", Value: label.Color})
+	}
+	update := bson.D{{Key: "$set", Value: fields}}
+
+	err = lr.collection.FindOneAndUpdate(context.TODO(), filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updatedLabel)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Label{}, errors.New("label with this name already exists")
+	}
+	if err != nil {
+		return domain.Label{}, errors.New("label not found")
+	}
+	return updatedLabel, nil
+}
+
+func (lr *LabelRepository) Remove(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := lr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("label not found")
+	}
+	return nil
+}
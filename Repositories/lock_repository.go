@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LockRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLockRepository(collection *mongo.Collection) *LockRepository {
+	return &LockRepository{
+		collection: collection,
+	}
+}
+
+func (lr *LockRepository) Acquire(name string, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: name},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "owner", Value: owner}},
+			bson.D{{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "owner", Value: owner},
+		{Key: "expires_at", Value: now.Add(ttl)},
+	}}}
+
+	_, err := lr.collection.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.New("cannot acquire lock")
+	}
+	return true, nil
+}
+
+func (lr *LockRepository) Release(name string, owner string) error {
+	filter := bson.D{
+		{Key: "_id", Value: name},
+		{Key: "owner", Value: owner},
+	}
+
+	_, err := lr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return errors.New("cannot release lock")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrganizationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationRepository(collection *mongo.Collection) *OrganizationRepository {
+	return &OrganizationRepository{
+		collection: collection,
+	}
+}
+
+func (or *OrganizationRepository) Create(organization *domain.Organization) (domain.Organization, error) {
+	organization.ID = primitive.NewObjectID()
+
+	_, err := or.collection.InsertOne(context.TODO(), organization)
+	if err != nil {
+		return domain.Organization{}, errors.New("cannot insert organization to database")
+	}
+	return *organization, nil
+}
+
+func (or *OrganizationRepository) FetchAll() ([]domain.Organization, error) {
+	return or.find(bson.D{})
+}
+
+func (or *OrganizationRepository) FetchByIDs(ids []primitive.ObjectID) ([]domain.Organization, error) {
+	if ids == nil {
+		ids = []primitive.ObjectID{}
+	}
+	return or.find(bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+}
+
+func (or *OrganizationRepository) Fetch(idStr string) (domain.Organization, error) {
+	var organization domain.Organization
+
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return domain.Organization{}, errors.New("invalid id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: id}}
+
+	err = or.collection.FindOne(context.TODO(), filter).Decode(&organization)
+	if err != nil {
+		return domain.Organization{}, errors.New("organization not found")
+	}
+	return organization, nil
+}
+
+func (or *OrganizationRepository) Remove(idStr string) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	_, err = or.collection.DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return errors.New("cannot remove organization")
+	}
+	return nil
+}
+
+func (or *OrganizationRepository) find(filter bson.D) ([]domain.Organization, error) {
+	organizations := []domain.Organization{}
+
+	cur, err := or.collection.Find(context.TODO(), filter)
+	if err != nil {
+		return []domain.Organization{}, errors.New("cannot retrieve organizations")
+	}
+
+	err = cur.All(context.TODO(), &organizations)
+	if err != nil {
+		return []domain.Organization{}, errors.New("cannot retrieve organizations")
+	}
+	return organizations, nil
+}
+
+func parseTenantID(tenantIDStr string) (primitive.ObjectID, error) {
+	tenantID, err := primitive.ObjectIDFromHex(tenantIDStr)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("missing or invalid organization")
+	}
+	return tenantID, nil
+}
+
+func tenantDocumentFilter(tenantIDStr string, idStr string) (bson.D, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "organization_id", Value: tenantID},
+	}, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	collection *mongo.Collection
+	ctx context.Context
+}
+
+func NewOutboxRepository(collection *mongo.Collection) *OutboxRepository {
+	return &OutboxRepository{
+		collection: collection,
+		ctx: context.TODO(),
+	}
+}
+
+func withOutbox(ctx context.Context, client *mongo.Client, fn func(ctx context.Context, outbox *OutboxRepository) error) error {
+	if _, ok := ctx.(mongo.SessionContext); ok || !TransactionsSupported {
+		return fn(ctx, &OutboxRepository{collection: OutboxCollection, ctx: ctx})
+	}
+
+	session, err := client.StartSession()
+	if err != nil {
+		return errors.New("cannot start transaction")
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx, &OutboxRepository{collection: OutboxCollection, ctx: sessionCtx})
+	})
+	return err
+}
+
+func (or *OutboxRepository) Append(event *domain.Event) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if event.Handled == nil {
+		event.Handled = []string{}
+	}
+
+	_, err := or.collection.InsertOne(or.ctx, event)
+	if err != nil {
+		return errors.New("cannot insert event to outbox")
+	}
+	return nil
+}
+
+func (or *OutboxRepository) ClaimPending(now time.Time, lease time.Duration) (domain.Event, bool, error) {
+	var event domain.Event
+
+	filter := bson.D{
+		{Key: "status", Value: "pending"},
+		{Key: "next_attempt_at", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "next_attempt_at", Value: now.Add(lease)}}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	err := or.collection.FindOneAndUpdate(or.ctx, filter, update, opts).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Event{}, false, nil
+	}
+	if err != nil {
+		return domain.Event{}, false, errors.New("cannot claim outbox event")
+	}
+	return event, true, nil
+}
+
+func (or *OutboxRepository) MarkHandled(idStr string, subscriber string) error {
+	return or.update(idStr, bson.D{{Key: "$addToSet", Value: bson.D{{Key: "handled", Value: subscriber}}}})
+}
+
+func (or *OutboxRepository) Complete(idStr string, publishedAt time.Time) error {
+	return or.update(idStr, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: "published"},
+		{Key: "published_at", Value: publishedAt},
+	}}})
+}
+
+func (or *OutboxRepository) Reschedule(idStr string, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return or.update(idStr, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: status},
+		{Key: "attempts", Value: attempts},
+		{Key: "next_attempt_at", Value: nextAttemptAt},
+		{Key: "last_error", Value: lastError},
+	}}})
+}
+
+func (or *OutboxRepository) update(idStr string, update bson.D) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	_, err = or.collection.UpdateOne(or.ctx, bson.D{{Key: "_id", Value: id}}, update)
+	if err != nil {
+		return errors.New("cannot update outbox event")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ProjectRepository struct {
+	collection *mongo.Collection
+}
+
+func NewProjectRepository(collection *mongo.Collection) *ProjectRepository {
+	return &ProjectRepository{
+		collection: collection,
+	}
+}
+
+func (pr *ProjectRepository) Create(tenantIDStr string, project *domain.Project) (domain.Project, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	project.ID = primitive.NewObjectID()
+	project.OrganizationID = tenantID
+
+	_, err = pr.collection.InsertOne(context.TODO(), project)
+	if err != nil {
+		return domain.Project{}, errors.New("cannot insert project to database")
+	}
+	return *project, nil
+}
+
+func (pr *ProjectRepository) FetchAll(tenantIDStr string) ([]domain.Project, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Project{}, err
+	}
+
+	return pr.find(bson.D{{Key: "organization_id", Value: tenantID}})
+}
+
+func (pr *ProjectRepository) FetchByMember(tenantIDStr string, userIDStr string) ([]domain.Project, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Project{}, err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return []domain.Project{}, errors.New("invalid id")
+	}
+
+	return pr.find(bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "members.user_id", Value: userID},
+	})
+}
+
+func (pr *ProjectRepository) Fetch(tenantIDStr string, idStr string) (domain.Project, error) {
+	var project domain.Project
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	err = pr.collection.FindOne(context.TODO(), filter).Decode(&project)
+	if err != nil {
+		return domain.Project{}, errors.New("project not found")
+	}
+	return project, nil
+}
+
+func (pr *ProjectRepository) Update(tenantIDStr string, idStr string, project domain.Project) (domain.Project, error) {
+	fields := bson.D{}
+	if project.Name != "" {
+		fields = append(fields, bson.E{Key: "name", Value: project.Name})
+	}
+	if project.Description != "" {
+		fields = append(fields, bson.E{Key: "description", Value: project.Description})
+	}
+
+	return pr.set(tenantIDStr, idStr, fields)
+}
+
+func (pr *ProjectRepository) UpdateMembers(tenantIDStr string, idStr string, members []domain.ProjectMember) (domain.Project, error) {
+	return pr.set(tenantIDStr, idStr, bson.D{{Key: "members", Value: members}})
+}
+
+func (pr *ProjectRepository) Remove(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := pr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("project not found")
+	}
+	return nil
+}
+
+func (pr *ProjectRepository) find(filter bson.D) ([]domain.Project, error) {
+	projects := []domain.Project{}
+
+	cur, err := pr.collection.Find(context.TODO(), filter)
+	if err != nil {
+		return []domain.Project{}, errors.New("cannot retrieve projects")
+	}
+
+	err = cur.All(context.TODO(), &projects)
+	if err != nil {
+		return []domain.Project{}, errors.New("cannot retrieve projects")
+	}
+	return projects, nil
+}
+
+func (pr *ProjectRepository) set(tenantIDStr string, idStr string, fields bson.D) (domain.Project, error) {
+	var project domain.Project
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
+	update := bson.D{{Key: "$set", Value: fields}}
+
+	result, err := pr.collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return domain.Project{}, errors.New(err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return domain.Project{}, errors.New("project not found")
+	}
+
+	err = pr.collection.FindOne(context.TODO(), filter).Decode(&project)
+	if err != nil {
+		return domain.Project{}, errors.New("project not found")
+	}
+	return project, nil
+}
\ No newline at end of file
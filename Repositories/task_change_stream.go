@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskChangeStream struct {
+	collection *mongo.Collection
+}
+
+type taskChangeDocument struct {
+	OperationType string `bson:"operationType"`
+	FullDocument *domain.Task `bson:"fullDocument"`
+	FullDocumentBeforeChange *domain.Task `bson:"fullDocumentBeforeChange"`
+}
+
+func NewTaskChangeStream(collection *mongo.Collection) *TaskChangeStream {
+	return &TaskChangeStream{
+		collection: collection,
+	}
+}
+
+func (ts *TaskChangeStream) Subscribe(tenantID string, lastEventID string) (<-chan domain.TaskChange, func(), error) {
+	tenant, err := primitive.ObjectIDFromHex(tenantID)
+	if err != nil {
+		return nil, nil, errors.New("missing or invalid organization")
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.D{
+		{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace", "delete"}}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "fullDocument.organization_id", Value: tenant}},
+			bson.D{{Key: "fullDocumentBeforeChange.organization_id", Value: tenant}},
+		}},
+	}}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := ts.watch(ctx, pipeline, lastEventID)
+	if err != nil && lastEventID != "" {
+		log.Println("cannot resume task change stream, starting from now:", err)
+		stream, err = ts.watch(ctx, pipeline, "")
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, errors.New("cannot watch task changes")
+	}
+
+	changes := make(chan domain.TaskChange)
+	go func() {
+		defer close(changes)
+		defer stream.Close(context.Background())
+
+		for stream.Next(ctx) {
+			var document taskChangeDocument
+			if err := stream.Decode(&document); err != nil {
+				log.Println("cannot decode task change:", err)
+				continue
+			}
+
+			change, ok := taskChangeFromDocument(document)
+			if !ok {
+				continue
+			}
+			change.ID = stream.ResumeToken().Lookup("_data").StringValue()
+
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			log.Println("task change stream closed:", err)
+		}
+	}()
+	return changes, cancel, nil
+}
+
+func (ts *TaskChangeStream) watch(ctx context.Context, pipeline mongo.Pipeline, lastEventID string) (*mongo.ChangeStream, error) {
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if lastEventID != "" {
+		opts.SetStartAfter(bson.D{{Key: "_data", Value: lastEventID}})
+	}
+	return ts.collection.Watch(ctx, pipeline, opts)
+}
+
+func taskChangeFromDocument(document taskChangeDocument) (domain.TaskChange, bool) {
+	switch document.OperationType {
+	case "insert":
+		if document.FullDocument != nil {
+			return domain.TaskChange{Type: domain.EventTaskCreated, Task: *document.FullDocument}, true
+		}
+	case "update", "replace":
+		if document.FullDocument != nil {
+			return domain.TaskChange{Type: domain.EventTaskUpdated, Task: *document.FullDocument}, true
+		}
+	case "delete":
+		if document.FullDocumentBeforeChange != nil {
+			return domain.TaskChange{Type: domain.EventTaskDeleted, Task: *document.FullDocumentBeforeChange}, true
+		}
+	}
+	return domain.TaskChange{}, false
+}
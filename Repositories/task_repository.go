@@ -3,63 +3,321 @@ package repositories
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TaskRepository struct {
 	collection *mongo.Collection
+	ctx context.Context
 }
 
 func NewTaskRepository(collection *mongo.Collection) *TaskRepository {
 	return &TaskRepository{
 		collection: collection,
+		ctx: context.TODO(),
 	}
 }
 
-func (tr *TaskRepository) Create(task *domain.Task) (domain.Task, error) {
+func (tr *TaskRepository) WithTransaction(fn func(tasks usecases.ITaskRepo) error) error {
+	if !TransactionsSupported {
+		return errors.New("atomic bulk operations need a MongoDB replica set, use best_effort mode instead")
+	}
+
+	session, err := tr.collection.Database().Client().StartSession()
+	if err != nil {
+		return errors.New("cannot start transaction")
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(&TaskRepository{collection: tr.collection, ctx: sessionCtx})
+	})
+	return err
+}
+
+func (tr *TaskRepository) WithOutbox(fn func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error) error {
+	return withOutbox(tr.ctx, tr.collection.Database().Client(), func(ctx context.Context, outbox *OutboxRepository) error {
+		return fn(&TaskRepository{collection: tr.collection, ctx: ctx}, outbox)
+	})
+}
+
+func (tr *TaskRepository) Create(tenantIDStr string, task *domain.Task) (domain.Task, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
 	task.ID = primitive.NewObjectID()
+	task.OrganizationID = tenantID
+	if task.Checklist == nil {
+		task.Checklist = []domain.ChecklistItem{}
+	}
+	task.BlockedBy = []primitive.ObjectID{}
+	task.Attachments = []domain.Attachment{}
+	if task.Labels == nil {
+		task.Labels = []primitive.ObjectID{}
+	}
 
-	_, err :=tr.collection.InsertOne(context.TODO(), task)
+	_, err = tr.collection.InsertOne(tr.ctx, task)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Task{}, errors.New("a task with this external id already exists")
+	}
 	if err != nil {
 		return domain.Task{}, errors.New("cannot insert task to database")
 	}
 	return *task, nil
 }
 
-func (tr *TaskRepository) FetchAll() ([]domain.Task, error) {
+func (tr *TaskRepository) FetchAll(tenantIDStr string, filter domain.TaskFilter) ([]domain.Task, error) {
 	var tasks []domain.Task
 
-	cur, err := tr.collection.Find(context.TODO(), bson.D{{}})
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	cur, err := tr.collection.Find(tr.ctx, taskFilterDocument(tenantID, filter))
 	if err != nil {
 		return []domain.Task{}, errors.New("cannot retrieve tasks")
 	}
 
-	err = cur.All(context.TODO(), &tasks)
+	err = cur.All(tr.ctx, &tasks)
 	if err != nil {
 		return []domain.Task{}, errors.New("cannot retrieve tasks")
 	}
 
-	cur.Close(context.TODO())
+	cur.Close(tr.ctx)
 
 	return tasks, nil
 }
 
-func(tr *TaskRepository) Fetch(idStr string) (domain.Task, error) {
-	var task domain.Task
+func (tr *TaskRepository) Each(tenantIDStr string, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
 
-	id, err := primitive.ObjectIDFromHex(idStr)
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := tr.collection.Find(tr.ctx, taskFilterDocument(tenantID, filter), opts)
+	if err != nil {
+		return errors.New("cannot retrieve tasks")
+	}
+	defer cur.Close(tr.ctx)
+
+	for cur.Next(tr.ctx) {
+		var task domain.Task
+		if err := cur.Decode(&task); err != nil {
+			return errors.New("cannot retrieve tasks")
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	if cur.Err() != nil {
+		return errors.New("cannot retrieve tasks")
+	}
+	return nil
+}
+
+func (tr *TaskRepository) Search(tenantIDStr string, filter domain.TaskFilter, search domain.TaskSearch, limit int) ([]domain.TaskSearchResult, error) {
+	var hits []struct {
+		Task domain.Task `bson:",inline"`
+		Score float64 `bson:"score"`
+	}
+
+	tenantID, err := parseTenantID(tenantIDStr)
 	if err != nil {
-		return domain.Task{}, errors.New("invalid id")
+		return []domain.TaskSearchResult{}, err
 	}
 
-	filter := bson.D{{Key: "_id", Value: id}}
+	query := append(taskFilterDocument(tenantID, filter), bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: textSearchString(search)}}})
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
 
-	err = tr.collection.FindOne(context.TODO(), filter).Decode(&task)
+	cur, err := tr.collection.Find(tr.ctx, query, opts)
+	if err != nil {
+		return []domain.TaskSearchResult{}, errors.New("cannot search tasks")
+	}
+	defer cur.Close(tr.ctx)
+
+	if err := cur.All(tr.ctx, &hits); err != nil {
+		return []domain.TaskSearchResult{}, errors.New("cannot search tasks")
+	}
+
+	results := []domain.TaskSearchResult{}
+	for _, hit := range hits {
+		results = append(results, domain.TaskSearchResult{Task: hit.Task, Score: hit.Score})
+	}
+	return results, nil
+}
+
+func (tr *TaskRepository) Report(tenantIDStr string, filter domain.TaskFilter, query domain.TaskReportQuery) (domain.TaskReport, error) {
+	var facets []struct {
+		Status []struct {
+			Status string `bson:"_id"`
+			Count int `bson:"count"`
+		} `bson:"status"`
+		Overdue []struct {
+			Count int `bson:"count"`
+		} `bson:"overdue"`
+		Throughput []struct {
+			Start time.Time `bson:"_id"`
+			Completed int `bson:"completed"`
+		} `bson:"throughput"`
+		CycleTime []struct {
+			Completed int `bson:"completed"`
+			AverageHours float64 `bson:"average_hours"`
+		} `bson:"cycle_time"`
+		Users []struct {
+			UserID primitive.ObjectID `bson:"_id"`
+			Total int `bson:"total"`
+			Overdue int `bson:"overdue"`
+			Completed int `bson:"completed"`
+			AverageHours float64 `bson:"average_hours"`
+		} `bson:"users"`
+	}
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.TaskReport{}, err
+	}
+
+	open := bson.A{"pending", "in-progress"}
+	overdue := bson.D{
+		{Key: "due_date", Value: bson.D{{Key: "$gt", Value: time.Time{}}, {Key: "$lt", Value: query.Now}}},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: open}}},
+	}
+	completed := bson.D{
+		{Key: "status", Value: "completed"},
+		{Key: "completed_at", Value: bson.D{{Key: "$gte", Value: query.From}, {Key: "$lt", Value: query.To}}},
+	}
+	isOverdue := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$gt", Value: bson.A{"$due_date", time.Time{}}}},
+		bson.D{{Key: "$lt", Value: bson.A{"$due_date", query.Now}}},
+		bson.D{{Key: "$in", Value: bson.A{"$status", open}}},
+	}}}
+	isCompleted := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{"$status", "completed"}}},
+		bson.D{{Key: "$gte", Value: bson.A{"$completed_at", query.From}}},
+		bson.D{{Key: "$lt", Value: bson.A{"$completed_at", query.To}}},
+	}}}
+	cycleHours := bson.D{{Key: "$divide", Value: bson.A{
+		bson.D{{Key: "$subtract", Value: bson.A{"$completed_at", "$created_at"}}},
+		float64(time.Hour / time.Millisecond),
+	}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: taskFilterDocument(tenantID, filter)}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "status", Value: bson.A{
+				bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$status"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+			}},
+			{Key: "overdue", Value: bson.A{
+				bson.D{{Key: "$match", Value: overdue}},
+				bson.D{{Key: "$count", Value: "count"}},
+			}},
+			{Key: "throughput", Value: bson.A{
+				bson.D{{Key: "$match", Value: completed}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+						{Key: "date", Value: "$completed_at"},
+						{Key: "unit", Value: query.Bucket},
+						{Key: "startOfWeek", Value: "monday"},
+						{Key: "timezone", Value: "UTC"},
+					}}}},
+					{Key: "completed", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+			}},
+			{Key: "cycle_time", Value: bson.A{
+				bson.D{{Key: "$match", Value: completed}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: nil},
+					{Key: "completed", Value: bson.D{{Key: "$sum", Value: 1}}},
+					{Key: "average_hours", Value: bson.D{{Key: "$avg", Value: cycleHours}}},
+				}}},
+			}},
+			{Key: "users", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{{Key: "created_by", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: "$created_by"},
+					{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+					{Key: "overdue", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{isOverdue, 1, 0}}}}}},
+					{Key: "completed", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{isCompleted, 1, 0}}}}}},
+					{Key: "average_hours", Value: bson.D{{Key: "$avg", Value: bson.D{{Key: "$cond", Value: bson.A{isCompleted, cycleHours, nil}}}}}},
+				}}},
+			}},
+		}}},
+	}
+
+	cur, err := tr.collection.Aggregate(tr.ctx, pipeline)
+	if err != nil {
+		return domain.TaskReport{}, errors.New("cannot build task report")
+	}
+	defer cur.Close(tr.ctx)
+
+	if err := cur.All(tr.ctx, &facets); err != nil || len(facets) == 0 {
+		return domain.TaskReport{}, errors.New("cannot build task report")
+	}
+
+	result := facets[0]
+	report := domain.TaskReport{ByStatus: map[string]int{}, Throughput: []domain.TaskReportBucket{}, Users: []domain.TaskUserReport{}}
+	for _, status := range result.Status {
+		report.ByStatus[status.Status] = status.Count
+	}
+	if len(result.Overdue) > 0 {
+		report.Overdue = result.Overdue[0].Count
+	}
+	for _, bucket := range result.Throughput {
+		report.Throughput = append(report.Throughput, domain.TaskReportBucket{Start: bucket.Start, Completed: bucket.Completed})
+	}
+	if len(result.CycleTime) > 0 {
+		report.CycleTime = domain.TaskCycleTime{Completed: result.CycleTime[0].Completed, AverageHours: result.CycleTime[0].AverageHours}
+	}
+	for _, user := range result.Users {
+		report.Users = append(report.Users, domain.TaskUserReport{
+			UserID: user.UserID,
+			Total: user.Total,
+			Overdue: user.Overdue,
+			Completed: user.Completed,
+			AverageCycleHours: user.AverageHours,
+		})
+	}
+	return report, nil
+}
+
+func textSearchString(search domain.TaskSearch) string {
+	parts := append([]string{}, search.Terms...)
+	for _, phrase := range search.Phrases {
+		parts = append(parts, `"`+phrase+`"`)
+	}
+	for _, excluded := range search.Excluded {
+		if strings.Contains(excluded, " ") {
+			parts = append(parts, `-"`+excluded+`"`)
+		} else {
+			parts = append(parts, "-"+excluded)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func(tr *TaskRepository) Fetch(tenantIDStr string, idStr string) (domain.Task, error) {
+	var task domain.Task
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	err = tr.collection.FindOne(tr.ctx, filter).Decode(&task)
 	if err != nil {
 		return domain.Task{}, errors.New("task not found")
 	}
@@ -67,57 +325,418 @@ func(tr *TaskRepository) Fetch(idStr string) (domain.Task, error) {
 	return task, nil
 }
 
-func(tr *TaskRepository) Update(idStr string, task domain.Task) (domain.Task, error) {
+func(tr *TaskRepository) Update(tenantIDStr string, idStr string, task domain.Task) (domain.Task, error) {
 	var updatedTask domain.Task
 
-	id, err := primitive.ObjectIDFromHex(idStr)
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
 	if err != nil {
-		return domain.Task{}, errors.New("invalid id")
+		return domain.Task{}, err
 	}
 
-	filter := bson.D{{Key: "_id", Value: id}}
-
 	fields := bson.D{}
 	if task.Title != "" {
-		fields = append(fields, bson.E{Key: "title", Value: task.Title})
+		fields = append(fields, literalField("title", task.Title))
 	}
 	if task.Description != "" {
-		fields = append(fields, bson.E{Key: "description", Value: task.Description})
+		fields = append(fields, literalField("description", task.Description))
 	}
 	if !time.Time.IsZero(task.DueDate) {
-		fields = append(fields, bson.E{Key: "due_date", Value: task.DueDate})
+		fields = append(fields, literalField("due_date", task.DueDate))
 	}
 	if task.Status != "" {
-		fields = append(fields, bson.E{Key: "status", Value: task.Status})
+		now := time.Now()
+		changed := bson.D{{Key: "$ne", Value: bson.A{"$status", task.Status}}}
+		fields = append(fields,
+			literalField("status", task.Status),
+			bson.E{Key: "status_changed_at", Value: bson.D{{Key: "$cond", Value: bson.A{changed, now, "$status_changed_at"}}}},
+		)
+		if task.Status == "completed" {
+			fields = append(fields, bson.E{Key: "completed_at", Value: bson.D{{Key: "$cond", Value: bson.A{changed, now, "$completed_at"}}}})
+		} else {
+			fields = append(fields, bson.E{Key: "completed_at", Value: "$$REMOVE"})
+		}
 	}
-	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
+	if task.Priority != "" {
+		fields = append(fields, literalField("priority", task.Priority))
+	}
+	if task.Rank != nil {
+		fields = append(fields, literalField("rank", *task.Rank))
+	}
+	if !task.ProjectID.IsZero() {
+		fields = append(fields, literalField("project_id", task.ProjectID))
+	}
+	if !task.ParentID.IsZero() {
+		fields = append(fields, literalField("parent_id", task.ParentID))
+	}
+	if task.AutoComplete != nil {
+		fields = append(fields, literalField("auto_complete", *task.AutoComplete))
+	}
+	fields = append(fields, literalField("updated_at", time.Now()))
 
-	update := bson.D{{Key: "$set", Value: fields}}
+	update := mongo.Pipeline{{{Key: "$set", Value: fields}}}
+	if !time.Time.IsZero(task.DueDate) {
+		update = append(update, bson.D{{Key: "$unset", Value: bson.A{"due_soon_notified", "overdue_notified", "overdue"}}})
+	}
 
-	_, err = tr.collection.UpdateOne(context.TODO(), filter, update)
+	_, err = tr.collection.UpdateOne(tr.ctx, filter, update)
 	if err != nil {
 		return domain.Task{}, errors.New(err.Error())
 	}
 	
-	err = tr.collection.FindOne(context.TODO(), filter).Decode(&updatedTask)
+	err = tr.collection.FindOne(tr.ctx, filter).Decode(&updatedTask)
 	if err != nil {
 		return domain.Task{}, errors.New("task not found")
 	}
 	return updatedTask, nil
 }
 
-func (tr *TaskRepository) Remove(idStr string) error {
+func literalField(key string, value interface{}) bson.E {
+	return bson.E{Key: key, Value: bson.D{{Key: "$literal", Value: value}}}
+}
+
+func (tr *TaskRepository) UpdateChecklist(tenantIDStr string, idStr string, checklist []domain.ChecklistItem) (domain.Task, error) {
+	return tr.set(tenantIDStr, idStr, bson.D{{Key: "checklist", Value: checklist}})
+}
+
+func (tr *TaskRepository) UpdateDependencies(tenantIDStr string, idStr string, blockedBy []primitive.ObjectID) (domain.Task, error) {
+	return tr.set(tenantIDStr, idStr, bson.D{{Key: "blocked_by", Value: blockedBy}})
+}
+
+func (tr *TaskRepository) AddAttachment(tenantIDStr string, idStr string, attachment domain.Attachment) (domain.Task, error) {
+	return tr.modify(tenantIDStr, idStr, bson.D{
+		{Key: "$push", Value: bson.D{{Key: "attachments", Value: attachment}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	})
+}
+
+func (tr *TaskRepository) RemoveAttachment(tenantIDStr string, idStr string, attachmentIDStr string) (domain.Task, error) {
+	attachmentID, err := primitive.ObjectIDFromHex(attachmentIDStr)
+	if err != nil {
+		return domain.Task{}, errors.New("invalid attachment id")
+	}
+
+	return tr.modify(tenantIDStr, idStr, bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "attachments", Value: bson.D{{Key: "_id", Value: attachmentID}}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	})
+}
+
+func (tr *TaskRepository) AddLabel(tenantIDStr string, idStr string, labelID primitive.ObjectID) (domain.Task, error) {
+	return tr.modify(tenantIDStr, idStr, bson.D{
+		{Key: "$addToSet", Value: bson.D{{Key: "labels", Value: labelID}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	})
+}
+
+func (tr *TaskRepository) RemoveLabel(tenantIDStr string, idStr string, labelID primitive.ObjectID) (domain.Task, error) {
+	return tr.modify(tenantIDStr, idStr, bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "labels", Value: labelID}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	})
+}
+
+func (tr *TaskRepository) RemoveLabelFromAll(tenantIDStr string, labelID primitive.ObjectID) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{{Key: "organization_id", Value: tenantID}, {Key: "labels", Value: labelID}}
+	update := bson.D{{Key: "$pull", Value: bson.D{{Key: "labels", Value: labelID}}}}
+
+	_, err = tr.collection.UpdateMany(tr.ctx, filter, update)
+	if err != nil {
+		return errors.New("cannot remove label from tasks")
+	}
+	return nil
+}
+
+func (tr *TaskRepository) UpdateSeries(tenantIDStr string, filter domain.TaskFilter, task domain.Task) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
+	fields := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if task.Title != "" {
+		fields = append(fields, bson.E{Key: "title", Value: task.Title})
+	}
+	if task.Description != "" {
+		fields = append(fields, bson.E{Key: "description", Value: task.Description})
+	}
+	if task.Priority != "" {
+		fields = append(fields, bson.E{Key: "priority", Value: task.Priority})
+	}
+	if task.Rank != nil {
+		fields = append(fields, bson.E{Key: "rank", Value: *task.Rank})
+	}
+	if task.Recurrence != nil {
+		fields = append(fields, bson.E{Key: "recurrence", Value: task.Recurrence})
+	}
+	update := bson.D{{Key: "$set", Value: fields}}
+
+	_, err = tr.collection.UpdateMany(tr.ctx, taskFilterDocument(tenantID, filter), update)
+	if err != nil {
+		return errors.New("cannot update task series")
+	}
+	return nil
+}
+
+func (tr *TaskRepository) EndSeries(tenantIDStr string, filter domain.TaskFilter) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$unset", Value: bson.D{{Key: "recurrence", Value: ""}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	}
+
+	_, err = tr.collection.UpdateMany(tr.ctx, taskFilterDocument(tenantID, filter), update)
+	if err != nil {
+		return errors.New("cannot end task series")
+	}
+	return nil
+}
+
+func (tr *TaskRepository) FetchRecurringDue(before time.Time) ([]domain.Task, error) {
+	tasks := []domain.Task{}
+
+	filter := bson.D{
+		{Key: "recurrence", Value: bson.D{{Key: "$exists", Value: true}}},
+		{Key: "next_generated", Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"pending", "in-progress"}}}},
+		{Key: "due_date", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+
+	cur, err := tr.collection.Find(tr.ctx, filter)
+	if err != nil {
+		return []domain.Task{}, errors.New("cannot retrieve tasks")
+	}
+
+	err = cur.All(tr.ctx, &tasks)
+	if err != nil {
+		return []domain.Task{}, errors.New("cannot retrieve tasks")
+	}
+	return tasks, nil
+}
+
+func (tr *TaskRepository) ClaimNextOccurrence(tenantIDStr string, idStr string) (bool, error) {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return false, err
+	}
+	filter = append(filter, bson.E{Key: "next_generated", Value: bson.D{{Key: "$ne", Value: true}}})
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "next_generated", Value: true}}}}
+
+	result, err := tr.collection.UpdateOne(tr.ctx, filter, update)
+	if err != nil {
+		return false, errors.New("cannot update task")
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (tr *TaskRepository) FetchPendingReminders(kind string, from time.Time, to time.Time) ([]domain.Task, error) {
+	tasks := []domain.Task{}
+
+	field, err := reminderField(kind)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	dueDate := bson.D{{Key: "$lt", Value: to}}
+	if !from.IsZero() {
+		dueDate = append(dueDate, bson.E{Key: "$gte", Value: from})
+	}
+	filter := bson.D{
+		{Key: field, Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"pending", "in-progress"}}}},
+		{Key: "due_date", Value: dueDate},
+	}
+
+	cur, err := tr.collection.Find(tr.ctx, filter)
+	if err != nil {
+		return []domain.Task{}, errors.New("cannot retrieve tasks")
+	}
+
+	err = cur.All(tr.ctx, &tasks)
+	if err != nil {
+		return []domain.Task{}, errors.New("cannot retrieve tasks")
+	}
+	return tasks, nil
+}
+
+func (tr *TaskRepository) MarkReminded(tenantIDStr string, idStr string, kind string) error {
+	field, err := reminderField(kind)
+	if err != nil {
+		return err
+	}
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: true}}}}
+
+	_, err = tr.collection.UpdateOne(tr.ctx, filter, update)
+	if err != nil {
+		return errors.New("cannot update task")
+	}
+	return nil
+}
+
+func (tr *TaskRepository) FlagOverdue(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "overdue", Value: true},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	_, err = tr.collection.UpdateOne(tr.ctx, filter, update)
+	if err != nil {
+		return errors.New("cannot update task")
+	}
+	return nil
+}
+
+func reminderField(kind string) (string, error) {
+	switch kind {
+	case "due_soon":
+		return "due_soon_notified", nil
+	case "overdue":
+		return "overdue_notified", nil
+	}
+	return "", errors.New("invalid reminder kind")
+}
+
+func (tr *TaskRepository) set(tenantIDStr string, idStr string, fields bson.D) (domain.Task, error) {
+	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
+	return tr.modify(tenantIDStr, idStr, bson.D{{Key: "$set", Value: fields}})
+}
+
+func (tr *TaskRepository) modify(tenantIDStr string, idStr string, update bson.D) (domain.Task, error) {
+	var updatedTask domain.Task
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	err = tr.collection.FindOneAndUpdate(tr.ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updatedTask)
+	if err != nil {
+		return domain.Task{}, errors.New("task not found")
+	}
+	return updatedTask, nil
+}
+
+func (tr *TaskRepository) Remove(tenantIDStr string, idStr string) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
 		return errors.New("invalid id")
 	}
 
-	filter := bson.D{{Key: "_id", Value: id}}
-	_, err = tr.collection.DeleteOne(context.TODO(), filter)
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "organization_id", Value: tenantID}}
 
-	if err != nil {
+	result, err := tr.collection.DeleteOne(tr.ctx, filter)
+	if err != nil || result.DeletedCount == 0 {
 		return errors.New("task not found")
 	}
 
+	dependents := bson.D{{Key: "organization_id", Value: tenantID}, {Key: "blocked_by", Value: id}}
+	update := bson.D{{Key: "$pull", Value: bson.D{{Key: "blocked_by", Value: id}}}}
+
+	_, err = tr.collection.UpdateMany(tr.ctx, dependents, update)
+	if err != nil {
+		return errors.New("cannot remove dependencies on the task")
+	}
+
 	return nil
+}
+
+func taskFilterDocument(tenantID primitive.ObjectID, filter domain.TaskFilter) bson.D {
+	document := bson.D{{Key: "organization_id", Value: tenantID}}
+
+	if filter.IDs != nil {
+		document = append(document, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: filter.IDs}}})
+	}
+
+	if filter.ExternalIDs != nil {
+		document = append(document, bson.E{Key: "external_id", Value: bson.D{{Key: "$in", Value: filter.ExternalIDs}}})
+	}
+
+	if !filter.ProjectID.IsZero() {
+		document = append(document, bson.E{Key: "project_id", Value: filter.ProjectID})
+	}
+
+	if !filter.ParentID.IsZero() {
+		document = append(document, bson.E{Key: "parent_id", Value: filter.ParentID})
+	}
+
+	if !filter.SeriesID.IsZero() {
+		document = append(document, bson.E{Key: "series_id", Value: filter.SeriesID})
+	}
+
+	if filter.Statuses != nil {
+		document = append(document, bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: filter.Statuses}}})
+	}
+
+	if filter.ParentIDs != nil {
+		document = append(document, bson.E{Key: "parent_id", Value: bson.D{{Key: "$in", Value: filter.ParentIDs}}})
+	}
+
+	if len(filter.Labels) > 0 {
+		operator := "$in"
+		if filter.MatchAllLabels {
+			operator = "$all"
+		}
+		document = append(document, bson.E{Key: "labels", Value: bson.D{{Key: operator, Value: filter.Labels}}})
+	}
+
+	if filter.Restricted {
+		visibleProjectIDs := filter.VisibleProjectIDs
+		if visibleProjectIDs == nil {
+			visibleProjectIDs = []primitive.ObjectID{}
+		}
+		document = append(document, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "project_id", Value: bson.D{{Key: "$in", Value: visibleProjectIDs}}}},
+			bson.D{{Key: "project_id", Value: bson.D{{Key: "$exists", Value: false}}}},
+		}})
+	}
+
+	if filter.Expression != nil {
+		document = append(document, bson.E{Key: "$and", Value: bson.A{taskExpressionDocument(*filter.Expression)}})
+	}
+
+	return document
+}
+
+func taskExpressionDocument(expression domain.TaskExpression) bson.D {
+	switch expression.Op {
+	case "and", "or":
+		children := bson.A{}
+		for _, child := range expression.Children {
+			children = append(children, taskExpressionDocument(child))
+		}
+		return bson.D{{Key: "$" + expression.Op, Value: children}}
+	case "not":
+		return bson.D{{Key: "$nor", Value: bson.A{taskExpressionDocument(expression.Children[0])}}}
+	case "eq":
+		return bson.D{{Key: expression.Field, Value: expression.Value}}
+	case "contains":
+		pattern := regexp.QuoteMeta(expression.Value.(string))
+		return bson.D{{Key: expression.Field, Value: primitive.Regex{Pattern: pattern, Options: "i"}}}
+	}
+	return bson.D{{Key: expression.Field, Value: bson.D{{Key: "$" + expression.Op, Value: expression.Value}}}}
 }
\ No newline at end of file
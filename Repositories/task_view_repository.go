@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskViewRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskViewRepository(collection *mongo.Collection) *TaskViewRepository {
+	return &TaskViewRepository{
+		collection: collection,
+	}
+}
+
+func (vr *TaskViewRepository) Create(tenantIDStr string, view *domain.TaskView) (domain.TaskView, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+
+	view.ID = primitive.NewObjectID()
+	view.OrganizationID = tenantID
+
+	_, err = vr.collection.InsertOne(context.TODO(), view)
+	if err != nil {
+		return domain.TaskView{}, errors.New("cannot insert view to database")
+	}
+	return *view, nil
+}
+
+func (vr *TaskViewRepository) FetchVisible(tenantIDStr string, userIDStr string) ([]domain.TaskView, error) {
+	views := []domain.TaskView{}
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.TaskView{}, err
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return []domain.TaskView{}, errors.New("invalid user id")
+	}
+
+	filter := bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "owner_id", Value: userID}},
+			bson.D{{Key: "visibility", Value: "global"}},
+		}},
+	}
+	cur, err := vr.collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return []domain.TaskView{}, errors.New("cannot retrieve views")
+	}
+
+	err = cur.All(context.TODO(), &views)
+	if err != nil {
+		return []domain.TaskView{}, errors.New("cannot retrieve views")
+	}
+	return views, nil
+}
+
+func (vr *TaskViewRepository) Fetch(tenantIDStr string, idStr string) (domain.TaskView, error) {
+	var view domain.TaskView
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+
+	err = vr.collection.FindOne(context.TODO(), filter).Decode(&view)
+	if err != nil {
+		return domain.TaskView{}, errors.New("view not found")
+	}
+	return view, nil
+}
+
+func (vr *TaskViewRepository) Update(tenantIDStr string, idStr string, update domain.TaskViewUpdate) (domain.TaskView, error) {
+	var updatedView domain.TaskView
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+
+	fields := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if update.Name != nil {
+		fields = append(fields, bson.E{Key: "name", Value: *update.Name})
+	}
+	if update.Visibility != nil {
+		fields = append(fields, bson.E{Key: "visibility", Value: *update.Visibility})
+	}
+	if update.Query != nil {
+		fields = append(fields, bson.E{Key: "query", Value: *update.Query})
+	}
+	if update.Sort != nil {
+		fields = append(fields, bson.E{Key: "sort", Value: update.Sort})
+	}
+	if update.Columns != nil {
+		fields = append(fields, bson.E{Key: "columns", Value: update.Columns})
+	}
+
+	err = vr.collection.FindOneAndUpdate(context.TODO(), filter, bson.D{{Key: "$set", Value: fields}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updatedView)
+	if err != nil {
+		return domain.TaskView{}, errors.New("view not found")
+	}
+	return updatedView, nil
+}
+
+func (vr *TaskViewRepository) Remove(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := vr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("view not found")
+	}
+	return nil
+}
+
+func (vr *TaskViewRepository) UpdatePositions(tenantIDStr string, userIDStr string, ids []primitive.ObjectID) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+	if _, err := primitive.ObjectIDFromHex(userIDStr); err != nil {
+		return errors.New("invalid user id")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	models := []mongo.WriteModel{}
+	for position, id := range ids {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: id}, {Key: "organization_id", Value: tenantID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "positions." + userIDStr, Value: position}}}}))
+	}
+
+	_, err = vr.collection.BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return errors.New("cannot reorder views")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,18 +15,26 @@ import (
 
 type UserRepository struct {
 	collection *mongo.Collection
+	ctx context.Context
 }
 
 func NewUserRepository(collection *mongo.Collection) *UserRepository {
 	return &UserRepository{
 		collection: collection,
+		ctx: context.TODO(),
 	}
 }
 
+func (ur *UserRepository) WithOutbox(fn func(users usecases.IUserRepo, outbox usecases.IOutboxRepo) error) error {
+	return withOutbox(ur.ctx, ur.collection.Database().Client(), func(ctx context.Context, outbox *OutboxRepository) error {
+		return fn(&UserRepository{collection: ur.collection, ctx: ctx}, outbox)
+	})
+}
+
 func (ur *UserRepository) FetchByUsername(username string) (domain.User, error) {
 	var existingUser domain.User
 
-	err := ur.collection.FindOne(context.TODO(), bson.D{{Key: "username", Value: username}}).Decode(&existingUser)
+	err := ur.collection.FindOne(ur.ctx, bson.D{{Key: "username", Value: username}}).Decode(&existingUser)
 
 	if err != nil {
 		return domain.User{}, errors.New("user does not exists")
@@ -33,8 +42,25 @@ func (ur *UserRepository) FetchByUsername(username string) (domain.User, error)
 	return existingUser, nil
 }
 
+func (ur *UserRepository) FetchByExternalIdentity(issuer string, subject string) (domain.User, error) {
+	var existingUser domain.User
+
+	filter := bson.D{{Key: "external_identities", Value: bson.D{
+		{Key: "$elemMatch", Value: bson.D{
+			{Key: "issuer", Value: issuer},
+			{Key: "subject", Value: subject},
+		}},
+	}}}
+
+	err := ur.collection.FindOne(ur.ctx, filter).Decode(&existingUser)
+	if err != nil {
+		return domain.User{}, errors.New("user does not exists")
+	}
+	return existingUser, nil
+}
+
 func (ur *UserRepository) CountUsers() (int, error) {
-	userCount, err := ur.collection.CountDocuments(context.TODO(), bson.D{{}})
+	userCount, err := ur.collection.CountDocuments(ur.ctx, bson.D{{}})
 	if err != nil {
 		return 0, errors.New("unable to register user")
 	}
@@ -43,8 +69,11 @@ func (ur *UserRepository) CountUsers() (int, error) {
 
 func (ur *UserRepository) Register(user *domain.User) (domain.User, error) {
 	user.ID = primitive.NewObjectID()
+	if user.Memberships == nil {
+		user.Memberships = []domain.Membership{}
+	}
 
-	_, err := ur.collection.InsertOne(context.TODO(), user)
+	_, err := ur.collection.InsertOne(ur.ctx, user)
 	if err != nil {
 		return domain.User{}, errors.New(err.Error())
 	}
@@ -61,32 +90,39 @@ func (ur *UserRepository) Promote(user *domain.User) (domain.User, error) {
 		{Key: "role", Value: "admin"},
 	}}}
 
-	_, err := ur.collection.UpdateOne(context.TODO(), filter, update)
+	_, err := ur.collection.UpdateOne(ur.ctx, filter, update)
 	if err != nil {
 		return domain.User{}, errors.New(err.Error())
 	}
 	
-	err = ur.collection.FindOne(context.TODO(), filter).Decode(&updatedUser)
+	err = ur.collection.FindOne(ur.ctx, filter).Decode(&updatedUser)
 	if err != nil {
 		return domain.User{}, errors.New("user not found")
 	}
 	return updatedUser, nil
 }
 
-func (ur *UserRepository) FetchAll() ([]domain.User, error) {
+func (ur *UserRepository) FetchAll(tenantIDStr string) ([]domain.User, error) {
 	var users []domain.User
 
-	cur, err := ur.collection.Find(context.TODO(), bson.D{{}})
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.User{}, err
+	}
+
+	filter := bson.D{{Key: "memberships.organization_id", Value: tenantID}}
+
+	cur, err := ur.collection.Find(ur.ctx, filter)
 	if err != nil {
 		return []domain.User{}, errors.New("could not fetch users")
 	}
 
-	err = cur.All(context.TODO(), &users)
+	err = cur.All(ur.ctx, &users)
 	if err != nil {
 		return []domain.User{}, errors.New("could not fetch users")
 	}
 
-	cur.Close(context.TODO())
+	cur.Close(ur.ctx)
 
 	return users, nil
 }
@@ -101,7 +137,7 @@ func (ur *UserRepository) Fetch(idStr string) (domain.User, error) {
 
 	filter := bson.D{{Key: "_id", Value: id}}
 
-	err = ur.collection.FindOne(context.TODO(), filter).Decode(&user)
+	err = ur.collection.FindOne(ur.ctx, filter).Decode(&user)
 	if err != nil {
 		return domain.User{}, errors.New("user not found")
 	}
@@ -109,7 +145,105 @@ func (ur *UserRepository) Fetch(idStr string) (domain.User, error) {
 	return user, nil
 }
 
-func (ur *UserRepository) Update(idStr string, updatedUser domain.User) (domain.User, error) {
+func (ur *UserRepository) FetchMember(tenantIDStr string, idStr string) (domain.User, error) {
+	var user domain.User
+
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return domain.User{}, errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "memberships.organization_id", Value: tenantID},
+	}
+
+	err = ur.collection.FindOne(ur.ctx, filter).Decode(&user)
+	if err != nil {
+		return domain.User{}, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func (ur *UserRepository) SetMembership(idStr string, membership domain.Membership) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "memberships.organization_id", Value: membership.OrganizationID},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "memberships.$.role", Value: membership.Role},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	result, err := ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return errors.New("system could not update user")
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	filter = bson.D{
+		{Key: "_id", Value: id},
+		{Key: "memberships.organization_id", Value: bson.D{{Key: "$ne", Value: membership.OrganizationID}}},
+	}
+	update = bson.D{
+		{Key: "$push", Value: bson.D{{Key: "memberships", Value: membership}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	}
+
+	result, err = ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return errors.New("system could not update user")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (ur *UserRepository) RemoveMembership(idStr string, tenantIDStr string) error {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return err
+	}
+
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "memberships.organization_id", Value: tenantID},
+	}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "memberships", Value: bson.D{{Key: "organization_id", Value: tenantID}}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	}
+
+	result, err := ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return errors.New("system could not update user")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user is not a member of this organization")
+	}
+	return nil
+}
+
+func (ur *UserRepository) Update(idStr string, update domain.ProfileUpdate) (domain.User, error) {
 	var user domain.User
 
 	id, err := primitive.ObjectIDFromHex(idStr)
@@ -120,19 +254,40 @@ func (ur *UserRepository) Update(idStr string, updatedUser domain.User) (domain.
 	filter := bson.D{{Key: "_id", Value: id}}
 
 	fields := bson.D{}
-	if updatedUser.Email != "" {
-		fields = append(fields, bson.E{Key: "email", Value: updatedUser.Email})
+	if update.Username != nil {
+		fields = append(fields, bson.E{Key: "username", Value: *update.Username})
+	}
+	if update.Email != nil {
+		fields = append(fields, bson.E{Key: "email", Value: *update.Email})
+	}
+	if update.DisplayName != nil {
+		fields = append(fields, bson.E{Key: "display_name", Value: *update.DisplayName})
+	}
+	if update.AvatarURL != nil {
+		fields = append(fields, bson.E{Key: "avatar_url", Value: *update.AvatarURL})
+	}
+	if update.Timezone != nil {
+		fields = append(fields, bson.E{Key: "timezone", Value: *update.Timezone})
+	}
+	if update.Locale != nil {
+		fields = append(fields, bson.E{Key: "locale", Value: *update.Locale})
+	}
+	if update.NotificationPreferences != nil {
+		fields = append(fields, bson.E{Key: "notification_preferences", Value: *update.NotificationPreferences})
 	}
 	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
 
-	update := bson.D{{Key: "$set", Value: fields}}
+	updateDoc := bson.D{{Key: "$set", Value: fields}}
 
-	_, err = ur.collection.UpdateOne(context.TODO(), filter, update)
+	_, err = ur.collection.UpdateOne(ur.ctx, filter, updateDoc)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.User{}, errors.New("user with this username already exists")
+	}
 	if err != nil {
 		return domain.User{}, errors.New(err.Error())
 	}
 	
-	err = ur.collection.FindOne(context.TODO(), filter).Decode(&user)
+	err = ur.collection.FindOne(ur.ctx, filter).Decode(&user)
 	if err != nil {
 		return domain.User{}, errors.New(err.Error())
 	}
@@ -155,7 +310,7 @@ func (ur *UserRepository) ChangePassword(idStr string, prevPassword string, newP
 		{Key: "password", Value: string(hashedPassword)},
 	}}}
 
-	_, err = ur.collection.UpdateOne(context.TODO(), filter, update)
+	_, err = ur.collection.UpdateOne(ur.ctx, filter, update)
 	if err != nil {
 		return errors.New("system could not update user")
 	}
@@ -163,6 +318,108 @@ func (ur *UserRepository) ChangePassword(idStr string, prevPassword string, newP
 	return nil
 }
 
+func (ur *UserRepository) UpdateRole(idStr string, role string) error {
+	return ur.updateFields(idStr, bson.D{
+		{Key: "role", Value: role},
+	})
+}
+
+func (ur *UserRepository) SetPendingTotpSecret(idStr string, secret string) error {
+	return ur.updateFields(idStr, bson.D{
+		{Key: "pending_totp_secret", Value: secret},
+	})
+}
+
+func (ur *UserRepository) EnableTwoFactor(idStr string, secret string, lastCounter int64, recoveryCodes []string) error {
+	return ur.updateFields(idStr, bson.D{
+		{Key: "two_factor_enabled", Value: true},
+		{Key: "totp_secret", Value: secret},
+		{Key: "pending_totp_secret", Value: ""},
+		{Key: "totp_last_counter", Value: lastCounter},
+		{Key: "recovery_codes", Value: recoveryCodes},
+	})
+}
+
+func (ur *UserRepository) DisableTwoFactor(idStr string) error {
+	return ur.updateFields(idStr, bson.D{
+		{Key: "two_factor_enabled", Value: false},
+		{Key: "totp_secret", Value: ""},
+		{Key: "pending_totp_secret", Value: ""},
+		{Key: "totp_last_counter", Value: int64(0)},
+		{Key: "recovery_codes", Value: []string{}},
+	})
+}
+
+func (ur *UserRepository) ClaimTotpCounter(idStr string, counter int64) (bool, error) {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return false, errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "totp_last_counter", Value: bson.D{{Key: "$lt", Value: counter}}}},
+			bson.D{{Key: "totp_last_counter", Value: bson.D{{Key: "$exists", Value: false}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "totp_last_counter", Value: counter}}}}
+
+	result, err := ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return false, errors.New("system could not update user")
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (ur *UserRepository) UpdateRecoveryCodes(idStr string, recoveryCodes []string) error {
+	return ur.updateFields(idStr, bson.D{
+		{Key: "recovery_codes", Value: recoveryCodes},
+	})
+}
+
+func (ur *UserRepository) ConsumeRecoveryCode(idStr string, hashedCode string) (bool, error) {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return false, errors.New("invalid id")
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "recovery_codes", Value: hashedCode},
+	}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "recovery_codes", Value: hashedCode}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	}
+
+	result, err := ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return false, errors.New("system could not update user")
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (ur *UserRepository) updateFields(idStr string, fields bson.D) error {
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	filter := bson.D{{Key: "_id", Value: id}}
+
+	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
+	update := bson.D{{Key: "$set", Value: fields}}
+
+	result, err := ur.collection.UpdateOne(ur.ctx, filter, update)
+	if err != nil {
+		return errors.New("system could not update user")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
 func (ur *UserRepository) Remove(idStr string) error {
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
@@ -171,7 +428,7 @@ func (ur *UserRepository) Remove(idStr string) error {
 
 	filter := bson.D{{Key: "_id", Value: id}}
 
-	_, err = ur.collection.DeleteOne(context.TODO(), filter)
+	_, err = ur.collection.DeleteOne(ur.ctx, filter)
 
 	if err != nil {
 		return errors.New("user not found")
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(collection *mongo.Collection) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		collection: collection,
+	}
+}
+
+func (dr *WebhookDeliveryRepository) Create(tenantIDStr string, delivery *domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery.ID = primitive.NewObjectID()
+	delivery.OrganizationID = tenantID
+	if delivery.Attempts == nil {
+		delivery.Attempts = []domain.WebhookAttempt{}
+	}
+
+	_, err = dr.collection.InsertOne(context.TODO(), delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, errors.New("cannot insert webhook delivery to database")
+	}
+	return *delivery, nil
+}
+
+func (dr *WebhookDeliveryRepository) Fetch(tenantIDStr string, idStr string) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	err = dr.collection.FindOne(context.TODO(), filter).Decode(&delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, errors.New("webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+func (dr *WebhookDeliveryRepository) FetchByWebhook(tenantIDStr string, webhookIDStr string, limit int) ([]domain.WebhookDelivery, error) {
+	filter, err := webhookFilter(tenantIDStr, webhookIDStr)
+	if err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+
+	return dr.find(filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit)))
+}
+
+func (dr *WebhookDeliveryRepository) FetchByStatus(tenantIDStr string, status string) ([]domain.WebhookDelivery, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+
+	filter := bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "status", Value: status},
+	}
+	return dr.find(filter, options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+}
+
+func (dr *WebhookDeliveryRepository) ClaimDue(now time.Time, lease time.Duration) (domain.WebhookDelivery, bool, error) {
+	var delivery domain.WebhookDelivery
+
+	filter := bson.D{
+		{Key: "status", Value: "pending"},
+		{Key: "next_attempt_at", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "next_attempt_at", Value: now.Add(lease)}}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	err := dr.collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.WebhookDelivery{}, false, nil
+	}
+	if err != nil {
+		return domain.WebhookDelivery{}, false, errors.New("cannot claim webhook delivery")
+	}
+	return delivery, true, nil
+}
+
+func (dr *WebhookDeliveryRepository) RecordAttempt(tenantIDStr string, idStr string, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "attempts", Value: attempt}}},
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: status},
+			{Key: "next_attempt_at", Value: nextAttemptAt},
+			{Key: "updated_at", Value: attempt.At},
+		}},
+	}
+
+	_, err = dr.collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return errors.New("cannot update webhook delivery")
+	}
+	return nil
+}
+
+func (dr *WebhookDeliveryRepository) Requeue(tenantIDStr string, idStr string, now time.Time) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	filter = append(filter, bson.E{Key: "status", Value: "dead"})
+
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: "pending"},
+		{Key: "next_attempt_at", Value: now},
+		{Key: "updated_at", Value: now},
+	}}}
+
+	err = dr.collection.FindOneAndUpdate(context.TODO(), filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, errors.New("dead webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+func (dr *WebhookDeliveryRepository) RemoveByWebhook(tenantIDStr string, webhookIDStr string) error {
+	filter, err := webhookFilter(tenantIDStr, webhookIDStr)
+	if err != nil {
+		return err
+	}
+
+	_, err = dr.collection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return errors.New("cannot remove webhook deliveries")
+	}
+	return nil
+}
+
+func (dr *WebhookDeliveryRepository) find(filter bson.D, opts *options.FindOptions) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+
+	cur, err := dr.collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return []domain.WebhookDelivery{}, errors.New("cannot retrieve webhook deliveries")
+	}
+
+	err = cur.All(context.TODO(), &deliveries)
+	if err != nil {
+		return []domain.WebhookDelivery{}, errors.New("cannot retrieve webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+func webhookFilter(tenantIDStr string, webhookIDStr string) (bson.D, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookID, err := primitive.ObjectIDFromHex(webhookIDStr)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+
+	return bson.D{
+		{Key: "organization_id", Value: tenantID},
+		{Key: "webhook_id", Value: webhookID},
+	}, nil
+}
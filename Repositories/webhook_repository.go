@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookRepository(collection *mongo.Collection) *WebhookRepository {
+	return &WebhookRepository{
+		collection: collection,
+	}
+}
+
+func (wr *WebhookRepository) Create(tenantIDStr string, webhook *domain.Webhook) (domain.Webhook, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	webhook.ID = primitive.NewObjectID()
+	webhook.OrganizationID = tenantID
+
+	_, err = wr.collection.InsertOne(context.TODO(), webhook)
+	if err != nil {
+		return domain.Webhook{}, errors.New("cannot insert webhook to database")
+	}
+	return *webhook, nil
+}
+
+func (wr *WebhookRepository) FetchAll(tenantIDStr string) ([]domain.Webhook, error) {
+	tenantID, err := parseTenantID(tenantIDStr)
+	if err != nil {
+		return []domain.Webhook{}, err
+	}
+
+	return wr.find(bson.D{{Key: "organization_id", Value: tenantID}})
+}
+
+func (wr *WebhookRepository) FetchSubscribed(tenantIDStr string, event string) ([]domain.Webhook, error) {
+	filter := bson.D{
+		{Key: "events", Value: event},
+		{Key: "active", Value: true},
+	}
+	if tenantIDStr != "" {
+		tenantID, err := parseTenantID(tenantIDStr)
+		if err != nil {
+			return []domain.Webhook{}, err
+		}
+		filter = append(filter, bson.E{Key: "organization_id", Value: tenantID})
+	}
+
+	return wr.find(filter)
+}
+
+func (wr *WebhookRepository) Fetch(tenantIDStr string, idStr string) (domain.Webhook, error) {
+	var webhook domain.Webhook
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	err = wr.collection.FindOne(context.TODO(), filter).Decode(&webhook)
+	if err != nil {
+		return domain.Webhook{}, errors.New("webhook not found")
+	}
+	return webhook, nil
+}
+
+func (wr *WebhookRepository) Update(tenantIDStr string, idStr string, update domain.WebhookUpdate) (domain.Webhook, error) {
+	var updatedWebhook domain.Webhook
+
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	fields := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if update.URL != nil {
+		fields = append(fields, bson.E{Key: "url", Value: *update.URL})
+	}
+	if update.Events != nil {
+		fields = append(fields, bson.E{Key: "events", Value: update.Events})
+	}
+	if update.Active != nil {
+		fields = append(fields, bson.E{Key: "active", Value: *update.Active})
+	}
+
+	err = wr.collection.FindOneAndUpdate(context.TODO(), filter, bson.D{{Key: "$set", Value: fields}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updatedWebhook)
+	if err != nil {
+		return domain.Webhook{}, errors.New("webhook not found")
+	}
+	return updatedWebhook, nil
+}
+
+func (wr *WebhookRepository) Remove(tenantIDStr string, idStr string) error {
+	filter, err := tenantDocumentFilter(tenantIDStr, idStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := wr.collection.DeleteOne(context.TODO(), filter)
+	if err != nil || result.DeletedCount == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+func (wr *WebhookRepository) find(filter bson.D) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+
+	cur, err := wr.collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return []domain.Webhook{}, errors.New("cannot retrieve webhooks")
+	}
+
+	err = cur.All(context.TODO(), &webhooks)
+	if err != nil {
+		return []domain.Webhook{}, errors.New("cannot retrieve webhooks")
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyManagerTestSuite struct {
+	suite.Suite
+	now time.Time
+	dir string
+}
+
+func (suite *KeyManagerTestSuite) SetupTest() {
+	suite.now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *KeyManagerTestSuite) newKeyManager(algorithm string) *infrastructure.KeyManager {
+	km, err := infrastructure.NewKeyManager(infrastructure.KeyConfig{
+		Dir:              suite.dir,
+		Algorithm:        algorithm,
+		RotationInterval: time.Hour * 24 * 7,
+		TokenLifetime:    time.Hour * 24,
+		Now:              func() time.Time { return suite.now },
+	})
+	suite.Require().NoError(err)
+	return km
+}
+
+func (suite *KeyManagerTestSuite) sign(km *infrastructure.KeyManager) string {
+	token, err := km.Sign(jwt.MapClaims{
+		"user_id": "687ce5ab33fd48459614ca4f",
+		"exp":     suite.now.Add(time.Hour * 24).Unix(),
+	})
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *KeyManagerTestSuite) keyIDs(km *infrastructure.KeyManager) []string {
+	kids := []string{}
+	for _, key := range km.JWKS()["keys"].([]map[string]string) {
+		kids = append(kids, key["kid"])
+	}
+	return kids
+}
+
+func (suite *KeyManagerTestSuite) TestSignAndParseRS256() {
+	km := suite.newKeyManager("RS256")
+
+	token, err := km.Parse(suite.sign(km))
+	suite.NoError(err)
+	suite.Equal("RS256", token.Method.Alg())
+	suite.NotEmpty(token.Header["kid"])
+}
+
+func (suite *KeyManagerTestSuite) TestSignAndParseEdDSA() {
+	km := suite.newKeyManager("EdDSA")
+
+	token, err := km.Parse(suite.sign(km))
+	suite.NoError(err)
+	suite.Equal("EdDSA", token.Method.Alg())
+
+	jwks := km.JWKS()["keys"].([]map[string]string)
+	suite.Equal("OKP", jwks[0]["kty"])
+	suite.Equal("Ed25519", jwks[0]["crv"])
+}
+
+func (suite *KeyManagerTestSuite) TestRejectsUnsupportedAlgorithm() {
+	_, err := infrastructure.NewKeyManager(infrastructure.KeyConfig{Algorithm: "HS256"})
+	suite.Error(err)
+}
+
+func (suite *KeyManagerTestSuite) TestRejectsHMACTokens() {
+	km := suite.newKeyManager("RS256")
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": suite.now.Add(time.Hour).Unix()})
+	forged.Header["kid"] = suite.keyIDs(km)[0]
+	tokenStr, _ := forged.SignedString([]byte("guessed"))
+
+	_, err := km.Parse(tokenStr)
+	suite.Error(err)
+}
+
+func (suite *KeyManagerTestSuite) TestRotationKeepsPreviousKeyForVerification() {
+	km := suite.newKeyManager("RS256")
+
+	suite.now = suite.now.Add(time.Hour*24*7 - time.Hour)
+	oldToken := suite.sign(km)
+
+	suite.now = suite.now.Add(time.Hour)
+	suite.NoError(km.RotateIfDue())
+	suite.Len(suite.keyIDs(km), 2)
+
+	newToken := suite.sign(km)
+	old, err := km.Parse(oldToken)
+	suite.NoError(err)
+	rotated, err := km.Parse(newToken)
+	suite.NoError(err)
+	suite.NotEqual(old.Header["kid"], rotated.Header["kid"])
+}
+
+func (suite *KeyManagerTestSuite) TestRotateIfDueIsNoopBeforeInterval() {
+	km := suite.newKeyManager("RS256")
+
+	suite.now = suite.now.Add(time.Hour * 24)
+	suite.NoError(km.RotateIfDue())
+	suite.Len(suite.keyIDs(km), 1)
+}
+
+func (suite *KeyManagerTestSuite) TestRetiredKeysArePrunedAfterTokenLifetime() {
+	km := suite.newKeyManager("RS256")
+	first := suite.keyIDs(km)[0]
+
+	suite.now = suite.now.Add(time.Hour * 24 * 7)
+	suite.NoError(km.RotateIfDue())
+
+	suite.now = suite.now.Add(time.Hour * 24 * 7)
+	suite.NoError(km.RotateIfDue())
+
+	suite.NotContains(suite.keyIDs(km), first)
+	_, err := os.Stat(filepath.Join(suite.dir, first+".pem"))
+	suite.True(os.IsNotExist(err))
+}
+
+func (suite *KeyManagerTestSuite) TestKeysArePersistedAcrossRestarts() {
+	km := suite.newKeyManager("EdDSA")
+	token := suite.sign(km)
+
+	restarted := suite.newKeyManager("EdDSA")
+	suite.Equal(suite.keyIDs(km), suite.keyIDs(restarted))
+
+	_, err := restarted.Parse(token)
+	suite.NoError(err)
+}
+
+func TestKeyManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyManagerTestSuite))
+}
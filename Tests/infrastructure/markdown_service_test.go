@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type MarkdownTestSuite struct {
+	suite.Suite
+	infra *infrastructure.Infrastructure
+}
+
+func (suite *MarkdownTestSuite) SetupTest() {
+	suite.infra = new(infrastructure.Infrastructure)
+}
+
+func (suite *MarkdownTestSuite) TestRenderMarkdownFormatsText() {
+	html := suite.infra.RenderMarkdown("**ship it** and see [the spec](https://example.com/spec)")
+
+	suite.Contains(html, "<strong>ship it</strong>")
+	suite.Contains(html, `href="https://example.com/spec"`)
+}
+
+func (suite *MarkdownTestSuite) TestRenderMarkdownStripsScripts() {
+	html := suite.infra.RenderMarkdown("hello <script>alert(1)</script> <img src=x onerror=alert(1)>")
+
+	suite.NotContains(html, "<script")
+	suite.NotContains(html, "onerror")
+}
+
+func (suite *MarkdownTestSuite) TestRenderMarkdownStripsJavascriptLinks() {
+	html := suite.infra.RenderMarkdown("[click](javascript:alert(1))")
+
+	suite.NotContains(html, "javascript:")
+}
+
+func TestMarkdownServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(MarkdownTestSuite))
+}
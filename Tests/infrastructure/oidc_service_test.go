@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type stubOIDCServer struct {
+	*httptest.Server
+	key           *rsa.PrivateKey
+	codeChallenge string
+	nonce         string
+	claims        jwt.MapClaims
+}
+
+func newStubOIDCServer() *stubOIDCServer {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	stub := &stubOIDCServer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 stub.URL,
+			"authorization_endpoint": stub.URL + "/authorize",
+			"token_endpoint":         stub.URL + "/token",
+			"jwks_uri":               stub.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "stub-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "valid-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != stub.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":                stub.URL,
+			"aud":                "task-manager",
+			"sub":                "subject-1",
+			"email":              "jesse@example.com",
+			"preferred_username": "jesse",
+			"groups":             []string{"engineering", "task-admins"},
+			"nonce":              stub.nonce,
+			"exp":                time.Now().Add(time.Minute).Unix(),
+		}
+		for claim, value := range stub.claims {
+			claims[claim] = value
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "stub-key"
+		idToken, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	stub.Server = httptest.NewServer(mux)
+	return stub
+}
+
+type OIDCTestSuite struct {
+	suite.Suite
+	stub     *stubOIDCServer
+	provider *infrastructure.OIDCProvider
+}
+
+func (suite *OIDCTestSuite) SetupTest() {
+	suite.stub = newStubOIDCServer()
+	suite.provider = infrastructure.NewOIDCProvider(infrastructure.OIDCConfig{
+		IssuerURL:       suite.stub.URL,
+		ClientID:        "task-manager",
+		RedirectURL:     "http://localhost:8080/login/oidc/callback",
+		Scopes:          []string{"openid"},
+		RoleClaim:       "groups",
+		AdminRoleValues: []string{"task-admins"},
+	}, suite.stub.Client())
+}
+
+func (suite *OIDCTestSuite) TearDownTest() {
+	suite.stub.Close()
+}
+
+func (suite *OIDCTestSuite) beginAuth() (string, string) {
+	authURL, sessionToken, err := suite.provider.BeginAuth()
+	suite.Require().NoError(err)
+
+	parsed, err := url.Parse(authURL)
+	suite.Require().NoError(err)
+	query := parsed.Query()
+	suite.Equal("S256", query.Get("code_challenge_method"))
+	suite.Equal("task-manager", query.Get("client_id"))
+
+	suite.stub.codeChallenge = query.Get("code_challenge")
+	suite.stub.nonce = query.Get("nonce")
+	return sessionToken, query.Get("state")
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuth() {
+	sessionToken, state := suite.beginAuth()
+
+	identity, err := suite.provider.CompleteAuth(sessionToken, state, "valid-code")
+	suite.NoError(err)
+	suite.Equal(suite.stub.URL, identity.Issuer)
+	suite.Equal("subject-1", identity.Subject)
+	suite.Equal("jesse", identity.Username)
+	suite.Equal("jesse@example.com", identity.Email)
+	suite.Equal("admin", identity.Role)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthMapsRegularRole() {
+	sessionToken, state := suite.beginAuth()
+	suite.stub.claims = jwt.MapClaims{"groups": []string{"engineering"}}
+
+	identity, err := suite.provider.CompleteAuth(sessionToken, state, "valid-code")
+	suite.NoError(err)
+	suite.Equal("regular", identity.Role)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthRejectsStateMismatch() {
+	sessionToken, _ := suite.beginAuth()
+
+	_, err := suite.provider.CompleteAuth(sessionToken, "forged", "valid-code")
+	suite.Error(err)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthRejectsWrongAudience() {
+	sessionToken, state := suite.beginAuth()
+	suite.stub.claims = jwt.MapClaims{"aud": "another-client"}
+
+	_, err := suite.provider.CompleteAuth(sessionToken, state, "valid-code")
+	suite.Error(err)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthRejectsNonceMismatch() {
+	sessionToken, state := suite.beginAuth()
+	suite.stub.claims = jwt.MapClaims{"nonce": "replayed"}
+
+	_, err := suite.provider.CompleteAuth(sessionToken, state, "valid-code")
+	suite.Error(err)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthRejectsExpiredIDToken() {
+	sessionToken, state := suite.beginAuth()
+	suite.stub.claims = jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}
+
+	_, err := suite.provider.CompleteAuth(sessionToken, state, "valid-code")
+	suite.Error(err)
+}
+
+func (suite *OIDCTestSuite) TestCompleteAuthRejectsRejectedCode() {
+	sessionToken, state := suite.beginAuth()
+
+	_, err := suite.provider.CompleteAuth(sessionToken, state, "stolen-code")
+	suite.Error(err)
+}
+
+func TestOIDCTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StreamTicketTestSuite struct {
+	suite.Suite
+}
+
+func (suite *StreamTicketTestSuite) TestTicketCarriesIdentityAndTenant() {
+	ticket, err := infrastructure.GenerateStreamTicket("687ce5ab33fd48459614ca4f", "regular", true, "687ce5ab33fd48459614ca50")
+	suite.Require().NoError(err)
+
+	claims, err := infrastructure.ValidateStreamTicket(ticket)
+	suite.Require().NoError(err)
+	suite.Equal("687ce5ab33fd48459614ca4f", claims["user_id"])
+	suite.Equal("regular", claims["role"])
+	suite.Equal(true, claims["two_factor"])
+	suite.Equal("687ce5ab33fd48459614ca50", claims["organization_id"])
+}
+
+func (suite *StreamTicketTestSuite) TestTicketIsNotABearerToken() {
+	ticket, err := infrastructure.GenerateStreamTicket("687ce5ab33fd48459614ca4f", "regular", false, "687ce5ab33fd48459614ca50")
+	suite.Require().NoError(err)
+
+	_, err = infrastructure.ValidateJwtToken("Bearer " + ticket)
+	suite.EqualError(err, "invalid token")
+}
+
+func (suite *StreamTicketTestSuite) TestSessionTokenIsNotATicket() {
+	token, err := new(infrastructure.Infrastructure).GenerateJwtToken(&domain.User{ID: primitive.NewObjectID(), Role: "regular"})
+	suite.Require().NoError(err)
+
+	_, err = infrastructure.ValidateStreamTicket(token)
+	suite.EqualError(err, "invalid or expired stream ticket")
+}
+
+func (suite *StreamTicketTestSuite) TestRedactQueryHidesCredentials() {
+	suite.Equal("/tasks/stream?last_event_id=42&ticket=REDACTED", infrastructure.RedactQuery("/tasks/stream?ticket=secret&last_event_id=42"))
+	suite.Equal("/tasks/stream?access_token=REDACTED", infrastructure.RedactQuery("/tasks/stream?access_token=secret"))
+	suite.Equal("/tasks/stream", infrastructure.RedactQuery("/tasks/stream?ticket=%zz"))
+	suite.Equal("/tasks", infrastructure.RedactQuery("/tasks"))
+}
+
+func TestStreamTicketTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamTicketTestSuite))
+}
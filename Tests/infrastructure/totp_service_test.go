@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TotpTestSuite struct {
+	suite.Suite
+	infra *infrastructure.Infrastructure
+}
+
+func (suite *TotpTestSuite) SetupTest() {
+	suite.infra = new(infrastructure.Infrastructure)
+}
+
+func (suite *TotpTestSuite) TestGenerateTotpCodeMatchesRFCVectors() {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := infrastructure.GenerateTotpCode(rfcSecret, time.Unix(unix, 0))
+		suite.NoError(err)
+		suite.Equal(expected, code)
+	}
+}
+
+func (suite *TotpTestSuite) TestValidateTotpCodeAllowsClockSkew() {
+	now := time.Unix(1234567890, 0)
+	previous, _ := infrastructure.GenerateTotpCode(rfcSecret, now.Add(-30*time.Second))
+	stale, _ := infrastructure.GenerateTotpCode(rfcSecret, now.Add(-90*time.Second))
+
+	suite.True(infrastructure.ValidateTotpCodeAt(rfcSecret, previous, now))
+	suite.False(infrastructure.ValidateTotpCodeAt(rfcSecret, stale, now))
+	suite.False(infrastructure.ValidateTotpCodeAt(rfcSecret, "12345", now))
+}
+
+func (suite *TotpTestSuite) TestMatchTotpCodeReturnsTheMatchedStep() {
+	now := time.Unix(1234567890, 0)
+	previous, _ := infrastructure.GenerateTotpCode(rfcSecret, now.Add(-30*time.Second))
+	current, _ := infrastructure.GenerateTotpCode(rfcSecret, now)
+
+	counter, ok := infrastructure.MatchTotpCodeAt(rfcSecret, previous, now)
+	suite.True(ok)
+	suite.Equal(int64(1234567890/30-1), counter)
+
+	counter, ok = infrastructure.MatchTotpCodeAt(rfcSecret, current, now)
+	suite.True(ok)
+	suite.Equal(int64(1234567890/30), counter)
+}
+
+func (suite *TotpTestSuite) TestGeneratedSecretRoundTrips() {
+	secret, err := suite.infra.GenerateTotpSecret()
+	suite.NoError(err)
+
+	code, err := infrastructure.GenerateTotpCode(secret, time.Now())
+	suite.NoError(err)
+	suite.True(suite.infra.ValidateTotpCode(secret, code))
+}
+
+func (suite *TotpTestSuite) TestProvisioningURI() {
+	uri := suite.infra.TotpProvisioningURI(rfcSecret, "heisenberg")
+
+	suite.True(strings.HasPrefix(uri, "otpauth://totp/TaskManager:heisenberg?"))
+	suite.Contains(uri, "secret="+rfcSecret)
+	suite.Contains(uri, "issuer=TaskManager")
+}
+
+func (suite *TotpTestSuite) TestGenerateRecoveryCodesAreUnique() {
+	codes, err := suite.infra.GenerateRecoveryCodes(10)
+	suite.NoError(err)
+	suite.Len(codes, 10)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		suite.Len(code, 17)
+		suite.False(seen[code])
+		seen[code] = true
+	}
+}
+
+func TestTotpTestSuite(t *testing.T) {
+	suite.Run(t, new(TotpTestSuite))
+}
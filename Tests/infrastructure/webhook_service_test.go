@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	infra *infrastructure.Infrastructure
+}
+
+func (suite *WebhookServiceTestSuite) SetupTest() {
+	suite.infra = new(infrastructure.Infrastructure)
+}
+
+func (suite *WebhookServiceTestSuite) TestSignWebhookPayload() {
+	signature := suite.infra.SignWebhookPayload("whsec_test", 1700000000, []byte(`{"event":"task.created"}`))
+
+	suite.Equal("sha256=aabc548901ea3b50be05eb85dc114164830b27c602dcb16a1623b007eff48c20", signature)
+}
+
+func (suite *WebhookServiceTestSuite) TestGenerateWebhookSecret() {
+	first, err := suite.infra.GenerateWebhookSecret()
+	suite.NoError(err)
+	second, err := suite.infra.GenerateWebhookSecret()
+	suite.NoError(err)
+
+	suite.True(strings.HasPrefix(first, "whsec_"))
+	suite.NotEqual(first, second)
+}
+
+func (suite *WebhookServiceTestSuite) TestSendPostsPayloadWithHeaders() {
+	suite.T().Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	var received *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := infrastructure.NewWebhookSender(nil)
+	status, err := sender.Send(server.URL, map[string]string{"X-Webhook-Signature": "sha256=abc"}, []byte(`{"ok":true}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusAccepted, status)
+
+	suite.Equal(http.MethodPost, received.Method)
+	suite.Equal("application/json", received.Header.Get("Content-Type"))
+	suite.Equal("sha256=abc", received.Header.Get("X-Webhook-Signature"))
+	suite.Equal(`{"ok":true}`, body)
+}
+
+func (suite *WebhookServiceTestSuite) TestSendDoesNotFollowRedirects() {
+	suite.T().Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	status, err := infrastructure.NewWebhookSender(nil).Send(server.URL, nil, []byte(`{}`))
+	suite.NoError(err)
+	suite.Equal(http.StatusFound, status)
+}
+
+func (suite *WebhookServiceTestSuite) TestSendRejectsPrivateTargets() {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	sender := infrastructure.NewWebhookSender(nil)
+	for _, target := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/", "http://[::1]/", "http://0.0.0.0/"} {
+		_, err := sender.Send(target, nil, []byte(`{}`))
+		suite.ErrorContains(err, "is not a public address", target)
+	}
+	suite.False(called)
+}
+
+func TestWebhookServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
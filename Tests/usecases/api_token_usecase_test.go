@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const rawAPIToken = "tm_pat_0123456789abcdef"
+
+type APITokenTestSuite struct {
+	suite.Suite
+	mockTokenRepo *mocks.MockAPITokenRepo
+	mockUserRepo  *mocks.MockUserRepo
+	mockinfra     *mocks.MockInfrastructure
+	usecase       usecases.APITokenUsecase
+}
+
+func (suite *APITokenTestSuite) SetupTest() {
+	suite.mockTokenRepo = new(mocks.MockAPITokenRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.mockinfra = new(mocks.MockInfrastructure)
+	suite.usecase = *usecases.NewAPITokenUsecase(suite.mockTokenRepo, suite.mockUserRepo, suite.mockinfra)
+}
+
+func (suite *APITokenTestSuite) TestCreateToken() {
+	userID := primitive.NewObjectID()
+
+	suite.mockTokenRepo.On("FetchByUser", userID.Hex()).Return([]domain.APIToken{}, nil)
+	suite.mockinfra.On("GenerateAPIToken").Return(rawAPIToken, nil)
+	suite.mockinfra.On("HashAPIToken", rawAPIToken).Return("hashed")
+	suite.mockTokenRepo.On("Create", mock.MatchedBy(func(token *domain.APIToken) bool {
+		return token.UserID == userID && token.TokenHash == "hashed" && token.Prefix == "tm_pat_01234"
+	})).Return(domain.APIToken{Name: "ci"}, nil)
+
+	raw, token, err := suite.usecase.Create(userID.Hex(), "ci", []string{"tasks:read"}, time.Time{}, nil)
+	suite.NoError(err)
+	suite.Equal(rawAPIToken, raw)
+	suite.Equal("ci", token.Name)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestCreateTokenInvalidScope() {
+	_, _, err := suite.usecase.Create(primitive.NewObjectID().Hex(), "ci", []string{"everything"}, time.Time{}, nil)
+	suite.Error(err)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestCreateTokenCannotEscalateScopes() {
+	_, _, err := suite.usecase.Create(primitive.NewObjectID().Hex(), "ci", []string{"account", "users:write"}, time.Time{}, []string{"account"})
+	suite.EqualError(err, "an API token cannot grant the users:write scope it does not have")
+
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *APITokenTestSuite) TestCreateTokenDuplicateName() {
+	userID := primitive.NewObjectID().Hex()
+
+	suite.mockTokenRepo.On("FetchByUser", userID).Return([]domain.APIToken{{Name: "ci"}}, nil)
+
+	_, _, err := suite.usecase.Create(userID, "ci", []string{"tasks:read"}, time.Time{}, nil)
+	suite.Error(err)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestAuthenticate() {
+	user := domain.User{ID: primitive.NewObjectID(), Role: "regular"}
+	token := domain.APIToken{ID: primitive.NewObjectID(), UserID: user.ID, Scopes: []string{"tasks:read"}}
+
+	suite.mockinfra.On("HashAPIToken", rawAPIToken).Return("hashed")
+	suite.mockTokenRepo.On("FetchByHash", "hashed").Return(token, nil)
+	suite.mockUserRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockTokenRepo.On("UpdateLastUsed", token.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	authenticatedUser, authenticatedToken, err := suite.usecase.Authenticate(rawAPIToken)
+	suite.NoError(err)
+	suite.Equal(user, authenticatedUser)
+	suite.False(authenticatedToken.LastUsedAt.IsZero())
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestAuthenticateExpiredToken() {
+	token := domain.APIToken{ID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(-time.Hour)}
+
+	suite.mockinfra.On("HashAPIToken", rawAPIToken).Return("hashed")
+	suite.mockTokenRepo.On("FetchByHash", "hashed").Return(token, nil)
+
+	_, _, err := suite.usecase.Authenticate(rawAPIToken)
+	suite.Error(err)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestAuthenticateUnknownToken() {
+	suite.mockinfra.On("HashAPIToken", rawAPIToken).Return("hashed")
+	suite.mockTokenRepo.On("FetchByHash", "hashed").Return(domain.APIToken{}, errors.New("token not found"))
+
+	_, _, err := suite.usecase.Authenticate(rawAPIToken)
+	suite.Error(err)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenTestSuite) TestRevokeToken() {
+	userID := primitive.NewObjectID().Hex()
+	tokenID := primitive.NewObjectID().Hex()
+
+	suite.mockTokenRepo.On("Remove", userID, tokenID).Return(nil)
+
+	err := suite.usecase.Revoke(userID, tokenID)
+	suite.NoError(err)
+
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func TestAPITokenUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenTestSuite))
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+type AttachmentTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockStorage     *mocks.MockBlobStorage
+	usecase         usecases.AttachmentUsecase
+	tenantID        primitive.ObjectID
+	admin           domain.Actor
+	member          domain.Actor
+	task            domain.Task
+}
+
+func (suite *AttachmentTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockStorage = new(mocks.MockBlobStorage)
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	limits := usecases.AttachmentLimits{MaxBytes: 64, AllowedTypes: []string{"image/png", "text/markdown"}}
+	suite.usecase = *usecases.NewAttachmentUsecase(suite.mockTaskRepo, tasks, suite.mockStorage, limits)
+
+	suite.tenantID = primitive.NewObjectID()
+	suite.admin = domain.Actor{
+		UserID:           primitive.NewObjectID().Hex(),
+		Role:             "regular",
+		OrganizationID:   suite.tenantID.Hex(),
+		OrganizationRole: "admin",
+	}
+	suite.member = domain.Actor{
+		UserID:           primitive.NewObjectID().Hex(),
+		Role:             "regular",
+		OrganizationID:   suite.tenantID.Hex(),
+		OrganizationRole: "member",
+	}
+	suite.task = domain.Task{
+		ID:     primitive.NewObjectID(),
+		Status: "pending",
+		Attachments: []domain.Attachment{{
+			ID:          primitive.NewObjectID(),
+			Filename:    "spec.md",
+			ContentType: "text/markdown",
+			Size:        7,
+			StorageKey:  "stored/spec",
+		}},
+	}
+
+	suite.mockTaskRepo.On("Fetch", suite.tenantID.Hex(), suite.task.ID.Hex()).Return(suite.task, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenantID.Hex(), domain.TaskFilter{ParentID: suite.task.ID}).Return([]domain.Task{}, nil).Maybe()
+}
+
+func (suite *AttachmentTestSuite) TestUploadSniffsContentType() {
+	key := suite.tenantID.Hex() + "/" + suite.task.ID.Hex() + "/"
+
+	suite.mockStorage.On("Put", mock.MatchedBy(func(k string) bool { return strings.HasPrefix(k, key) }), pngHeader, int64(len(pngHeader)), "image/png").Return(nil)
+	suite.mockTaskRepo.On("AddAttachment", suite.tenantID.Hex(), suite.task.ID.Hex(), mock.MatchedBy(func(a domain.Attachment) bool {
+		return a.Filename == "screenshot.png" && a.ContentType == "image/png" && a.UploadedBy.Hex() == suite.admin.UserID
+	})).Return(suite.task, nil)
+
+	attachment, err := suite.usecase.Upload(suite.admin, suite.task.ID.Hex(), "../../screenshot.png", int64(len(pngHeader)), strings.NewReader(pngHeader))
+	suite.NoError(err)
+	suite.Equal("screenshot.png", attachment.Filename)
+	suite.Equal(key+attachment.ID.Hex(), attachment.StorageKey)
+
+	suite.mockStorage.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AttachmentTestSuite) TestUploadRefinesTextTypeByExtension() {
+	body := "# Spec\n"
+
+	suite.mockStorage.On("Put", mock.Anything, body, int64(len(body)), "text/markdown").Return(nil)
+	suite.mockTaskRepo.On("AddAttachment", suite.tenantID.Hex(), suite.task.ID.Hex(), mock.Anything).Return(suite.task, nil)
+
+	attachment, err := suite.usecase.Upload(suite.admin, suite.task.ID.Hex(), "spec.md", int64(len(body)), strings.NewReader(body))
+	suite.NoError(err)
+	suite.Equal("text/markdown", attachment.ContentType)
+}
+
+func (suite *AttachmentTestSuite) TestUploadRejectsDisallowedType() {
+	body := "<html><script>alert(1)</script></html>"
+
+	_, err := suite.usecase.Upload(suite.admin, suite.task.ID.Hex(), "image.png", int64(len(body)), strings.NewReader(body))
+	suite.EqualError(err, "file type text/html is not allowed")
+
+	suite.mockStorage.AssertNotCalled(suite.T(), "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AttachmentTestSuite) TestUploadRejectsOversizedFile() {
+	_, err := suite.usecase.Upload(suite.admin, suite.task.ID.Hex(), "big.png", 65, strings.NewReader(pngHeader))
+	suite.Error(err)
+
+	suite.mockStorage.AssertNotCalled(suite.T(), "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AttachmentTestSuite) TestUploadRequiresWriteAccess() {
+	_, err := suite.usecase.Upload(suite.member, suite.task.ID.Hex(), "screenshot.png", int64(len(pngHeader)), strings.NewReader(pngHeader))
+	suite.Error(err)
+
+	suite.mockStorage.AssertNotCalled(suite.T(), "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AttachmentTestSuite) TestUploadDeletesBlobWhenMetadataFails() {
+	suite.mockStorage.On("Put", mock.Anything, pngHeader, int64(len(pngHeader)), "image/png").Return(nil)
+	suite.mockTaskRepo.On("AddAttachment", suite.tenantID.Hex(), suite.task.ID.Hex(), mock.Anything).Return(domain.Task{}, errors.New("task not found"))
+	suite.mockStorage.On("Delete", mock.Anything).Return(nil)
+
+	_, err := suite.usecase.Upload(suite.admin, suite.task.ID.Hex(), "screenshot.png", int64(len(pngHeader)), strings.NewReader(pngHeader))
+	suite.Error(err)
+
+	suite.mockStorage.AssertExpectations(suite.T())
+}
+
+func (suite *AttachmentTestSuite) TestDownloadAllowsViewers() {
+	suite.mockStorage.On("Get", "stored/spec").Return(io.NopCloser(strings.NewReader("# Spec\n")), nil)
+
+	attachment, content, err := suite.usecase.Download(suite.member, suite.task.ID.Hex(), suite.task.Attachments[0].ID.Hex())
+	suite.NoError(err)
+	defer content.Close()
+
+	body, _ := io.ReadAll(content)
+	suite.Equal("spec.md", attachment.Filename)
+	suite.Equal("# Spec\n", string(body))
+}
+
+func (suite *AttachmentTestSuite) TestDownloadUnknownAttachment() {
+	_, _, err := suite.usecase.Download(suite.member, suite.task.ID.Hex(), primitive.NewObjectID().Hex())
+	suite.EqualError(err, "attachment not found")
+
+	suite.mockStorage.AssertNotCalled(suite.T(), "Get", mock.Anything)
+}
+
+func (suite *AttachmentTestSuite) TestRemoveDeletesMetadataAndBlob() {
+	attachmentID := suite.task.Attachments[0].ID.Hex()
+
+	suite.mockTaskRepo.On("RemoveAttachment", suite.tenantID.Hex(), suite.task.ID.Hex(), attachmentID).Return(suite.task, nil)
+	suite.mockStorage.On("Delete", "stored/spec").Return(nil)
+
+	err := suite.usecase.Remove(suite.admin, suite.task.ID.Hex(), attachmentID)
+	suite.NoError(err)
+
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+	suite.mockStorage.AssertExpectations(suite.T())
+}
+
+func TestAttachmentTestSuite(t *testing.T) {
+	suite.Run(t, new(AttachmentTestSuite))
+}
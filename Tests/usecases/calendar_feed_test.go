@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarFeedTestSuite struct {
+	suite.Suite
+	mockFeedRepo    *mocks.MockCalendarFeedRepo
+	mockUserRepo    *mocks.MockUserRepo
+	mockInfra       *mocks.MockInfrastructure
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	usecase         *usecases.CalendarFeedUsecase
+	tenantID        primitive.ObjectID
+	user            domain.User
+	feed            domain.CalendarFeed
+}
+
+func (suite *CalendarFeedTestSuite) SetupTest() {
+	suite.mockFeedRepo = new(mocks.MockCalendarFeedRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.mockInfra = new(mocks.MockInfrastructure)
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	suite.usecase = usecases.NewCalendarFeedUsecase(suite.mockFeedRepo, suite.mockUserRepo, suite.mockInfra, tasks)
+
+	suite.tenantID = primitive.NewObjectID()
+	suite.user = domain.User{ID: primitive.NewObjectID(), Role: "user", Memberships: []domain.Membership{{OrganizationID: suite.tenantID, Role: "admin"}}}
+	suite.feed = domain.CalendarFeed{ID: primitive.NewObjectID(), UserID: suite.user.ID, OrganizationID: suite.tenantID, Name: "Work, tasks", CreatedAt: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)}
+	suite.mockInfra.On("HashAPIToken", "tm_cal_secret").Return("hashed")
+	suite.mockFeedRepo.On("UpdateLastUsed", suite.feed.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+}
+
+func (suite *CalendarFeedTestSuite) TestCreateStoresOnlyTheTokenHash() {
+	actor := domain.Actor{UserID: suite.user.ID.Hex(), OrganizationID: suite.tenantID.Hex()}
+	suite.mockInfra.On("GenerateFeedToken").Return("tm_cal_secret", nil)
+	suite.mockFeedRepo.On("Create", mock.MatchedBy(func(feed *domain.CalendarFeed) bool {
+		return feed.TokenHash == "hashed" && feed.Prefix == "tm_cal_secre" && feed.Name == "Tasks" && feed.OrganizationID == suite.tenantID
+	})).Return(suite.feed, nil)
+
+	rawToken, feed, err := suite.usecase.Create(actor, " ")
+	suite.NoError(err)
+	suite.Equal("tm_cal_secret", rawToken)
+	suite.Equal(suite.feed.ID, feed.ID)
+}
+
+func (suite *CalendarFeedTestSuite) TestRenderMapsTasksToTodos() {
+	updated := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+	pending := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy; then verify", Description: "Line one\nLine two", Status: "in-progress", Priority: "urgent", DueDate: time.Date(2026, time.October, 20, 17, 0, 0, 0, time.UTC), CreatedAt: updated, UpdatedAt: updated}
+	completed := domain.Task{ID: primitive.NewObjectID(), Title: strings.Repeat("long ", 20), Status: "completed", Priority: "low", DueDate: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), CreatedAt: updated, UpdatedAt: updated.Add(time.Hour)}
+	suite.mockFeedRepo.On("FetchByHash", "hashed").Return(suite.feed, nil)
+	suite.mockUserRepo.On("Fetch", suite.user.ID.Hex()).Return(suite.user, nil)
+	suite.mockTaskRepo.On("Each", suite.tenantID.Hex(), domain.TaskFilter{Expression: &domain.TaskExpression{Op: "gt", Field: "due_date", Value: time.Time{}}}).Return([]domain.Task{pending, completed}, nil)
+
+	calendar, err := suite.usecase.Render("tm_cal_secret", "")
+	suite.NoError(err)
+
+	suite.Equal(updated.Add(time.Hour), calendar.LastModified)
+	suite.Len(calendar.ETag, 34)
+	suite.Contains(calendar.Body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	suite.Contains(calendar.Body, "X-WR-CALNAME:Work\\, tasks\r\n")
+	suite.Contains(calendar.Body, strings.Join([]string{
+		"BEGIN:VTODO",
+		"UID:" + pending.ID.Hex() + "@task-manager",
+		"DTSTAMP:20261018T093000Z",
+		"CREATED:20261018T093000Z",
+		"LAST-MODIFIED:20261018T093000Z",
+		`SUMMARY:Deploy\; then verify`,
+		"DESCRIPTION:Line one\\nLine two",
+		"PRIORITY:1",
+		"DUE:20261020T170000Z",
+		"STATUS:IN-PROCESS",
+		"END:VTODO",
+	}, "\r\n"))
+	suite.Contains(calendar.Body, "STATUS:COMPLETED\r\nCOMPLETED:20261018T103000Z\r\n")
+	suite.Contains(calendar.Body, "SUMMARY:"+strings.Repeat("long ", 13)+"lo\r\n ng long ")
+	suite.True(strings.HasSuffix(calendar.Body, "END:VCALENDAR\r\n"))
+
+	for _, line := range strings.Split(calendar.Body, "\r\n") {
+		suite.LessOrEqual(len(line), 75)
+	}
+}
+
+func (suite *CalendarFeedTestSuite) TestETagFollowsTaskUpdates() {
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy", Status: "pending", DueDate: time.Now(), UpdatedAt: time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)}
+	edited := task
+	edited.UpdatedAt = task.UpdatedAt.Add(time.Minute)
+	suite.mockFeedRepo.On("FetchByHash", "hashed").Return(suite.feed, nil)
+	suite.mockUserRepo.On("Fetch", suite.user.ID.Hex()).Return(suite.user, nil)
+	suite.mockTaskRepo.On("Each", suite.tenantID.Hex(), mock.Anything).Return([]domain.Task{task}, nil).Twice()
+	suite.mockTaskRepo.On("Each", suite.tenantID.Hex(), mock.Anything).Return([]domain.Task{edited}, nil).Once()
+
+	first, err := suite.usecase.Render("tm_cal_secret", "event")
+	suite.NoError(err)
+	again, err := suite.usecase.Render("tm_cal_secret", "event")
+	suite.NoError(err)
+	changed, err := suite.usecase.Render("tm_cal_secret", "event")
+	suite.NoError(err)
+
+	suite.Equal(first.ETag, again.ETag)
+	suite.NotEqual(first.ETag, changed.ETag)
+	suite.Contains(first.Body, "BEGIN:VEVENT\r\n")
+	suite.Contains(first.Body, "STATUS:CONFIRMED\r\n")
+	suite.NotContains(first.Body, "VTODO")
+}
+
+func (suite *CalendarFeedTestSuite) TestRenderRejectsRevokedFeedsAndFormerMembers() {
+	suite.mockInfra.On("HashAPIToken", "tm_cal_revoked").Return("revoked")
+	suite.mockFeedRepo.On("FetchByHash", "revoked").Return(domain.CalendarFeed{}, errors.New("feed not found"))
+	_, err := suite.usecase.Render("tm_cal_revoked", "")
+	suite.EqualError(err, "feed not found")
+
+	former := suite.user
+	former.Memberships = nil
+	suite.mockFeedRepo.On("FetchByHash", "hashed").Return(suite.feed, nil)
+	suite.mockUserRepo.On("Fetch", suite.user.ID.Hex()).Return(former, nil)
+	_, err = suite.usecase.Render("tm_cal_secret", "")
+	suite.EqualError(err, "feed not found")
+
+	_, err = suite.usecase.Render("tm_cal_secret", "journal")
+	suite.EqualError(err, "component must be todo or event")
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Each", mock.Anything, mock.Anything)
+}
+
+func TestCalendarFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarFeedTestSuite))
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommentTestSuite struct {
+	suite.Suite
+	mockCommentRepo *mocks.MockCommentRepo
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockUserRepo    *mocks.MockUserRepo
+	mockInfra       *mocks.MockInfrastructure
+	usecase         usecases.CommentUsecase
+	tenantID        primitive.ObjectID
+	author          domain.Actor
+	task            domain.Task
+}
+
+func (suite *CommentTestSuite) SetupTest() {
+	suite.mockCommentRepo = new(mocks.MockCommentRepo)
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.mockInfra = new(mocks.MockInfrastructure)
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	suite.usecase = *usecases.NewCommentUsecase(suite.mockCommentRepo, tasks, suite.mockUserRepo, suite.mockInfra)
+
+	suite.tenantID = primitive.NewObjectID()
+	suite.author = domain.Actor{
+		UserID:           primitive.NewObjectID().Hex(),
+		Role:             "regular",
+		OrganizationID:   suite.tenantID.Hex(),
+		OrganizationRole: "member",
+	}
+	suite.task = domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+
+	suite.mockTaskRepo.On("Fetch", suite.tenantID.Hex(), suite.task.ID.Hex()).Return(suite.task, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenantID.Hex(), domain.TaskFilter{ParentID: suite.task.ID}).Return([]domain.Task{}, nil)
+}
+
+func (suite *CommentTestSuite) TestCreateResolvesMentions() {
+	member := domain.User{
+		ID:          primitive.NewObjectID(),
+		Username:    "jesse",
+		Memberships: []domain.Membership{{OrganizationID: suite.tenantID, Role: "member"}},
+	}
+	outsider := domain.User{ID: primitive.NewObjectID(), Username: "gus"}
+	body := "@jesse and @gus please review, cc walt@example.com"
+
+	suite.mockUserRepo.On("FetchByUsername", "jesse").Return(member, nil)
+	suite.mockUserRepo.On("FetchByUsername", "gus").Return(outsider, nil)
+	suite.mockCommentRepo.On("Create", suite.tenantID.Hex(), mock.MatchedBy(func(c *domain.Comment) bool {
+		return c.AuthorID.Hex() == suite.author.UserID && c.TaskID == suite.task.ID &&
+			len(c.Mentions) == 1 && c.Mentions[0] == member.ID
+	})).Return(domain.Comment{Body: body}, nil)
+	suite.mockInfra.On("RenderMarkdown", body).Return("<p>rendered</p>")
+
+	comment, err := suite.usecase.Create(suite.author, suite.task.ID.Hex(), body)
+	suite.NoError(err)
+	suite.Equal("<p>rendered</p>", comment.BodyHTML)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockCommentRepo.AssertExpectations(suite.T())
+}
+
+func (suite *CommentTestSuite) TestCreateEmptyBody() {
+	_, err := suite.usecase.Create(suite.author, suite.task.ID.Hex(), "")
+	suite.Error(err)
+
+	suite.mockCommentRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *CommentTestSuite) TestUpdateByOtherUserIsRejected() {
+	comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, AuthorID: primitive.NewObjectID()}
+
+	suite.mockCommentRepo.On("Fetch", suite.tenantID.Hex(), comment.ID.Hex()).Return(comment, nil)
+
+	_, err := suite.usecase.Update(suite.author, suite.task.ID.Hex(), comment.ID.Hex(), "edited")
+	suite.Error(err)
+
+	suite.mockCommentRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommentTestSuite) TestRemoveByOrganizationAdmin() {
+	comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, AuthorID: primitive.NewObjectID()}
+	admin := suite.author
+	admin.OrganizationRole = "admin"
+
+	suite.mockCommentRepo.On("Fetch", suite.tenantID.Hex(), comment.ID.Hex()).Return(comment, nil)
+	suite.mockCommentRepo.On("Remove", suite.tenantID.Hex(), comment.ID.Hex()).Return(nil)
+
+	err := suite.usecase.Remove(admin, suite.task.ID.Hex(), comment.ID.Hex())
+	suite.NoError(err)
+
+	suite.mockCommentRepo.AssertExpectations(suite.T())
+}
+
+func (suite *CommentTestSuite) TestRemoveCommentOfAnotherTask() {
+	comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: primitive.NewObjectID()}
+	authorID, _ := primitive.ObjectIDFromHex(suite.author.UserID)
+	comment.AuthorID = authorID
+
+	suite.mockCommentRepo.On("Fetch", suite.tenantID.Hex(), comment.ID.Hex()).Return(comment, nil)
+
+	err := suite.usecase.Remove(suite.author, suite.task.ID.Hex(), comment.ID.Hex())
+	suite.Error(err)
+
+	suite.mockCommentRepo.AssertNotCalled(suite.T(), "Remove", mock.Anything, mock.Anything)
+}
+
+func (suite *CommentTestSuite) TestTaskDeletionRemovesItsComments() {
+	event := domain.Event{Type: domain.EventTaskDeleted, OrganizationID: suite.tenantID}
+	suite.mockCommentRepo.On("RemoveByTask", suite.tenantID.Hex(), suite.task.ID.Hex()).Return(nil)
+
+	err := suite.usecase.HandleTaskDeleted(event, suite.task)
+	suite.NoError(err)
+
+	suite.mockCommentRepo.AssertExpectations(suite.T())
+}
+
+func TestCommentUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CommentTestSuite))
+}
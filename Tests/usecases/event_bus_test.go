@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventBusTestSuite struct {
+	suite.Suite
+	mockOutboxRepo *mocks.MockOutboxRepo
+	bus            *usecases.EventBus
+	tenantID       primitive.ObjectID
+	now            time.Time
+}
+
+func (suite *EventBusTestSuite) SetupTest() {
+	suite.mockOutboxRepo = new(mocks.MockOutboxRepo)
+	suite.bus = usecases.NewEventBus(suite.mockOutboxRepo)
+	suite.tenantID = primitive.NewObjectID()
+	suite.now = time.Date(2026, time.May, 4, 8, 0, 0, 0, time.UTC)
+}
+
+func (suite *EventBusTestSuite) TestSyncSubscribersReceiveTypedPayload() {
+	suite.mockOutboxRepo.On("Append", mock.Anything).Return(nil)
+	var received domain.Task
+	suite.bus.Subscribe(domain.EventTaskCreated, usecases.Typed(func(event domain.Event, task domain.Task) error {
+		received = task
+		return nil
+	}))
+
+	err := suite.bus.Publish(suite.tenantID.Hex(), domain.EventTaskCreated, domain.Task{Title: "Ship"})
+	suite.NoError(err)
+	suite.Equal("Ship", received.Title)
+}
+
+func (suite *EventBusTestSuite) TestEventsArePersistedWithoutAsyncSubscribers() {
+	suite.mockOutboxRepo.On("Append", mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventTaskCreated && e.Status == "pending" && e.OrganizationID == suite.tenantID
+	})).Return(nil)
+
+	err := suite.bus.Publish(suite.tenantID.Hex(), domain.EventTaskCreated, domain.Task{Title: "Ship"})
+	suite.NoError(err)
+
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *EventBusTestSuite) TestSyncSubscriberPanicIsIsolated() {
+	suite.mockOutboxRepo.On("Append", mock.Anything).Return(nil)
+	calls := 0
+	suite.bus.Subscribe("*", func(event domain.Event) error {
+		panic("boom")
+	})
+	suite.bus.Subscribe(domain.EventTaskDeleted, func(event domain.Event) error {
+		calls++
+		return nil
+	})
+
+	err := suite.bus.Publish(suite.tenantID.Hex(), domain.EventTaskDeleted, domain.Task{})
+	suite.EqualError(err, "task.deleted handler panicked: boom")
+	suite.Equal(1, calls)
+}
+
+func (suite *EventBusTestSuite) TestAsyncSubscribersGoThroughTheOutbox() {
+	suite.bus.SubscribeAsync("audit", domain.EventUserRegistered, func(event domain.Event) error {
+		return nil
+	})
+	suite.mockOutboxRepo.On("Append", mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventUserRegistered && e.Status == "pending" && e.OrganizationID.IsZero() && e.Payload == `{"username":"abebe"}`
+	})).Return(nil)
+
+	err := suite.bus.Publish("", domain.EventUserRegistered, map[string]string{"username": "abebe"})
+	suite.NoError(err)
+
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *EventBusTestSuite) expectClaim(event domain.Event) {
+	suite.mockOutboxRepo.On("ClaimPending", suite.now, time.Minute).Return(event, true, nil).Once()
+	suite.mockOutboxRepo.On("ClaimPending", suite.now, time.Minute).Return(domain.Event{}, false, nil).Once()
+}
+
+func (suite *EventBusTestSuite) TestRelayCompletesHandledEvents() {
+	event := domain.Event{ID: primitive.NewObjectID(), Type: domain.EventTaskCreated, Payload: `{}`}
+	delivered := 0
+	suite.bus.SubscribeAsync("webhooks", "*", func(event domain.Event) error {
+		delivered++
+		return nil
+	})
+
+	suite.expectClaim(event)
+	suite.mockOutboxRepo.On("MarkHandled", event.ID.Hex(), "webhooks").Return(nil)
+	suite.mockOutboxRepo.On("Complete", event.ID.Hex(), suite.now).Return(nil)
+
+	err := suite.bus.RelayPending(suite.now)
+	suite.NoError(err)
+	suite.Equal(1, delivered)
+
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *EventBusTestSuite) TestRelayRetriesOnlyFailedSubscribers() {
+	event := domain.Event{ID: primitive.NewObjectID(), Type: domain.EventTaskCreated, Payload: `{}`, Handled: []string{"audit"}, Attempts: 2}
+	suite.bus.SubscribeAsync("audit", "*", func(event domain.Event) error {
+		suite.Fail("audit already handled this event")
+		return nil
+	})
+	suite.bus.SubscribeAsync("webhooks", domain.EventTaskCreated, func(event domain.Event) error {
+		panic("webhook store down")
+	})
+
+	suite.expectClaim(event)
+	suite.mockOutboxRepo.On("Reschedule", event.ID.Hex(), "pending", 3, suite.now.Add(time.Second*20), "webhooks: task.created handler panicked: webhook store down").Return(nil)
+
+	err := suite.bus.RelayPending(suite.now)
+	suite.Error(err)
+
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+	suite.mockOutboxRepo.AssertNotCalled(suite.T(), "Complete", mock.Anything, mock.Anything)
+}
+
+func (suite *EventBusTestSuite) TestRelayGivesUpAfterMaxAttempts() {
+	event := domain.Event{ID: primitive.NewObjectID(), Type: domain.EventTaskCreated, Payload: `{}`, Attempts: 9}
+	suite.bus.SubscribeAsync("webhooks", "*", func(event domain.Event) error {
+		return errors.New("unavailable")
+	})
+
+	suite.expectClaim(event)
+	suite.mockOutboxRepo.On("Reschedule", event.ID.Hex(), "failed", 10, suite.now, "webhooks: unavailable").Return(nil)
+
+	err := suite.bus.RelayPending(suite.now)
+	suite.EqualError(err, "webhooks: unavailable")
+
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *EventBusTestSuite) TestTaskUsecasePublishesThroughTheBus() {
+	mockTaskRepo := new(mocks.MockTaskRepo)
+	tasks := usecases.NewTaskUsecase(mockTaskRepo, new(mocks.MockProjectRepo), nil, suite.bus)
+	admin := domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenantID.Hex()}
+	existing := domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+
+	var removed domain.Task
+	suite.bus.Subscribe(domain.EventTaskDeleted, usecases.Typed(func(event domain.Event, task domain.Task) error {
+		removed = task
+		return nil
+	}))
+
+	mockTaskRepo.On("Fetch", suite.tenantID.Hex(), existing.ID.Hex()).Return(existing, nil)
+	mockTaskRepo.On("FetchAll", suite.tenantID.Hex(), domain.TaskFilter{ParentID: existing.ID}).Return([]domain.Task{}, nil)
+	mockTaskRepo.On("Remove", suite.tenantID.Hex(), existing.ID.Hex()).Return(nil)
+	suite.mockOutboxRepo.On("Append", mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventTaskDeleted
+	})).Return(nil)
+
+	err := tasks.Remove(admin, existing.ID.Hex())
+	suite.NoError(err)
+	suite.Equal(existing.ID, removed.ID)
+}
+
+func (suite *EventBusTestSuite) TestTaskUsecaseFailsWhenTheEventCannotBeRecorded() {
+	mockTaskRepo := new(mocks.MockTaskRepo)
+	tasks := usecases.NewTaskUsecase(mockTaskRepo, new(mocks.MockProjectRepo), nil, suite.bus)
+	admin := domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenantID.Hex()}
+	existing := domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+
+	dispatched := false
+	suite.bus.Subscribe(domain.EventTaskDeleted, func(event domain.Event) error {
+		dispatched = true
+		return nil
+	})
+
+	mockTaskRepo.On("Fetch", suite.tenantID.Hex(), existing.ID.Hex()).Return(existing, nil)
+	mockTaskRepo.On("FetchAll", suite.tenantID.Hex(), domain.TaskFilter{ParentID: existing.ID}).Return([]domain.Task{}, nil)
+	mockTaskRepo.On("Remove", suite.tenantID.Hex(), existing.ID.Hex()).Return(nil)
+	suite.mockOutboxRepo.On("Append", mock.Anything).Return(errors.New("cannot insert event to outbox"))
+
+	err := tasks.Remove(admin, existing.ID.Hex())
+	suite.EqualError(err, "cannot insert event to outbox")
+	suite.False(dispatched)
+}
+
+func TestEventBusTestSuite(t *testing.T) {
+	suite.Run(t, new(EventBusTestSuite))
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LabelTestSuite struct {
+	suite.Suite
+	mockLabelRepo   *mocks.MockLabelRepo
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	usecase         usecases.LabelUsecase
+	tenant          string
+	admin           domain.Actor
+	member          domain.Actor
+}
+
+func (suite *LabelTestSuite) SetupTest() {
+	suite.mockLabelRepo = new(mocks.MockLabelRepo)
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	suite.usecase = *usecases.NewLabelUsecase(suite.mockLabelRepo, suite.mockTaskRepo, tasks)
+
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "admin"}
+	suite.member = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "member"}
+}
+
+func (suite *LabelTestSuite) TestCreate() {
+	suite.mockLabelRepo.On("Create", suite.tenant, mock.MatchedBy(func(l *domain.Label) bool {
+		return l.Name == "bug" && l.Color == "#d73a4a"
+	})).Return(domain.Label{Name: "bug", Color: "#d73a4a"}, nil)
+
+	label, err := suite.usecase.Create(suite.admin, &domain.Label{Name: " bug ", Color: "#D73A4A"})
+	suite.NoError(err)
+	suite.Equal("bug", label.Name)
+
+	suite.mockLabelRepo.AssertExpectations(suite.T())
+}
+
+func (suite *LabelTestSuite) TestCreateInvalidColor() {
+	_, err := suite.usecase.Create(suite.admin, &domain.Label{Name: "bug", Color: "red"})
+	suite.Error(err)
+
+	suite.mockLabelRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *LabelTestSuite) TestCreateByMemberIsRejected() {
+	_, err := suite.usecase.Create(suite.member, &domain.Label{Name: "bug", Color: "#d73a4a"})
+	suite.EqualError(err, "only admins can manage labels")
+
+	suite.mockLabelRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *LabelTestSuite) TestRemoveUnassignsFromTasks() {
+	label := domain.Label{ID: primitive.NewObjectID(), Name: "bug"}
+
+	suite.mockLabelRepo.On("Fetch", suite.tenant, label.ID.Hex()).Return(label, nil)
+	suite.mockLabelRepo.On("Remove", suite.tenant, label.ID.Hex()).Return(nil)
+	suite.mockTaskRepo.On("RemoveLabelFromAll", suite.tenant, label.ID).Return(nil)
+
+	err := suite.usecase.Remove(suite.admin, label.ID.Hex())
+	suite.NoError(err)
+
+	suite.mockLabelRepo.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *LabelTestSuite) TestAssign() {
+	task := domain.Task{ID: primitive.NewObjectID()}
+	label := domain.Label{ID: primitive.NewObjectID(), Name: "bug"}
+
+	suite.mockTaskRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockLabelRepo.On("Fetch", suite.tenant, label.ID.Hex()).Return(label, nil)
+	suite.mockTaskRepo.On("AddLabel", suite.tenant, task.ID.Hex(), label.ID).Return(domain.Task{ID: task.ID, Labels: []primitive.ObjectID{label.ID}}, nil)
+
+	updatedTask, err := suite.usecase.Assign(suite.admin, task.ID.Hex(), label.ID.Hex())
+	suite.NoError(err)
+	suite.Equal([]primitive.ObjectID{label.ID}, updatedTask.Labels)
+
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *LabelTestSuite) TestAssignUnknownLabel() {
+	task := domain.Task{ID: primitive.NewObjectID()}
+	labelID := primitive.NewObjectID().Hex()
+
+	suite.mockTaskRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockLabelRepo.On("Fetch", suite.tenant, labelID).Return(domain.Label{}, errors.New("label not found"))
+
+	_, err := suite.usecase.Assign(suite.admin, task.ID.Hex(), labelID)
+	suite.Error(err)
+
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "AddLabel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LabelTestSuite) TestUnassignRequiresWriteAccess() {
+	task := domain.Task{ID: primitive.NewObjectID(), Labels: []primitive.ObjectID{primitive.NewObjectID()}}
+
+	suite.mockTaskRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+
+	_, err := suite.usecase.Unassign(suite.member, task.ID.Hex(), task.Labels[0].Hex())
+	suite.Error(err)
+
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "RemoveLabel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLabelTestSuite(t *testing.T) {
+	suite.Run(t, new(LabelTestSuite))
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OIDCTestSuite struct {
+	suite.Suite
+	mockProvider *mocks.MockOIDCProvider
+	mockRepo     *mocks.MockUserRepo
+	mockinfra    *mocks.MockInfrastructure
+	usecase      usecases.OIDCUsecase
+}
+
+func (suite *OIDCTestSuite) SetupTest() {
+	suite.mockProvider = new(mocks.MockOIDCProvider)
+	suite.mockRepo = new(mocks.MockUserRepo)
+	suite.mockinfra = new(mocks.MockInfrastructure)
+	suite.usecase = *usecases.NewOIDCUsecase(suite.mockProvider, suite.mockRepo, suite.mockinfra, nil)
+}
+
+func (suite *OIDCTestSuite) TestCallbackProvisionsNewUser() {
+	identity := domain.OIDCIdentity{Issuer: "https://idp", Subject: "sub-1", Email: "jesse@example.com", Username: "jesse", Role: "regular"}
+
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(identity, nil)
+	suite.mockRepo.On("FetchByExternalIdentity", "https://idp", "sub-1").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("FetchByUsername", "jesse").Return(domain.User{Username: "jesse"}, nil)
+	suite.mockRepo.On("FetchByUsername", "jesse1").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("Register", mock.MatchedBy(func(user *domain.User) bool {
+		return user.Username == "jesse1" && user.Role == "regular" && user.Password == "" &&
+			len(user.ExternalIdentities) == 1 && user.ExternalIdentities[0].Subject == "sub-1"
+	})).Return(domain.User{Username: "jesse1", Role: "regular"}, nil)
+	suite.mockinfra.On("GenerateJwtToken", mock.Anything).Return("jwt_token", nil)
+
+	result, err := suite.usecase.Callback("session", "state", "code")
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+
+	suite.mockProvider.AssertExpectations(suite.T())
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCTestSuite) TestCallbackSanitizesProvisionedUsername() {
+	identity := domain.OIDCIdentity{Issuer: "https://idp", Subject: "sub-1", Username: "Jesse Smith Q. Longname-From-The-Directory", Role: "regular"}
+
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(identity, nil)
+	suite.mockRepo.On("FetchByExternalIdentity", "https://idp", "sub-1").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("FetchByUsername", "JesseSmithQ.Longname-From-The-Di").Return(domain.User{Username: "JesseSmithQ.Longname-From-The-Di"}, nil)
+	suite.mockRepo.On("FetchByUsername", "JesseSmithQ.Longname-From-The-D1").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("Register", mock.MatchedBy(func(user *domain.User) bool {
+		return user.Username == "JesseSmithQ.Longname-From-The-D1"
+	})).Return(domain.User{Username: "JesseSmithQ.Longname-From-The-D1", Role: "regular"}, nil)
+	suite.mockinfra.On("GenerateJwtToken", mock.Anything).Return("jwt_token", nil)
+
+	_, err := suite.usecase.Callback("session", "state", "code")
+	suite.NoError(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCTestSuite) TestCallbackFallsBackWhenUsernameIsTooShort() {
+	identity := domain.OIDCIdentity{Issuer: "https://idp", Subject: "sub-1", Email: "j@example.com", Username: "J ", Role: "regular"}
+
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(identity, nil)
+	suite.mockRepo.On("FetchByExternalIdentity", "https://idp", "sub-1").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("FetchByUsername", "user").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("Register", mock.MatchedBy(func(user *domain.User) bool {
+		return user.Username == "user"
+	})).Return(domain.User{Username: "user", Role: "regular"}, nil)
+	suite.mockinfra.On("GenerateJwtToken", mock.Anything).Return("jwt_token", nil)
+
+	_, err := suite.usecase.Callback("session", "state", "code")
+	suite.NoError(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCTestSuite) TestCallbackSyncsMappedRole() {
+	user := domain.User{ID: primitive.NewObjectID(), Username: "jesse", Role: "regular"}
+	identity := domain.OIDCIdentity{Issuer: "https://idp", Subject: "sub-1", Role: "admin"}
+
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(identity, nil)
+	suite.mockRepo.On("FetchByExternalIdentity", "https://idp", "sub-1").Return(user, nil)
+	suite.mockRepo.On("UpdateRole", user.ID.Hex(), "admin").Return(nil)
+	suite.mockinfra.On("GenerateJwtToken", mock.MatchedBy(func(u *domain.User) bool {
+		return u.Role == "admin"
+	})).Return("jwt_token", nil)
+
+	result, err := suite.usecase.Callback("session", "state", "code")
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+
+	suite.mockProvider.AssertExpectations(suite.T())
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCTestSuite) TestCallbackRequiresTwoFactorWhenEnabled() {
+	user := domain.User{ID: primitive.NewObjectID(), Username: "jesse", Role: "regular", TwoFactorEnabled: true}
+	identity := domain.OIDCIdentity{Issuer: "https://idp", Subject: "sub-1"}
+
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(identity, nil)
+	suite.mockRepo.On("FetchByExternalIdentity", "https://idp", "sub-1").Return(user, nil)
+	suite.mockinfra.On("GenerateChallengeToken", &user).Return("challenge", nil)
+
+	result, err := suite.usecase.Callback("session", "state", "code")
+	suite.NoError(err)
+	suite.True(result.TwoFactorRequired)
+	suite.Equal("challenge", result.Challenge)
+
+	suite.mockProvider.AssertExpectations(suite.T())
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCTestSuite) TestCallbackProviderFailure() {
+	suite.mockProvider.On("CompleteAuth", "session", "state", "code").Return(domain.OIDCIdentity{}, errors.New("state mismatch"))
+
+	result, err := suite.usecase.Callback("session", "state", "code")
+	suite.Error(err)
+	suite.Equal(domain.LoginResult{}, result)
+
+	suite.mockProvider.AssertExpectations(suite.T())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func TestOIDCUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrganizationTestSuite struct {
+	suite.Suite
+	mockOrganizationRepo *mocks.MockOrganizationRepo
+	mockUserRepo         *mocks.MockUserRepo
+	usecase              usecases.OrganizationUsecase
+	tenantID             primitive.ObjectID
+	admin                domain.User
+	orgAdmin             domain.Actor
+}
+
+func (suite *OrganizationTestSuite) SetupTest() {
+	suite.mockOrganizationRepo = new(mocks.MockOrganizationRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.usecase = *usecases.NewOrganizationUsecase(suite.mockOrganizationRepo, suite.mockUserRepo)
+
+	suite.tenantID = primitive.NewObjectID()
+	suite.admin = domain.User{
+		ID:          primitive.NewObjectID(),
+		Role:        "regular",
+		Memberships: []domain.Membership{{OrganizationID: suite.tenantID, Role: "admin"}},
+	}
+	suite.orgAdmin = domain.Actor{
+		UserID:           suite.admin.ID.Hex(),
+		Role:             "regular",
+		OrganizationID:   suite.tenantID.Hex(),
+		OrganizationRole: "admin",
+	}
+}
+
+func (suite *OrganizationTestSuite) TestCreateMakesCreatorOrganizationAdmin() {
+	organization := domain.Organization{ID: suite.tenantID, Name: "Finance"}
+
+	suite.mockOrganizationRepo.On("Create", mock.AnythingOfType("*domain.Organization")).Return(organization, nil)
+	suite.mockUserRepo.On("SetMembership", suite.admin.ID.Hex(), domain.Membership{OrganizationID: suite.tenantID, Role: "admin"}).Return(nil)
+
+	createdOrganization, err := suite.usecase.Create(domain.Actor{UserID: suite.admin.ID.Hex(), Role: "regular"}, &domain.Organization{Name: "Finance"})
+	suite.NoError(err)
+	suite.Equal(organization, createdOrganization)
+
+	suite.mockOrganizationRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestResolveTenantDefaultsToOnlyMembership() {
+	suite.mockUserRepo.On("Fetch", suite.admin.ID.Hex()).Return(suite.admin, nil)
+
+	tenantID, role, err := suite.usecase.ResolveTenant(suite.admin.ID.Hex(), "regular", "")
+	suite.NoError(err)
+	suite.Equal(suite.tenantID.Hex(), tenantID)
+	suite.Equal("admin", role)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestResolveTenantRejectsOtherOrganizations() {
+	suite.mockUserRepo.On("Fetch", suite.admin.ID.Hex()).Return(suite.admin, nil)
+
+	_, _, err := suite.usecase.ResolveTenant(suite.admin.ID.Hex(), "regular", primitive.NewObjectID().Hex())
+	suite.Error(err)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestResolveTenantRequiresSelectionForSeveralMemberships() {
+	suite.admin.Memberships = append(suite.admin.Memberships, domain.Membership{OrganizationID: primitive.NewObjectID(), Role: "member"})
+
+	suite.mockUserRepo.On("Fetch", suite.admin.ID.Hex()).Return(suite.admin, nil)
+
+	_, _, err := suite.usecase.ResolveTenant(suite.admin.ID.Hex(), "regular", "")
+	suite.Error(err)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestResolveTenantForGlobalAdmin() {
+	requested := primitive.NewObjectID().Hex()
+
+	suite.mockOrganizationRepo.On("Fetch", requested).Return(domain.Organization{}, nil)
+
+	tenantID, role, err := suite.usecase.ResolveTenant(primitive.NewObjectID().Hex(), "admin", requested)
+	suite.NoError(err)
+	suite.Equal(requested, tenantID)
+	suite.Equal("admin", role)
+
+	suite.mockOrganizationRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestSetMemberInvalidRole() {
+	_, err := suite.usecase.SetMember(suite.orgAdmin, primitive.NewObjectID().Hex(), "owner")
+	suite.Error(err)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestSetMember() {
+	user := domain.User{ID: primitive.NewObjectID()}
+	membership := domain.Membership{OrganizationID: suite.tenantID, Role: "member"}
+
+	suite.mockUserRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockUserRepo.On("FetchMember", suite.tenantID.Hex(), user.ID.Hex()).Return(domain.User{}, errors.New("user not found")).Once()
+	suite.mockUserRepo.On("SetMembership", user.ID.Hex(), membership).Return(nil)
+	suite.mockUserRepo.On("FetchMember", suite.tenantID.Hex(), user.ID.Hex()).Return(user, nil).Once()
+
+	_, err := suite.usecase.SetMember(suite.orgAdmin, user.ID.Hex(), "member")
+	suite.NoError(err)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OrganizationTestSuite) TestRemoveLastAdminIsRejected() {
+	suite.mockUserRepo.On("FetchMember", suite.tenantID.Hex(), suite.admin.ID.Hex()).Return(suite.admin, nil)
+	suite.mockUserRepo.On("FetchAll", suite.tenantID.Hex()).Return([]domain.User{suite.admin}, nil)
+
+	err := suite.usecase.RemoveMember(suite.orgAdmin, suite.admin.ID.Hex())
+	suite.Error(err)
+
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func TestOrganizationUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationTestSuite))
+}
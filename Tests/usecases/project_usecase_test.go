@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectTestSuite struct {
+	suite.Suite
+	mockProjectRepo *mocks.MockProjectRepo
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockUserRepo    *mocks.MockUserRepo
+	usecase         usecases.ProjectUsecase
+	tenant          string
+	ownerID         primitive.ObjectID
+	owner           domain.Actor
+	project         domain.Project
+}
+
+func (suite *ProjectTestSuite) SetupTest() {
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.usecase = *usecases.NewProjectUsecase(suite.mockProjectRepo, suite.mockTaskRepo, suite.mockUserRepo)
+
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.ownerID = primitive.NewObjectID()
+	suite.owner = domain.Actor{UserID: suite.ownerID.Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "member"}
+	suite.project = domain.Project{
+		ID:      primitive.NewObjectID(),
+		Name:    "Platform",
+		Members: []domain.ProjectMember{{UserID: suite.ownerID, Role: "owner"}},
+	}
+}
+
+func (suite *ProjectTestSuite) TestCreateMakesCreatorOwner() {
+	project := &domain.Project{Name: "Platform"}
+
+	suite.mockProjectRepo.On("Create", suite.tenant, mock.MatchedBy(func(p *domain.Project) bool {
+		return len(p.Members) == 1 && p.Members[0].UserID == suite.ownerID && p.Members[0].Role == "owner"
+	})).Return(suite.project, nil)
+
+	createdProject, err := suite.usecase.Create(suite.owner, project)
+	suite.NoError(err)
+	suite.Equal(suite.project.ID, createdProject.ID)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestCreateMissingName() {
+	_, err := suite.usecase.Create(suite.owner, &domain.Project{})
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestFetchAllForRegularUserUsesMembership() {
+	suite.mockProjectRepo.On("FetchByMember", suite.tenant, suite.owner.UserID).Return([]domain.Project{suite.project}, nil)
+
+	projects, err := suite.usecase.FetchAll(suite.owner)
+	suite.NoError(err)
+	suite.Len(projects, 1)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestFetchByNonMemberIsHidden() {
+	outsider := domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+
+	_, err := suite.usecase.Fetch(outsider, suite.project.ID.Hex())
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestSetMember() {
+	member := domain.ProjectMember{UserID: primitive.NewObjectID(), Role: "editor"}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+	suite.mockUserRepo.On("FetchMember", suite.tenant, member.UserID.Hex()).Return(domain.User{ID: member.UserID}, nil)
+	suite.mockProjectRepo.On("UpdateMembers", suite.tenant, suite.project.ID.Hex(), append(suite.project.Members, member)).Return(suite.project, nil)
+
+	_, err := suite.usecase.SetMember(suite.owner, suite.project.ID.Hex(), member)
+	suite.NoError(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestSetMemberRequiresOwner() {
+	editorID := primitive.NewObjectID()
+	suite.project.Members = append(suite.project.Members, domain.ProjectMember{UserID: editorID, Role: "editor"})
+	editor := domain.Actor{UserID: editorID.Hex(), Role: "regular", OrganizationID: suite.tenant}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+
+	_, err := suite.usecase.SetMember(editor, suite.project.ID.Hex(), domain.ProjectMember{UserID: primitive.NewObjectID(), Role: "viewer"})
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestRemoveLastOwnerIsRejected() {
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+
+	_, err := suite.usecase.RemoveMember(suite.owner, suite.project.ID.Hex(), suite.ownerID.Hex())
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestRemoveProjectWithTasksIsRejected() {
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ProjectID: suite.project.ID}).Return([]domain.Task{{ID: primitive.NewObjectID()}}, nil)
+
+	err := suite.usecase.Remove(suite.owner, suite.project.ID.Hex())
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestFetchTasks() {
+	tasks := []domain.Task{{ID: primitive.NewObjectID(), ProjectID: suite.project.ID}}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(suite.project, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ProjectID: suite.project.ID}).Return(tasks, nil)
+
+	fetchedTasks, err := suite.usecase.FetchTasks(suite.owner, suite.project.ID.Hex())
+	suite.NoError(err)
+	suite.Equal(tasks, fetchedTasks)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProjectTestSuite) TestFetchTasksUnknownProject() {
+	suite.mockProjectRepo.On("Fetch", suite.tenant, suite.project.ID.Hex()).Return(domain.Project{}, errors.New("project not found"))
+
+	_, err := suite.usecase.FetchTasks(suite.owner, suite.project.ID.Hex())
+	suite.Error(err)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func TestProjectUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectTestSuite))
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecurrenceTestSuite struct {
+	suite.Suite
+	mockRepo        *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	usecase         usecases.TaskUsecase
+	tenant          string
+	admin           domain.Actor
+}
+
+func (suite *RecurrenceTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.usecase = *usecases.NewTaskUsecase(suite.mockRepo, suite.mockProjectRepo, nil, nil)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+}
+
+func (suite *RecurrenceTestSuite) next(rule string, start time.Time, after time.Time) time.Time {
+	parsed, err := usecases.ParseRecurrenceRule(rule)
+	suite.Require().NoError(err)
+
+	next, ok := parsed.Next(start, after)
+	suite.Require().True(ok)
+	return next
+}
+
+func (suite *RecurrenceTestSuite) TestParseRejectsUnsupportedRules() {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := usecases.ParseRecurrenceRule(rule)
+		suite.Error(err, rule)
+	}
+}
+
+func (suite *RecurrenceTestSuite) TestDailyInterval() {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	next := suite.next("FREQ=DAILY;INTERVAL=3", start, start)
+	suite.Equal(time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC), next)
+}
+
+func (suite *RecurrenceTestSuite) TestWeeklyOnGivenWeekdays() {
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	suite.Equal(time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), suite.next("FREQ=WEEKLY;BYDAY=MO,WE", start, start))
+	suite.Equal(time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC), suite.next("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", start, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC)))
+}
+
+func (suite *RecurrenceTestSuite) TestMonthlyByDay() {
+	start := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	suite.Equal(time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC), suite.next("FREQ=MONTHLY", start, start))
+	suite.Equal(time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), suite.next("FREQ=MONTHLY;BYMONTHDAY=-1", start, start))
+	suite.Equal(time.Date(2025, 2, 15, 9, 0, 0, 0, time.UTC), suite.next("FREQ=MONTHLY;BYMONTHDAY=1,15", start, time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)))
+}
+
+func (suite *RecurrenceTestSuite) TestUntilEndsSeries() {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	rule, err := usecases.ParseRecurrenceRule("FREQ=DAILY;UNTIL=20250302")
+	suite.Require().NoError(err)
+
+	_, ok := rule.Next(start, time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC))
+	suite.False(ok)
+}
+
+func (suite *RecurrenceTestSuite) TestCreateStartsSeries() {
+	due := time.Now().Add(24 * time.Hour)
+	task := &domain.Task{
+		Title:       "Weekly report",
+		Description: "Send the report",
+		DueDate:     due,
+		Status:      "pending",
+		Recurrence:  &domain.Recurrence{Rule: "FREQ=WEEKLY"},
+	}
+
+	suite.mockRepo.On("Create", suite.tenant, mock.MatchedBy(func(t *domain.Task) bool {
+		return !t.SeriesID.IsZero() && t.Recurrence.Start.Equal(due)
+	})).Return(*task, nil)
+
+	_, err := suite.usecase.Create(suite.admin, task)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RecurrenceTestSuite) TestCompletingSpawnsNextOccurrence() {
+	start := time.Now().Add(-2 * time.Hour)
+	task := domain.Task{
+		ID:         primitive.NewObjectID(),
+		Title:      "Daily standup notes",
+		DueDate:    start,
+		Status:     "pending",
+		Priority:   "high",
+		Checklist:  []domain.ChecklistItem{{ID: primitive.NewObjectID(), Title: "Post", Done: true}},
+		Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY", Start: start},
+		SeriesID:   primitive.NewObjectID(),
+	}
+	completed := task
+	completed.Status = "completed"
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("Update", suite.tenant, task.ID.Hex(), mock.Anything).Return(completed, nil)
+	suite.mockRepo.On("ClaimNextOccurrence", suite.tenant, task.ID.Hex()).Return(true, nil)
+	suite.mockRepo.On("Create", suite.tenant, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.SeriesID == task.SeriesID && t.Status == "pending" && t.Priority == "high" &&
+			t.DueDate.Equal(start.Add(24*time.Hour)) && len(t.Checklist) == 1 && !t.Checklist[0].Done
+	})).Return(domain.Task{}, nil)
+
+	_, err := suite.usecase.Update(suite.admin, task.ID.Hex(), domain.Task{Status: "completed"})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RecurrenceTestSuite) TestRollOverSkipsAlreadyClaimedOccurrences() {
+	now := time.Now()
+	task := domain.Task{
+		ID:             primitive.NewObjectID(),
+		OrganizationID: primitive.NewObjectID(),
+		DueDate:        now.Add(-time.Hour),
+		Status:         "pending",
+		Recurrence:     &domain.Recurrence{Rule: "FREQ=DAILY", Start: now.Add(-time.Hour)},
+		SeriesID:       primitive.NewObjectID(),
+	}
+
+	suite.mockRepo.On("FetchRecurringDue", now).Return([]domain.Task{task}, nil)
+	suite.mockRepo.On("ClaimNextOccurrence", task.OrganizationID.Hex(), task.ID.Hex()).Return(false, nil)
+
+	err := suite.usecase.RollOverRecurring(now)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *RecurrenceTestSuite) TestUpdateSeriesTargetsOpenOccurrences() {
+	start := time.Now()
+	task := domain.Task{
+		ID:         primitive.NewObjectID(),
+		Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY", Start: start},
+		SeriesID:   primitive.NewObjectID(),
+	}
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("UpdateSeries", suite.tenant, domain.TaskFilter{
+		SeriesID: task.SeriesID,
+		Statuses: []string{"pending", "in-progress"},
+	}, domain.Task{
+		Title:      "Renamed",
+		Recurrence: &domain.Recurrence{Rule: "FREQ=WEEKLY", Start: start},
+	}).Return(nil)
+
+	_, err := suite.usecase.UpdateSeries(suite.admin, task.ID.Hex(), domain.Task{
+		Title:      "Renamed",
+		Recurrence: &domain.Recurrence{Rule: "FREQ=WEEKLY"},
+	})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func TestRecurrenceTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceTestSuite))
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReminderTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockUserRepo    *mocks.MockUserRepo
+	mockLockRepo    *mocks.MockLockRepo
+	mockNotifier    *mocks.MockNotifier
+	now             time.Time
+	tenantID        primitive.ObjectID
+	tenant          string
+	config          usecases.ReminderConfig
+}
+
+func (suite *ReminderTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockUserRepo = new(mocks.MockUserRepo)
+	suite.mockLockRepo = new(mocks.MockLockRepo)
+	suite.mockNotifier = new(mocks.MockNotifier)
+
+	suite.now = time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+	suite.tenantID = primitive.NewObjectID()
+	suite.tenant = suite.tenantID.Hex()
+	suite.config = usecases.ReminderConfig{
+		Interval:      time.Minute,
+		DueSoonWindow: time.Hour * 24,
+		Owner:         "replica-a",
+		Now:           func() time.Time { return suite.now },
+	}
+}
+
+func (suite *ReminderTestSuite) scheduler() *usecases.ReminderScheduler {
+	return usecases.NewReminderScheduler(suite.mockTaskRepo, suite.mockProjectRepo, suite.mockUserRepo, suite.mockLockRepo, suite.mockNotifier, suite.config)
+}
+
+func (suite *ReminderTestSuite) expectLeader(leader bool) {
+	suite.mockLockRepo.On("Acquire", "task_reminders", "replica-a", suite.now, time.Minute*3).Return(leader, nil)
+}
+
+func (suite *ReminderTestSuite) TestFollowerDoesNothing() {
+	suite.expectLeader(false)
+
+	err := suite.scheduler().RunOnce()
+	suite.NoError(err)
+
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "FetchPendingReminders", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockNotifier.AssertNotCalled(suite.T(), "Notify", mock.Anything)
+}
+
+func (suite *ReminderTestSuite) TestDueSoonNotifiesOptedInProjectMembers() {
+	member := domain.User{ID: primitive.NewObjectID(), Username: "member", NotificationPreferences: domain.NotificationPreferences{EmailOnDueSoon: true}}
+	optedOut := domain.User{ID: primitive.NewObjectID(), Username: "quiet"}
+	outsider := domain.User{ID: primitive.NewObjectID(), Username: "outsider", NotificationPreferences: domain.NotificationPreferences{EmailOnDueSoon: true}}
+	project := domain.Project{ID: primitive.NewObjectID(), Members: []domain.ProjectMember{
+		{UserID: member.ID, Role: "owner"},
+		{UserID: optedOut.ID, Role: "member"},
+	}}
+	task := domain.Task{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, ProjectID: project.ID, Title: "Ship", DueDate: suite.now.Add(time.Hour * 3)}
+
+	suite.expectLeader(true)
+	suite.mockTaskRepo.On("FetchPendingReminders", "due_soon", suite.now, suite.now.Add(time.Hour*24)).Return([]domain.Task{task}, nil)
+	suite.mockTaskRepo.On("FetchPendingReminders", "overdue", time.Time{}, suite.now).Return([]domain.Task{}, nil)
+	suite.mockUserRepo.On("FetchAll", suite.tenant).Return([]domain.User{member, optedOut, outsider}, nil)
+	suite.mockProjectRepo.On("Fetch", suite.tenant, project.ID.Hex()).Return(project, nil)
+	suite.mockNotifier.On("Notify", domain.Notification{
+		Kind:       "due_soon",
+		Task:       task,
+		Recipients: []domain.User{member},
+		CreatedAt:  suite.now,
+	}).Return(nil)
+	suite.mockTaskRepo.On("MarkReminded", suite.tenant, task.ID.Hex(), "due_soon").Return(nil)
+
+	err := suite.scheduler().RunOnce()
+	suite.NoError(err)
+
+	suite.mockNotifier.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ReminderTestSuite) TestOverdueIsFlaggedWhenEnabled() {
+	suite.config.AutoFlagOverdue = true
+	suite.config.DueSoonWindow = 0
+	admin := domain.User{ID: primitive.NewObjectID(), NotificationPreferences: domain.NotificationPreferences{EmailOnOverdue: true}}
+	task := domain.Task{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, DueDate: suite.now.Add(-time.Hour)}
+
+	suite.expectLeader(true)
+	suite.mockTaskRepo.On("FetchPendingReminders", "overdue", time.Time{}, suite.now).Return([]domain.Task{task}, nil)
+	suite.mockTaskRepo.On("FlagOverdue", suite.tenant, task.ID.Hex()).Return(nil)
+	suite.mockUserRepo.On("FetchAll", suite.tenant).Return([]domain.User{admin}, nil)
+	suite.mockNotifier.On("Notify", mock.MatchedBy(func(n domain.Notification) bool {
+		return n.Kind == "overdue" && n.Task.Overdue && len(n.Recipients) == 1
+	})).Return(nil)
+	suite.mockTaskRepo.On("MarkReminded", suite.tenant, task.ID.Hex(), "overdue").Return(nil)
+
+	err := suite.scheduler().RunOnce()
+	suite.NoError(err)
+
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "FetchPendingReminders", "due_soon", mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderTestSuite) TestOverdueIsNotFlaggedByDefault() {
+	suite.config.DueSoonWindow = 0
+	task := domain.Task{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, DueDate: suite.now.Add(-time.Hour)}
+
+	suite.expectLeader(true)
+	suite.mockTaskRepo.On("FetchPendingReminders", "overdue", time.Time{}, suite.now).Return([]domain.Task{task}, nil)
+	suite.mockUserRepo.On("FetchAll", suite.tenant).Return([]domain.User{}, nil)
+	suite.mockNotifier.On("Notify", mock.Anything).Return(nil)
+	suite.mockTaskRepo.On("MarkReminded", suite.tenant, task.ID.Hex(), "overdue").Return(nil)
+
+	err := suite.scheduler().RunOnce()
+	suite.NoError(err)
+
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "FlagOverdue", mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderTestSuite) TestFailedNotificationIsRetried() {
+	suite.config.DueSoonWindow = 0
+	task := domain.Task{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, DueDate: suite.now.Add(-time.Hour)}
+
+	suite.expectLeader(true)
+	suite.mockTaskRepo.On("FetchPendingReminders", "overdue", time.Time{}, suite.now).Return([]domain.Task{task}, nil)
+	suite.mockUserRepo.On("FetchAll", suite.tenant).Return([]domain.User{}, nil)
+	suite.mockNotifier.On("Notify", mock.Anything).Return(errors.New("smtp unavailable"))
+
+	err := suite.scheduler().RunOnce()
+	suite.EqualError(err, "smtp unavailable")
+
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "MarkReminded", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderTestSuite) TestClockDrivesTheWindow() {
+	suite.now = suite.now.Add(time.Hour * 48)
+
+	suite.expectLeader(true)
+	suite.mockTaskRepo.On("FetchPendingReminders", "due_soon", suite.now, suite.now.Add(time.Hour*24)).Return([]domain.Task{}, nil)
+	suite.mockTaskRepo.On("FetchPendingReminders", "overdue", time.Time{}, suite.now).Return([]domain.Task{}, nil)
+
+	err := suite.scheduler().RunOnce()
+	suite.NoError(err)
+
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func TestReminderTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderTestSuite))
+}
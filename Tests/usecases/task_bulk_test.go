@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskBulkTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockTxRepo      *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockLabelRepo   *mocks.MockLabelRepo
+	mockEvents      *mocks.MockEventPublisher
+	mockTransactor  *mocks.MockTaskTransactor
+	usecase         *usecases.TaskBulkUsecase
+	tenant          string
+	admin           domain.Actor
+}
+
+func (suite *TaskBulkTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockTxRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockLabelRepo = new(mocks.MockLabelRepo)
+	suite.mockEvents = new(mocks.MockEventPublisher)
+	suite.mockTransactor = &mocks.MockTaskTransactor{Tasks: suite.mockTxRepo}
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, suite.mockLabelRepo, suite.mockEvents)
+	suite.usecase = usecases.NewTaskBulkUsecase(suite.mockTransactor, tasks)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+	suite.mockLabelRepo.On("FetchAll", suite.tenant).Return([]domain.Label{}, nil)
+}
+
+func (suite *TaskBulkTestSuite) pendingTask() domain.Task {
+	return domain.Task{ID: primitive.NewObjectID(), Title: "Deploy", Status: "pending", Priority: "medium"}
+}
+
+func (suite *TaskBulkTestSuite) statuses(response domain.TaskBulkResponse) []string {
+	statuses := []string{}
+	for _, result := range response.Results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func (suite *TaskBulkTestSuite) TestBestEffortReportsEachOperation() {
+	created := suite.pendingTask()
+	removed := suite.pendingTask()
+	suite.mockTaskRepo.On("Create", suite.tenant, mock.AnythingOfType("*domain.Task")).Return(created, nil)
+	suite.mockTaskRepo.On("Fetch", suite.tenant, removed.ID.Hex()).Return(removed, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: removed.ID}).Return([]domain.Task{}, nil)
+	suite.mockTaskRepo.On("Remove", suite.tenant, removed.ID.Hex()).Return(nil)
+	suite.mockEvents.On("Publish", suite.tenant, mock.Anything, mock.Anything).Return(nil)
+
+	response, err := suite.usecase.Apply(suite.admin, domain.TaskBulkRequest{Mode: "best_effort", Operations: []domain.TaskBulkOperation{
+		{Op: "create", Task: domain.Task{Title: "Deploy", Description: "Ship it", DueDate: time.Now().Add(time.Hour), Status: "pending"}},
+		{Op: "create", Task: domain.Task{Title: "Incomplete"}},
+		{Op: "delete", ID: removed.ID.Hex()},
+		{Op: "archive", ID: removed.ID.Hex()},
+	}})
+	suite.NoError(err)
+
+	suite.True(response.Committed)
+	suite.Equal([]string{"succeeded", "failed", "succeeded", "failed"}, suite.statuses(response))
+	suite.Equal(created.ID.Hex(), response.Results[0].ID)
+	suite.Equal("missing required fields", response.Results[1].Error)
+	suite.Equal("op must be create, update or delete", response.Results[3].Error)
+	suite.Equal(2, response.Succeeded)
+	suite.Equal(2, response.Failed)
+	suite.mockTransactor.AssertNotCalled(suite.T(), "WithTransaction")
+}
+
+func (suite *TaskBulkTestSuite) TestAtomicRollsBackOnFirstFailure() {
+	first := suite.pendingTask()
+	second := suite.pendingTask()
+	suite.mockTransactor.On("WithTransaction").Return(nil)
+	suite.mockTxRepo.On("Fetch", suite.tenant, first.ID.Hex()).Return(first, nil)
+	suite.mockTxRepo.On("Update", suite.tenant, first.ID.Hex(), domain.Task{Status: "in-progress"}).Return(first, nil)
+
+	response, err := suite.usecase.Apply(suite.admin, domain.TaskBulkRequest{Operations: []domain.TaskBulkOperation{
+		{Op: "update", ID: first.ID.Hex(), Task: domain.Task{Status: "in-progress"}},
+		{Op: "update", ID: second.ID.Hex(), Task: domain.Task{Status: "done"}},
+		{Op: "delete", ID: second.ID.Hex()},
+	}})
+	suite.NoError(err)
+
+	suite.Equal("atomic", response.Mode)
+	suite.False(response.Committed)
+	suite.Equal([]string{"rolled_back", "failed", "skipped"}, suite.statuses(response))
+	suite.Nil(response.Results[0].Task)
+	suite.Equal("invalid task status value", response.Results[1].Error)
+	suite.Equal(0, response.Succeeded)
+	suite.Equal(1, response.Failed)
+	suite.mockEvents.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskBulkTestSuite) TestAtomicPublishesEventsAfterCommit() {
+	task := suite.pendingTask()
+	updated := task
+	updated.Status = "completed"
+	suite.mockTransactor.On("WithTransaction").Return(nil)
+	suite.mockTxRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockTxRepo.On("Update", suite.tenant, task.ID.Hex(), domain.Task{Status: "completed"}).Return(updated, nil)
+	suite.mockEvents.On("Publish", suite.tenant, domain.EventTaskUpdated, updated).Return(nil).Once()
+	suite.mockEvents.On("Publish", suite.tenant, domain.EventTaskStatusChanged, domain.TaskStatusChange{Task: updated, PreviousStatus: "pending"}).Return(nil).Once()
+
+	response, err := suite.usecase.Apply(suite.admin, domain.TaskBulkRequest{Mode: "atomic", Operations: []domain.TaskBulkOperation{
+		{Op: "update", ID: task.ID.Hex(), Task: domain.Task{Status: "completed"}},
+	}})
+	suite.NoError(err)
+
+	suite.True(response.Committed)
+	suite.Equal([]string{"succeeded"}, suite.statuses(response))
+	suite.mockEvents.AssertExpectations(suite.T())
+}
+
+func (suite *TaskBulkTestSuite) TestAtomicReportsTransactionErrors() {
+	suite.mockTransactor.On("WithTransaction").Return(errors.New("atomic bulk operations need a MongoDB replica set, use best_effort mode instead"))
+
+	_, err := suite.usecase.Apply(suite.admin, domain.TaskBulkRequest{Operations: []domain.TaskBulkOperation{{Op: "delete", ID: primitive.NewObjectID().Hex()}}})
+	suite.EqualError(err, "atomic bulk operations need a MongoDB replica set, use best_effort mode instead")
+}
+
+func (suite *TaskBulkTestSuite) TestFilterAndPatchUpdatesMatchingTasks() {
+	first := suite.pendingTask()
+	second := suite.pendingTask()
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Expression != nil
+	})).Return([]domain.Task{first, second}, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.ParentIDs != nil
+	})).Return([]domain.Task{}, nil)
+	for _, task := range []domain.Task{first, second} {
+		suite.mockTaskRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+		suite.mockTaskRepo.On("Update", suite.tenant, task.ID.Hex(), domain.Task{Status: "pending", Priority: "high"}).Return(task, nil).Once()
+	}
+	suite.mockEvents.On("Publish", suite.tenant, domain.EventTaskUpdated, mock.Anything).Return(nil)
+
+	response, err := suite.usecase.Apply(suite.admin, domain.TaskBulkRequest{
+		Mode:   "best_effort",
+		Filter: &domain.TaskQuery{Expression: "status:pending"},
+		Patch:  &domain.Task{Priority: "high"},
+	})
+	suite.NoError(err)
+
+	suite.Equal([]string{"succeeded", "succeeded"}, suite.statuses(response))
+	suite.Equal(first.ID.Hex(), response.Results[0].ID)
+	suite.Equal(second.ID.Hex(), response.Results[1].ID)
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskBulkTestSuite) TestRejectsInvalidRequests() {
+	operations := make([]domain.TaskBulkOperation, 101)
+	cases := map[string]domain.TaskBulkRequest{
+		"mode must be atomic or best_effort":                {Mode: "eventually", Operations: operations[:1]},
+		"send operations or a filter with a patch":          {},
+		"at most 100 operations can be sent at once":        {Operations: operations},
+		"operations cannot be combined with a filter":       {Operations: operations[:1], Filter: &domain.TaskQuery{Expression: "status:pending"}, Patch: &domain.Task{}},
+		"a filter needs a patch and a patch needs a filter": {Filter: &domain.TaskQuery{Expression: "status:pending"}},
+		"filter needs labels or a query":                    {Filter: &domain.TaskQuery{}, Patch: &domain.Task{Priority: "high"}},
+	}
+
+	for message, request := range cases {
+		_, err := suite.usecase.Apply(suite.admin, request)
+		suite.EqualError(err, message)
+	}
+	suite.mockTransactor.AssertNotCalled(suite.T(), "WithTransaction")
+}
+
+func TestTaskBulkTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskBulkTestSuite))
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskQueryTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockLabelRepo   *mocks.MockLabelRepo
+	usecase         *usecases.TaskUsecase
+	tenantID        primitive.ObjectID
+	tenant          string
+	admin           domain.Actor
+	infra           domain.Label
+}
+
+func (suite *TaskQueryTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockLabelRepo = new(mocks.MockLabelRepo)
+	suite.usecase = usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, suite.mockLabelRepo, nil)
+	suite.tenantID = primitive.NewObjectID()
+	suite.tenant = suite.tenantID.Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+	suite.infra = domain.Label{ID: primitive.NewObjectID(), Name: "Infra"}
+	suite.mockLabelRepo.On("FetchAll", suite.tenant).Return([]domain.Label{suite.infra}, nil)
+}
+
+func (suite *TaskQueryTestSuite) expression(query string) (*domain.TaskExpression, error) {
+	var captured *domain.TaskExpression
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		captured = filter.Expression
+		return filter.Expression != nil
+	})).Return([]domain.Task{}, nil).Once()
+
+	_, err := suite.usecase.FetchAll(suite.admin, domain.TaskQuery{Expression: query})
+	return captured, err
+}
+
+func (suite *TaskQueryTestSuite) TestParsesBooleanExpression() {
+	expression, err := suite.expression(`status:pending AND due<2026-11-01 AND (label:infra OR title~"deploy \"now\"")`)
+	suite.NoError(err)
+
+	day := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	suite.Equal(domain.TaskExpression{Op: "and", Children: []domain.TaskExpression{
+		{Op: "eq", Field: "status", Value: "pending"},
+		{Op: "and", Children: []domain.TaskExpression{
+			{Op: "gt", Field: "due_date", Value: time.Time{}},
+			{Op: "lt", Field: "due_date", Value: day},
+		}},
+		{Op: "or", Children: []domain.TaskExpression{
+			{Op: "eq", Field: "labels", Value: suite.infra.ID},
+			{Op: "contains", Field: "title", Value: `deploy "now"`},
+		}},
+	}}, *expression)
+}
+
+func (suite *TaskQueryTestSuite) TestImplicitAndNegationAndPriorityOrder() {
+	expression, err := suite.expression(`priority>=high -project:none NOT overdue:false`)
+	suite.NoError(err)
+
+	suite.Equal(domain.TaskExpression{Op: "and", Children: []domain.TaskExpression{
+		{Op: "in", Field: "priority", Value: []string{"high", "urgent"}},
+		{Op: "not", Children: []domain.TaskExpression{{Op: "exists", Field: "project_id", Value: false}}},
+		{Op: "not", Children: []domain.TaskExpression{{Op: "ne", Field: "overdue", Value: true}}},
+	}}, *expression)
+}
+
+func (suite *TaskQueryTestSuite) TestReportsPositionedErrors() {
+	cases := map[string]string{
+		`status:done`:                       `invalid status "done" at position 8`,
+		`colour:red`:                        `unknown field "colour" at position 1`,
+		`status pending`:                    `expected an operator after "status" at position 8`,
+		`status<pending`:                    `operator "<" is not supported for status at position 7`,
+		`(status:pending OR due>2026-01-01`: `unclosed parenthesis at position 1`,
+		`due>2026-13-01`:                    `invalid date "2026-13-01", use YYYY-MM-DD or RFC 3339 at position 5`,
+		`status:pending)`:                   `unexpected ")" at position 15`,
+		`title~"deploy`:                     `unterminated string at position 7`,
+		`label:billing`:                     `unknown label "billing" at position 7`,
+		`status:pending AND`:                `expected a condition at position 19`,
+	}
+
+	for query, message := range cases {
+		_, err := suite.usecase.FetchAll(suite.admin, domain.TaskQuery{Expression: query})
+		suite.EqualError(err, message, query)
+	}
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "FetchAll", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskQueryTestSuite) TestInMemoryPredicateMatchesTheSameTasks() {
+	due := time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC)
+	matching := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy gateway", Status: "pending", DueDate: due, Labels: []primitive.ObjectID{suite.infra.ID}}
+	late := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy docs", Status: "pending", DueDate: due.AddDate(0, 1, 0)}
+	undated := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy later", Status: "pending"}
+	done := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy api", Status: "completed", DueDate: due}
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{matching, late, undated, done}, nil)
+
+	expression, err := suite.expression(`status:pending AND due<2026-11-01 AND (label:infra OR title~"DOCS")`)
+	suite.NoError(err)
+
+	index := usecases.NewTaskSearchIndex(suite.mockTaskRepo)
+	results, err := index.Search(suite.tenant, domain.TaskFilter{Expression: expression}, domain.TaskSearch{Terms: []string{"deploy"}}, 10)
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal(matching.ID, results[0].Task.ID)
+}
+
+func TestTaskQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskQueryTestSuite))
+}
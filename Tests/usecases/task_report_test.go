@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskReportTestSuite struct {
+	suite.Suite
+	mockTaskRepo     *mocks.MockTaskRepo
+	mockProjectRepo  *mocks.MockProjectRepo
+	mockTaskReporter *mocks.MockTaskReporter
+	tasks            *usecases.TaskUsecase
+	usecase          *usecases.TaskReportUsecase
+	tenant           string
+	admin            domain.Actor
+	member           domain.Actor
+}
+
+func (suite *TaskReportTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockTaskReporter = new(mocks.MockTaskReporter)
+	suite.tasks = usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	suite.usecase = usecases.NewTaskReportUsecase(suite.mockTaskReporter, suite.tasks)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+	suite.member = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "member"}
+}
+
+func (suite *TaskReportTestSuite) TestAggregatorMatchesThePipelineSemantics() {
+	alice := primitive.NewObjectID()
+	bob := primitive.NewObjectID()
+	day := func(month time.Month, day int, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	tasks := []domain.Task{
+		{ID: primitive.NewObjectID(), Status: "completed", CreatedBy: alice, DueDate: day(time.September, 3, 0), CreatedAt: day(time.September, 1, 0), CompletedAt: day(time.September, 2, 12)},
+		{ID: primitive.NewObjectID(), Status: "completed", CreatedBy: alice, DueDate: day(time.September, 9, 0), CreatedAt: day(time.September, 7, 0), CompletedAt: day(time.September, 8, 0)},
+		{ID: primitive.NewObjectID(), Status: "pending", CreatedBy: bob, DueDate: day(time.January, 1, 0), CreatedAt: day(time.January, 1, 0)},
+		{ID: primitive.NewObjectID(), Status: "completed", CreatedBy: bob, DueDate: day(time.July, 1, 0), CreatedAt: day(time.June, 1, 0), CompletedAt: day(time.July, 1, 0)},
+		{ID: primitive.NewObjectID(), Status: "in-progress", DueDate: time.Now().Add(48 * time.Hour), CreatedAt: day(time.September, 10, 0)},
+		{ID: primitive.NewObjectID(), Status: "completed", CreatedBy: bob, DueDate: day(time.September, 5, 0), CreatedAt: day(time.September, 4, 0)},
+	}
+	suite.mockTaskRepo.On("Each", suite.tenant, domain.TaskFilter{}).Return(tasks, nil)
+	usecase := usecases.NewTaskReportUsecase(usecases.NewTaskReportAggregator(suite.mockTaskRepo), suite.tasks)
+
+	report, err := usecase.Report(suite.admin, domain.TaskQuery{}, "2026-09-01", "2026-09-14", "")
+	suite.NoError(err)
+
+	suite.Equal(day(time.September, 1, 0), report.From)
+	suite.Equal(day(time.September, 15, 0), report.To)
+	suite.Equal("week", report.Bucket)
+	suite.Equal(6, report.Total)
+	suite.Equal(map[string]int{"pending": 1, "in-progress": 1, "completed": 4, "canceled": 0}, report.ByStatus)
+	suite.Equal(1, report.Overdue)
+	suite.Equal([]domain.TaskReportBucket{
+		{Start: day(time.August, 31, 0), Completed: 1},
+		{Start: day(time.September, 7, 0), Completed: 1},
+		{Start: day(time.September, 14, 0), Completed: 0},
+	}, report.Throughput)
+	suite.Equal(domain.TaskCycleTime{Completed: 2, AverageHours: 30}, report.CycleTime)
+	suite.Equal([]domain.TaskUserReport{
+		{UserID: alice, Total: 2, Completed: 2, AverageCycleHours: 30},
+		{UserID: bob, Total: 3, Overdue: 1},
+	}, report.Users)
+}
+
+func (suite *TaskReportTestSuite) TestReportsOnlyVisibleTasksAndFillsGaps() {
+	project := domain.Project{ID: primitive.NewObjectID()}
+	suite.mockProjectRepo.On("FetchByMember", suite.tenant, suite.member.UserID).Return([]domain.Project{project}, nil)
+	filter := domain.TaskFilter{Restricted: true, VisibleProjectIDs: []primitive.ObjectID{project.ID}}
+	first := primitive.NewObjectID()
+	second := primitive.NewObjectID()
+	suite.mockTaskReporter.On("Report", suite.tenant, filter, mock.MatchedBy(func(query domain.TaskReportQuery) bool {
+		return query.Bucket == "day" && query.To.Sub(query.From) == 30*24*time.Hour && time.Since(query.Now) < time.Minute
+	})).Return(domain.TaskReport{
+		ByStatus:   map[string]int{"completed": 2, "pending": 1},
+		Throughput: []domain.TaskReportBucket{{Start: time.Now().UTC().Truncate(24 * time.Hour), Completed: 2}},
+		CycleTime:  domain.TaskCycleTime{Completed: 2, AverageHours: 10.0 / 3},
+		Users: []domain.TaskUserReport{
+			{UserID: first, Total: 1},
+			{UserID: second, Total: 2, Completed: 2, AverageCycleHours: 10.0 / 3},
+		},
+	}, nil)
+
+	report, err := suite.usecase.Report(suite.member, domain.TaskQuery{}, "", "", "day")
+	suite.NoError(err)
+
+	suite.Equal(3, report.Total)
+	suite.Equal(0, report.ByStatus["canceled"])
+	suite.Len(report.Throughput, 31)
+	suite.Equal(2, report.Throughput[30].Completed)
+	suite.Equal(0, report.Throughput[0].Completed)
+	suite.Equal(3.33, report.CycleTime.AverageHours)
+	suite.Equal(second, report.Users[0].UserID)
+	suite.Equal(3.33, report.Users[0].AverageCycleHours)
+}
+
+func (suite *TaskReportTestSuite) TestRejectsInvalidRanges() {
+	_, err := suite.usecase.Report(suite.admin, domain.TaskQuery{}, "", "", "year")
+	suite.EqualError(err, "bucket must be day, week or month")
+
+	_, err = suite.usecase.Report(suite.admin, domain.TaskQuery{}, "2026-10-01", "2026-09-01", "")
+	suite.EqualError(err, "from must be before to")
+
+	_, err = suite.usecase.Report(suite.admin, domain.TaskQuery{}, "2024-01-01", "2026-01-01", "day")
+	suite.EqualError(err, "the range spans more than 366 buckets, use a larger bucket")
+
+	_, err = suite.usecase.Report(suite.admin, domain.TaskQuery{}, "yesterday", "", "")
+	suite.EqualError(err, `invalid from "yesterday", use YYYY-MM-DD or RFC 3339`)
+
+	suite.mockTaskReporter.AssertNotCalled(suite.T(), "Report", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskReportTestSuite) TestReporterErrorsAreReturned() {
+	suite.mockTaskReporter.On("Report", suite.tenant, domain.TaskFilter{}, mock.Anything).Return(domain.TaskReport{}, errors.New("cannot build task report"))
+
+	_, err := suite.usecase.Report(suite.admin, domain.TaskQuery{}, "", "", "month")
+	suite.EqualError(err, "cannot build task report")
+}
+
+func (suite *TaskReportTestSuite) TestCreateRecordsCreatorAndCompletion() {
+	task := &domain.Task{Title: "Ship", Description: "Ship it", DueDate: time.Now(), Status: "completed", CreatedBy: primitive.NewObjectID(), CompletedAt: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	suite.mockTaskRepo.On("Create", suite.tenant, mock.AnythingOfType("*domain.Task")).Return(domain.Task{}, nil)
+
+	_, err := suite.tasks.Create(suite.admin, task)
+	suite.NoError(err)
+
+	suite.Equal(suite.admin.UserID, task.CreatedBy.Hex())
+	suite.Equal(task.CreatedAt, task.StatusChangedAt)
+	suite.Equal(task.CreatedAt, task.CompletedAt)
+}
+
+func TestTaskReportTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskReportTestSuite))
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskSearchTestSuite struct {
+	suite.Suite
+	mockTaskRepo     *mocks.MockTaskRepo
+	mockProjectRepo  *mocks.MockProjectRepo
+	mockTaskSearcher *mocks.MockTaskSearcher
+	usecase          *usecases.TaskSearchUsecase
+	tenantID         primitive.ObjectID
+	tenant           string
+	admin            domain.Actor
+}
+
+func (suite *TaskSearchTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockTaskSearcher = new(mocks.MockTaskSearcher)
+	suite.usecase = usecases.NewTaskSearchUsecase(suite.mockTaskSearcher, usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil))
+	suite.tenantID = primitive.NewObjectID()
+	suite.tenant = suite.tenantID.Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+}
+
+func (suite *TaskSearchTestSuite) TestParsesPhrasesAndNegation() {
+	expected := domain.TaskSearch{
+		Terms:    []string{"deploy", "api"},
+		Phrases:  []string{"release notes"},
+		Excluded: []string{"staging", "old docs"},
+	}
+	suite.mockTaskSearcher.On("Search", suite.tenant, domain.TaskFilter{}, expected, 20).Return([]domain.TaskSearchResult{}, nil)
+
+	_, err := suite.usecase.Search(suite.admin, `deploy "release   notes" -staging api -"old docs"`, "", 0)
+	suite.NoError(err)
+
+	suite.mockTaskSearcher.AssertExpectations(suite.T())
+}
+
+func (suite *TaskSearchTestSuite) TestRejectsQueriesWithoutPositiveTerms() {
+	_, err := suite.usecase.Search(suite.admin, `-staging ""`, "", 0)
+	suite.EqualError(err, "search query needs at least one term")
+
+	_, err = suite.usecase.Search(suite.admin, "deploy", "", 101)
+	suite.EqualError(err, "limit must be between 1 and 100")
+
+	suite.mockTaskSearcher.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskSearchTestSuite) TestUsesFetchAllVisibility() {
+	member := domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant}
+	project := domain.Project{ID: primitive.NewObjectID()}
+	suite.mockProjectRepo.On("FetchByMember", suite.tenant, member.UserID).Return([]domain.Project{project}, nil)
+	suite.mockTaskSearcher.On("Search", suite.tenant, domain.TaskFilter{Restricted: true, VisibleProjectIDs: []primitive.ObjectID{project.ID}}, mock.Anything, 5).Return([]domain.TaskSearchResult{}, nil)
+
+	_, err := suite.usecase.Search(member, "deploy", "", 5)
+	suite.NoError(err)
+
+	suite.mockTaskSearcher.AssertExpectations(suite.T())
+}
+
+func (suite *TaskSearchTestSuite) TestHighlightsMatches() {
+	task := domain.Task{
+		Title:       "Deploy <api> gateway",
+		Description: "Nothing to see here for a while. Before we can ship anything the team has to write the release notes and then deploying the gateway happens on friday after lunch",
+	}
+	suite.mockTaskSearcher.On("Search", suite.tenant, domain.TaskFilter{}, mock.Anything, 20).Return([]domain.TaskSearchResult{{Task: task, Score: 2}}, nil)
+
+	results, err := suite.usecase.Search(suite.admin, `deploy "release notes"`, "", 0)
+	suite.NoError(err)
+	suite.Equal("<mark>Deploy</mark> &lt;api&gt; gateway", results[0].Highlights["title"])
+	suite.Equal("…Before we can ship anything the team has to write the <mark>release</mark> <mark>notes</mark> and then <mark>deploying</mark> the gateway happens on friday…", results[0].Highlights["description"])
+}
+
+func (suite *TaskSearchTestSuite) TestInvertedIndexRanksAndFilters() {
+	index := usecases.NewTaskSearchIndex(suite.mockTaskRepo)
+	hidden := primitive.NewObjectID()
+	visible := primitive.NewObjectID()
+	titleHit := domain.Task{ID: primitive.NewObjectID(), ProjectID: visible, Title: "Deploy gateway"}
+	bodyHit := domain.Task{ID: primitive.NewObjectID(), Title: "Gateway", Description: "deploy after the release notes are out"}
+	staging := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy staging"}
+	secret := domain.Task{ID: primitive.NewObjectID(), ProjectID: hidden, Title: "Deploy secret"}
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{titleHit, bodyHit, staging, secret}, nil).Once()
+
+	filter := domain.TaskFilter{Restricted: true, VisibleProjectIDs: []primitive.ObjectID{visible}}
+	results, err := index.Search(suite.tenant, filter, domain.TaskSearch{Terms: []string{"deploy"}, Excluded: []string{"staging"}}, 10)
+	suite.NoError(err)
+	suite.Len(results, 2)
+	suite.Equal(titleHit.ID, results[0].Task.ID)
+	suite.Equal(bodyHit.ID, results[1].Task.ID)
+	suite.Greater(results[0].Score, results[1].Score)
+
+	results, err = index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Phrases: []string{"release notes"}}, 10)
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal(bodyHit.ID, results[0].Task.ID)
+
+	results, err = index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Phrases: []string{"notes release"}}, 10)
+	suite.NoError(err)
+	suite.Empty(results)
+
+	suite.mockTaskRepo.AssertNumberOfCalls(suite.T(), "FetchAll", 1)
+}
+
+func (suite *TaskSearchTestSuite) TestInvertedIndexFollowsTaskEvents() {
+	index := usecases.NewTaskSearchIndex(suite.mockTaskRepo)
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Write docs"}
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{task}, nil).Once()
+
+	_, err := index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Terms: []string{"docs"}}, 10)
+	suite.NoError(err)
+
+	publish := func(eventType string, task domain.Task) {
+		payload, _ := json.Marshal(task)
+		suite.NoError(index.HandleEvent(domain.Event{Type: eventType, OrganizationID: suite.tenantID, Payload: string(payload)}))
+	}
+
+	task.Title = "Write runbook"
+	publish(domain.EventTaskUpdated, task)
+	results, err := index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Terms: []string{"docs"}}, 10)
+	suite.NoError(err)
+	suite.Empty(results)
+
+	results, err = index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Terms: []string{"runbook"}}, 10)
+	suite.NoError(err)
+	suite.Len(results, 1)
+
+	publish(domain.EventTaskDeleted, task)
+	results, err = index.Search(suite.tenant, domain.TaskFilter{}, domain.TaskSearch{Terms: []string{"runbook"}}, 10)
+	suite.NoError(err)
+	suite.Empty(results)
+}
+
+func TestTaskSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskSearchTestSuite))
+}
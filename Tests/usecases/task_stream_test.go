@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskStreamTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	hub             *usecases.TaskChangeHub
+	stream          *usecases.TaskStreamUsecase
+	tenantID        primitive.ObjectID
+	tenant          string
+}
+
+func (suite *TaskStreamTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.hub = usecases.NewTaskChangeHub()
+	suite.stream = usecases.NewTaskStreamUsecase(suite.hub, usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil))
+	suite.tenantID = primitive.NewObjectID()
+	suite.tenant = suite.tenantID.Hex()
+}
+
+func (suite *TaskStreamTestSuite) publish(tenantID primitive.ObjectID, eventType string, task domain.Task) {
+	payload, _ := json.Marshal(task)
+	err := suite.hub.HandleEvent(domain.Event{Type: eventType, OrganizationID: tenantID, Payload: string(payload)})
+	suite.NoError(err)
+}
+
+func (suite *TaskStreamTestSuite) receive(changes <-chan domain.TaskChange) domain.TaskChange {
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(time.Second):
+		suite.FailNow("no task change received")
+		return domain.TaskChange{}
+	}
+}
+
+func (suite *TaskStreamTestSuite) TestHubDeliversOnlyTenantTaskChanges() {
+	changes, cancel, err := suite.hub.Subscribe(suite.tenant, "")
+	suite.NoError(err)
+	defer cancel()
+
+	suite.publish(primitive.NewObjectID(), domain.EventTaskCreated, domain.Task{Title: "Other tenant"})
+	suite.publish(suite.tenantID, domain.EventTaskStatusChanged, domain.Task{Title: "Ignored"})
+	suite.publish(suite.tenantID, domain.EventTaskCreated, domain.Task{Title: "Ship"})
+
+	change := suite.receive(changes)
+	suite.Equal(domain.EventTaskCreated, change.Type)
+	suite.Equal("Ship", change.Task.Title)
+	suite.NotEmpty(change.ID)
+	suite.Empty(changes)
+}
+
+func (suite *TaskStreamTestSuite) TestHubReplaysAfterLastEventID() {
+	first, cancel, err := suite.hub.Subscribe(suite.tenant, "")
+	suite.NoError(err)
+	suite.publish(suite.tenantID, domain.EventTaskCreated, domain.Task{Title: "One"})
+	seen := suite.receive(first)
+	cancel()
+
+	suite.publish(suite.tenantID, domain.EventTaskUpdated, domain.Task{Title: "Two"})
+	suite.publish(suite.tenantID, domain.EventTaskDeleted, domain.Task{Title: "Three"})
+
+	resumed, cancel, err := suite.hub.Subscribe(suite.tenant, seen.ID)
+	suite.NoError(err)
+	defer cancel()
+
+	suite.Equal("Two", suite.receive(resumed).Task.Title)
+	suite.Equal("Three", suite.receive(resumed).Task.Title)
+	suite.Empty(resumed)
+}
+
+func (suite *TaskStreamTestSuite) TestHubIgnoresUnknownLastEventID() {
+	suite.publish(suite.tenantID, domain.EventTaskCreated, domain.Task{Title: "One"})
+
+	changes, cancel, err := suite.hub.Subscribe(suite.tenant, "someone-else-12")
+	suite.NoError(err)
+	defer cancel()
+
+	suite.Empty(changes)
+}
+
+func (suite *TaskStreamTestSuite) TestStreamHidesTasksOfOtherProjects() {
+	member := primitive.NewObjectID()
+	visible := domain.Project{ID: primitive.NewObjectID(), Members: []domain.ProjectMember{{UserID: member, Role: "member"}}}
+	hidden := primitive.NewObjectID()
+	actor := domain.Actor{UserID: member.Hex(), Role: "regular", OrganizationID: suite.tenant}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, visible.ID.Hex()).Return(visible, nil)
+	suite.mockProjectRepo.On("Fetch", suite.tenant, hidden.Hex()).Return(domain.Project{}, errors.New("project not found"))
+
+	changes, cancel, err := suite.stream.Stream(actor, "")
+	suite.NoError(err)
+	defer cancel()
+
+	suite.publish(suite.tenantID, domain.EventTaskCreated, domain.Task{Title: "Secret", ProjectID: hidden})
+	suite.publish(suite.tenantID, domain.EventTaskUpdated, domain.Task{Title: "Shared", ProjectID: visible.ID})
+
+	change := suite.receive(changes)
+	suite.Equal("Shared", change.Task.Title)
+	suite.Equal(domain.EventTaskUpdated, change.Type)
+}
+
+func (suite *TaskStreamTestSuite) TestStreamRequiresOrganization() {
+	_, _, err := suite.stream.Stream(domain.Actor{UserID: primitive.NewObjectID().Hex()}, "")
+	suite.EqualError(err, "missing or invalid organization")
+}
+
+func TestTaskStreamTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskStreamTestSuite))
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskTransferTestSuite struct {
+	suite.Suite
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	usecase         *usecases.TaskUsecase
+	tenant          string
+	admin           domain.Actor
+}
+
+func (suite *TaskTransferTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.usecase = usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, nil, nil)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+}
+
+func (suite *TaskTransferTestSuite) TestExportCSVStreamsEveryTask() {
+	created := time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)
+	rank := 2
+	first := domain.Task{ID: primitive.NewObjectID(), ExternalID: "JIRA-1", Title: "Deploy, then verify", Description: "Gateway", Status: "pending", Priority: "high", Rank: &rank, DueDate: created.AddDate(0, 0, 7), CreatedAt: created, UpdatedAt: created}
+	second := domain.Task{ID: primitive.NewObjectID(), Title: "Docs", Description: "Write", Status: "completed", Priority: "low", CreatedAt: created, UpdatedAt: created}
+	suite.mockTaskRepo.On("Each", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{first, second}, nil)
+
+	write, err := suite.usecase.Export(suite.admin, domain.TaskQuery{}, "csv")
+	suite.NoError(err)
+
+	var output bytes.Buffer
+	suite.NoError(write(&output))
+	suite.Equal(strings.Join([]string{
+		"id,external_id,title,description,status,priority,rank,due_date,project_id,parent_id,labels,created_at,updated_at",
+		first.ID.Hex() + `,JIRA-1,"Deploy, then verify",Gateway,pending,high,2,2026-10-08T08:00:00Z,,,,2026-10-01T08:00:00Z,2026-10-01T08:00:00Z`,
+		second.ID.Hex() + ",,Docs,Write,completed,low,,,,,,2026-10-01T08:00:00Z,2026-10-01T08:00:00Z",
+		"",
+	}, "\n"), output.String())
+}
+
+func (suite *TaskTransferTestSuite) TestExportJSONWritesAnArray() {
+	tasks := []domain.Task{{ID: primitive.NewObjectID(), Title: "One"}, {ID: primitive.NewObjectID(), Title: "Two"}}
+	suite.mockTaskRepo.On("Each", suite.tenant, domain.TaskFilter{}).Return(tasks, nil).Once()
+	suite.mockTaskRepo.On("Each", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{}, nil).Once()
+
+	write, err := suite.usecase.Export(suite.admin, domain.TaskQuery{}, "json")
+	suite.NoError(err)
+
+	var output bytes.Buffer
+	suite.NoError(write(&output))
+	var decoded []domain.Task
+	suite.NoError(json.Unmarshal(output.Bytes(), &decoded))
+	suite.Equal([]string{"One", "Two"}, []string{decoded[0].Title, decoded[1].Title})
+
+	output.Reset()
+	suite.NoError(write(&output))
+	suite.Equal("[]\n", output.String())
+}
+
+func (suite *TaskTransferTestSuite) TestExportRejectsUnknownFormat() {
+	_, err := suite.usecase.Export(suite.admin, domain.TaskQuery{}, "xlsx")
+	suite.EqualError(err, "format must be csv, json or ndjson")
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Each", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTransferTestSuite) TestImportCSVWithMappingReportsEachRow() {
+	existing := domain.Task{ID: primitive.NewObjectID(), ExternalID: "JIRA-2", Title: "Existing"}
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ExternalIDs: []string{"JIRA-1", "JIRA-2", "JIRA-3", "JIRA-1"}}).Return([]domain.Task{existing}, nil)
+	created := domain.Task{ID: primitive.NewObjectID()}
+	suite.mockTaskRepo.On("Create", suite.tenant, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ExternalID == "JIRA-1" && task.Title == "Deploy" && task.Priority == "high" && task.DueDate.Equal(time.Date(2026, time.November, 2, 0, 0, 0, 0, time.UTC))
+	})).Return(created, nil).Once()
+
+	input := strings.Join([]string{
+		"Key,Summary,Details,State,Priority,Due",
+		"JIRA-1,Deploy,Ship the gateway,pending,High,2026-11-02",
+		"JIRA-2,Existing,Already imported,pending,low,2026-11-02",
+		"JIRA-3,Broken,Bad status,done,low,2026-11-02",
+		"JIRA-1,Again,Same key,pending,low,2026-11-02",
+		",Undated,No due date,pending,low,next week",
+	}, "\n")
+	mapping := map[string]string{"Key": "external_id", "Summary": "title", "Details": "description", "State": "status", "Due": "due_date"}
+
+	report, err := suite.usecase.Import(suite.admin, "csv", strings.NewReader(input), mapping, false)
+	suite.NoError(err)
+
+	suite.Equal(domain.TaskImportReport{Created: 1, Skipped: 1, Failed: 3, Rows: []domain.TaskImportRow{
+		{Row: 2, Status: "created", ID: created.ID.Hex(), ExternalID: "JIRA-1"},
+		{Row: 3, Status: "skipped", ID: existing.ID.Hex(), ExternalID: "JIRA-2"},
+		{Row: 4, Status: "failed", ExternalID: "JIRA-3", Error: "invalid status"},
+		{Row: 5, Status: "failed", ExternalID: "JIRA-1", Error: "external id is used by an earlier row"},
+		{Row: 6, Status: "failed", Error: `invalid due_date "next week", use YYYY-MM-DD or RFC 3339`},
+	}}, report)
+}
+
+func (suite *TaskTransferTestSuite) TestDryRunValidatesWithoutSaving() {
+	input := `{"title": "Deploy", "description": "Ship it", "status": "pending", "due_date": "2026-11-02", "rank": 3}
+{"title": "Incomplete", "status": "pending"}
+`
+
+	report, err := suite.usecase.Import(suite.admin, "ndjson", strings.NewReader(input), nil, true)
+	suite.NoError(err)
+
+	suite.True(report.DryRun)
+	suite.Equal([]domain.TaskImportRow{
+		{Row: 1, Status: "valid"},
+		{Row: 2, Status: "failed", Error: "missing required fields"},
+	}, report.Rows)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTransferTestSuite) TestImportRejectsInvalidInput() {
+	cases := map[string]struct {
+		format  string
+		input   string
+		mapping map[string]string
+	}{
+		`cannot map "Owner" to unknown task field "assignee"`: {"csv", "Owner\nabeni\n", map[string]string{"Owner": "assignee"}},
+		`column "Summary" is missing`:                         {"csv", "Title\nDeploy\n", map[string]string{"Summary": "title"}},
+		"import has no rows":                                  {"json", "[]", nil},
+		"invalid JSON: expected an array of tasks":            {"json", `{"title": "Deploy"}`, nil},
+		"format must be csv, json or ndjson":                  {"xlsx", "", nil},
+	}
+
+	for message, test := range cases {
+		_, err := suite.usecase.Import(suite.admin, test.format, strings.NewReader(test.input), test.mapping, false)
+		suite.EqualError(err, message)
+	}
+}
+
+func TestTaskTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskTransferTestSuite))
+}
@@ -7,19 +7,37 @@ import (
 	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Usecases"
 	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type TaskTestSuite struct {
 	suite.Suite
-	mockRepo *mocks.MockTaskRepo
-	usecase  usecases.TaskUsecase
+	mockRepo        *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	usecase         usecases.TaskUsecase
+	tenant          string
+	admin           domain.Actor
+	member          domain.Actor
 }
 
 func (suite *TaskTestSuite) SetupTest() {
 	suite.mockRepo = new(mocks.MockTaskRepo)
-	suite.usecase = *usecases.NewTaskUsecase(suite.mockRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.usecase = *usecases.NewTaskUsecase(suite.mockRepo, suite.mockProjectRepo, nil, nil)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "admin", OrganizationID: suite.tenant}
+	suite.member = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "member"}
+}
+
+func (suite *TaskTestSuite) project(role string) domain.Project {
+	memberID, _ := primitive.ObjectIDFromHex(suite.member.UserID)
+	return domain.Project{
+		ID:      primitive.NewObjectID(),
+		Name:    "Project",
+		Members: []domain.ProjectMember{{UserID: memberID, Role: role}},
+	}
 }
 
 func (suite *TaskTestSuite) TestTaskCreate() {
@@ -30,9 +48,9 @@ func (suite *TaskTestSuite) TestTaskCreate() {
 		Status:      "pending",
 	}
 
-	suite.mockRepo.On("Create", task).Return(*task, nil)
+	suite.mockRepo.On("Create", suite.tenant, task).Return(*task, nil)
 
-	createdTask, err := suite.usecase.Create(task)
+	createdTask, err := suite.usecase.Create(suite.admin, task)
 	suite.NoError(err)
 	suite.Equal(task.Title, createdTask.Title)
 	suite.Equal(task.Description, createdTask.Description)
@@ -49,7 +67,7 @@ func (suite *TaskTestSuite) TestTaskCreateMissingFields() {
 		Status:      "pending",
 	}
 
-	createdTask, err := suite.usecase.Create(task)
+	createdTask, err := suite.usecase.Create(suite.admin, task)
 	suite.Error(err)
 	suite.Equal(domain.Task{}, createdTask)
 
@@ -64,13 +82,81 @@ func (suite *TaskTestSuite) TestTaskCreateInvalidStatus() {
 		Status:      "invalid-status",
 	}
 
-	createdTask, err := suite.usecase.Create(task)
+	createdTask, err := suite.usecase.Create(suite.admin, task)
 	suite.Error(err)
 	suite.Equal(domain.Task{}, createdTask)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TaskTestSuite) TestTaskCreateDefaultsPriority() {
+	task := &domain.Task{
+		Title:       "Test Task",
+		Description: "This is a test task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	suite.mockRepo.On("Create", suite.tenant, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.Priority == "medium"
+	})).Return(*task, nil)
+
+	_, err := suite.usecase.Create(suite.admin, task)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateInvalidPriority() {
+	rank := -1
+	for _, task := range []*domain.Task{
+		{Title: "Task", Description: "Desc", DueDate: time.Now(), Status: "pending", Priority: "critical"},
+		{Title: "Task", Description: "Desc", DueDate: time.Now(), Status: "pending", Rank: &rank},
+	} {
+		_, err := suite.usecase.Create(suite.admin, task)
+		suite.Error(err)
+	}
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateInvalidPriority() {
+	_, err := suite.usecase.Update(suite.admin, primitive.NewObjectID().Hex(), domain.Task{Status: "pending", Priority: "whenever"})
+	suite.EqualError(err, "invalid priority")
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskNextUpOrdersByScore() {
+	now := time.Now()
+	first, second := 1, 2
+	farUrgent := domain.Task{ID: primitive.NewObjectID(), Priority: "urgent", DueDate: now.Add(60 * 24 * time.Hour)}
+	overdueMedium := domain.Task{ID: primitive.NewObjectID(), Priority: "medium", DueDate: now.Add(-48 * time.Hour)}
+	soonHigh := domain.Task{ID: primitive.NewObjectID(), Priority: "high", DueDate: now.Add(24 * time.Hour)}
+	laterLowRanked := domain.Task{ID: primitive.NewObjectID(), Priority: "low", Rank: &second, DueDate: now.Add(30 * 24 * time.Hour)}
+	laterLowFirst := domain.Task{ID: primitive.NewObjectID(), Priority: "low", Rank: &first, DueDate: now.Add(40 * 24 * time.Hour)}
+
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{Statuses: []string{"pending", "in-progress"}}).
+		Return([]domain.Task{laterLowRanked, farUrgent, laterLowFirst, soonHigh, overdueMedium}, nil)
+
+	tasks, err := suite.usecase.NextUp(suite.admin, 0)
+	suite.NoError(err)
+
+	ids := []primitive.ObjectID{}
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	suite.Equal([]primitive.ObjectID{overdueMedium.ID, soonHigh.ID, farUrgent.ID, laterLowFirst.ID, laterLowRanked.ID}, ids)
+	suite.InDelta(47, *tasks[0].Score, 0.01)
+}
+
+func (suite *TaskTestSuite) TestTaskNextUpInvalidLimit() {
+	_, err := suite.usecase.NextUp(suite.admin, 500)
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "FetchAll", mock.Anything, mock.Anything)
+}
+
 func (suite *TaskTestSuite) TestTaskFetchAll() {
 	tasks := []domain.Task{
 		{
@@ -89,15 +175,50 @@ func (suite *TaskTestSuite) TestTaskFetchAll() {
 		},
 	}
 
-	suite.mockRepo.On("FetchAll").Return(tasks, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return(tasks, nil)
 
-	fetchedTasks, err := suite.usecase.FetchAll()
+	fetchedTasks, err := suite.usecase.FetchAll(suite.admin, domain.TaskQuery{})
 	suite.NoError(err)
 	suite.Equal(len(tasks), len(fetchedTasks))
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TaskTestSuite) TestTaskFetchAllByLabels() {
+	labelA := primitive.NewObjectID()
+	labelB := primitive.NewObjectID()
+	parent := domain.Task{ID: primitive.NewObjectID(), Labels: []primitive.ObjectID{labelA, labelB}}
+	child := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "completed"}
+
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{
+		Labels:         []primitive.ObjectID{labelA, labelB},
+		MatchAllLabels: true,
+	}).Return([]domain.Task{parent}, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{
+		ParentIDs: []primitive.ObjectID{parent.ID},
+	}).Return([]domain.Task{child}, nil)
+
+	tasks, err := suite.usecase.FetchAll(suite.admin, domain.TaskQuery{
+		Labels:     []string{labelA.Hex(), labelB.Hex()},
+		LabelMatch: "all",
+	})
+	suite.NoError(err)
+	suite.Len(tasks, 1)
+	suite.Equal(1, tasks[0].Progress.CompletedSubtasks)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskFetchAllInvalidLabelQuery() {
+	_, err := suite.usecase.FetchAll(suite.admin, domain.TaskQuery{Labels: []string{"not-an-id"}})
+	suite.Error(err)
+
+	_, err = suite.usecase.FetchAll(suite.admin, domain.TaskQuery{LabelMatch: "some"})
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "FetchAll", mock.Anything, mock.Anything)
+}
+
 func (suite *TaskTestSuite) TestTaskFetch() {
 	task := domain.Task{
 		ID:          primitive.NewObjectID(),
@@ -107,9 +228,10 @@ func (suite *TaskTestSuite) TestTaskFetch() {
 		Status:      "pending",
 	}
 
-	suite.mockRepo.On("Fetch", task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: task.ID}).Return([]domain.Task{}, nil)
 
-	fetchedTask, err := suite.usecase.Fetch(task.ID.Hex())
+	fetchedTask, err := suite.usecase.Fetch(suite.admin, task.ID.Hex())
 	suite.NoError(err)
 	suite.Equal(task.ID, fetchedTask.ID)
 	suite.Equal(task.Title, fetchedTask.Title)
@@ -126,9 +248,10 @@ func (suite *TaskTestSuite) TestTaskUpdate() {
 		Status:      "in-progress",
 	}
 
-	suite.mockRepo.On("Update", task.ID.Hex(), task).Return(task, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("Update", suite.tenant, task.ID.Hex(), task).Return(task, nil)
 
-	updatedTask, err := suite.usecase.Update(task.ID.Hex(), task)
+	updatedTask, err := suite.usecase.Update(suite.admin, task.ID.Hex(), task)
 	suite.NoError(err)
 	suite.Equal(task.ID, updatedTask.ID)
 	suite.Equal(task.Title, updatedTask.Title)
@@ -145,7 +268,7 @@ func (suite *TaskTestSuite) TestTaskUpdateInvalidStatus() {
 		Status:      "invalid-status",
 	}
 
-	updatedTask, err := suite.usecase.Update(task.ID.Hex(), task)
+	updatedTask, err := suite.usecase.Update(suite.admin, task.ID.Hex(), task)
 	suite.Error(err)
 	suite.Equal(domain.Task{}, updatedTask)
 
@@ -155,10 +278,389 @@ func (suite *TaskTestSuite) TestTaskUpdateInvalidStatus() {
 func (suite *TaskTestSuite) TestTaskRemove() {
 	taskID := primitive.NewObjectID()
 
-	suite.mockRepo.On("Remove", taskID.Hex()).Return(nil)
+	suite.mockRepo.On("Fetch", suite.tenant, taskID.Hex()).Return(domain.Task{ID: taskID}, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: taskID}).Return([]domain.Task{}, nil)
+	suite.mockRepo.On("Remove", suite.tenant, taskID.Hex()).Return(nil)
+
+	err := suite.usecase.Remove(suite.admin, taskID.Hex())
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskFetchAllRestrictsRegularUsersToTheirProjects() {
+	project := suite.project("viewer")
+
+	suite.mockProjectRepo.On("FetchByMember", suite.tenant, suite.member.UserID).Return([]domain.Project{project}, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{
+		Restricted:        true,
+		VisibleProjectIDs: []primitive.ObjectID{project.ID},
+	}).Return([]domain.Task{}, nil)
+
+	_, err := suite.usecase.FetchAll(suite.member, domain.TaskQuery{})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskFetchHidesTasksOfOtherProjects() {
+	project := domain.Project{ID: primitive.NewObjectID()}
+	task := domain.Task{ID: primitive.NewObjectID(), ProjectID: project.ID}
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockProjectRepo.On("Fetch", suite.tenant, project.ID.Hex()).Return(project, nil)
+
+	fetchedTask, err := suite.usecase.Fetch(suite.member, task.ID.Hex())
+	suite.Error(err)
+	suite.Equal(domain.Task{}, fetchedTask)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateByProjectEditor() {
+	project := suite.project("editor")
+	task := &domain.Task{
+		Title:       "Test Task",
+		Description: "This is a test task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		ProjectID:   project.ID,
+	}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, project.ID.Hex()).Return(project, nil)
+	suite.mockRepo.On("Create", suite.tenant, task).Return(*task, nil)
+
+	_, err := suite.usecase.Create(suite.member, task)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateByProjectViewerIsRejected() {
+	project := suite.project("viewer")
+	task := &domain.Task{
+		Title:       "Test Task",
+		Description: "This is a test task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		ProjectID:   project.ID,
+	}
+
+	suite.mockProjectRepo.On("Fetch", suite.tenant, project.ID.Hex()).Return(project, nil)
+
+	_, err := suite.usecase.Create(suite.member, task)
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateWithoutProjectRequiresAdmin() {
+	task := &domain.Task{
+		Title:       "Test Task",
+		Description: "This is a test task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	_, err := suite.usecase.Create(suite.member, task)
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskOrganizationAdminManagesWholeTenant() {
+	orgAdmin := suite.member
+	orgAdmin.OrganizationRole = "admin"
+	task := &domain.Task{
+		Title:       "Test Task",
+		Description: "This is a test task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{}, nil)
+	suite.mockRepo.On("Create", suite.tenant, task).Return(*task, nil)
+
+	_, err := suite.usecase.FetchAll(orgAdmin, domain.TaskQuery{})
+	suite.NoError(err)
+
+	_, err = suite.usecase.Create(orgAdmin, task)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskFetchComputesProgress() {
+	task := domain.Task{
+		ID:     primitive.NewObjectID(),
+		Status: "in-progress",
+		Checklist: []domain.ChecklistItem{
+			{ID: primitive.NewObjectID(), Title: "Draft", Done: true},
+			{ID: primitive.NewObjectID(), Title: "Review"},
+		},
+	}
+	subtasks := []domain.Task{
+		{ID: primitive.NewObjectID(), ParentID: task.ID, Status: "completed"},
+		{ID: primitive.NewObjectID(), ParentID: task.ID, Status: "completed"},
+		{ID: primitive.NewObjectID(), ParentID: task.ID, Status: "pending"},
+	}
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: task.ID}).Return(subtasks, nil)
+
+	fetchedTask, err := suite.usecase.Fetch(suite.admin, task.ID.Hex())
+	suite.NoError(err)
+	suite.Equal(&domain.TaskProgress{
+		CompletedSubtasks:       2,
+		TotalSubtasks:           3,
+		CompletedChecklistItems: 1,
+		TotalChecklistItems:     2,
+	}, fetchedTask.Progress)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateSubtaskInheritsProject() {
+	project := suite.project("editor")
+	parent := domain.Task{ID: primitive.NewObjectID(), ProjectID: project.ID}
+	task := &domain.Task{
+		Title:       "Subtask",
+		Description: "This is a subtask",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		ParentID:    parent.ID,
+	}
+
+	suite.mockRepo.On("Fetch", suite.tenant, parent.ID.Hex()).Return(parent, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: parent.ID}).Return([]domain.Task{}, nil)
+	suite.mockProjectRepo.On("Fetch", suite.tenant, project.ID.Hex()).Return(project, nil)
+	suite.mockRepo.On("Create", suite.tenant, task).Return(*task, nil)
+
+	_, err := suite.usecase.Create(suite.member, task)
+	suite.NoError(err)
+	suite.Equal(project.ID, task.ProjectID)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskCreateSubtaskTooDeep() {
+	tasks := []domain.Task{{ID: primitive.NewObjectID()}}
+	for i := 1; i < 5; i++ {
+		tasks = append(tasks, domain.Task{ID: primitive.NewObjectID(), ParentID: tasks[i-1].ID})
+	}
+	for _, task := range tasks {
+		suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	}
+	deepest := tasks[len(tasks)-1]
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: deepest.ID}).Return([]domain.Task{}, nil)
+
+	_, err := suite.usecase.Create(suite.admin, &domain.Task{
+		Title:       "Too deep",
+		Description: "One level too many",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		ParentID:    deepest.ID,
+	})
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateRejectsParentCycle() {
+	parent := domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+	child := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "pending"}
+
+	suite.mockRepo.On("Fetch", suite.tenant, parent.ID.Hex()).Return(parent, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, child.ID.Hex()).Return(child, nil)
+
+	_, err := suite.usecase.Update(suite.admin, parent.ID.Hex(), domain.Task{Status: "pending", ParentID: child.ID})
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateAutoCompletesParent() {
+	autoComplete := true
+	parent := domain.Task{ID: primitive.NewObjectID(), Status: "in-progress", AutoComplete: &autoComplete}
+	child := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "pending"}
+	completedChild := child
+	completedChild.Status = "completed"
+	sibling := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "completed"}
+	completedParent := parent
+	completedParent.Status = "completed"
+
+	suite.mockRepo.On("Fetch", suite.tenant, child.ID.Hex()).Return(child, nil)
+	suite.mockRepo.On("Update", suite.tenant, child.ID.Hex(), domain.Task{Status: "completed"}).Return(completedChild, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, parent.ID.Hex()).Return(parent, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: parent.ID}).Return([]domain.Task{completedChild, sibling}, nil)
+	suite.mockRepo.On("Update", suite.tenant, parent.ID.Hex(), domain.Task{Status: "completed"}).Return(completedParent, nil)
+
+	_, err := suite.usecase.Update(suite.admin, child.ID.Hex(), domain.Task{Status: "completed"})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateAutoCompletesParentWithCanceledSubtasks() {
+	autoComplete := true
+	parent := domain.Task{ID: primitive.NewObjectID(), Status: "in-progress", AutoComplete: &autoComplete}
+	child := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "pending"}
+	canceledChild := child
+	canceledChild.Status = "canceled"
+	completedSibling := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "completed"}
+	canceledSibling := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "canceled"}
+	completedParent := parent
+	completedParent.Status = "completed"
+
+	suite.mockRepo.On("Fetch", suite.tenant, child.ID.Hex()).Return(child, nil)
+	suite.mockRepo.On("Update", suite.tenant, child.ID.Hex(), domain.Task{Status: "canceled"}).Return(canceledChild, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, parent.ID.Hex()).Return(parent, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: parent.ID}).Return([]domain.Task{canceledChild, completedSibling, canceledSibling}, nil)
+	suite.mockRepo.On("Update", suite.tenant, parent.ID.Hex(), domain.Task{Status: "completed"}).Return(completedParent, nil)
+
+	_, err := suite.usecase.Update(suite.admin, child.ID.Hex(), domain.Task{Status: "canceled"})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateDoesNotAutoCompleteParentWithOnlyCanceledSubtasks() {
+	autoComplete := true
+	parent := domain.Task{ID: primitive.NewObjectID(), Status: "in-progress", AutoComplete: &autoComplete}
+	child := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "pending"}
+	canceledChild := child
+	canceledChild.Status = "canceled"
+	canceledSibling := domain.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Status: "canceled"}
+
+	suite.mockRepo.On("Fetch", suite.tenant, child.ID.Hex()).Return(child, nil)
+	suite.mockRepo.On("Update", suite.tenant, child.ID.Hex(), domain.Task{Status: "canceled"}).Return(canceledChild, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, parent.ID.Hex()).Return(parent, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: parent.ID}).Return([]domain.Task{canceledChild, canceledSibling}, nil)
+
+	_, err := suite.usecase.Update(suite.admin, child.ID.Hex(), domain.Task{Status: "canceled"})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", suite.tenant, parent.ID.Hex(), mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskRemoveWithSubtasksIsRejected() {
+	taskID := primitive.NewObjectID()
+
+	suite.mockRepo.On("Fetch", suite.tenant, taskID.Hex()).Return(domain.Task{ID: taskID}, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: taskID}).Return([]domain.Task{{ParentID: taskID}}, nil)
+
+	err := suite.usecase.Remove(suite.admin, taskID.Hex())
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Remove", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskRemoveWithAttachmentsIsRejected() {
+	taskID := primitive.NewObjectID()
+	task := domain.Task{ID: taskID, Attachments: []domain.Attachment{{ID: primitive.NewObjectID()}}}
+
+	suite.mockRepo.On("Fetch", suite.tenant, taskID.Hex()).Return(task, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: taskID}).Return([]domain.Task{}, nil)
+
+	err := suite.usecase.Remove(suite.admin, taskID.Hex())
+	suite.EqualError(err, "task still has attachments")
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Remove", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateChecklistItem() {
+	item := domain.ChecklistItem{ID: primitive.NewObjectID(), Title: "Draft"}
+	task := domain.Task{ID: primitive.NewObjectID(), Checklist: []domain.ChecklistItem{item}}
+	done := true
+	checkedItem := item
+	checkedItem.Done = true
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("UpdateChecklist", suite.tenant, task.ID.Hex(), []domain.ChecklistItem{checkedItem}).Return(task, nil)
+
+	_, err := suite.usecase.UpdateChecklistItem(suite.admin, task.ID.Hex(), item.ID.Hex(), domain.ChecklistItemUpdate{Done: &done})
+	suite.NoError(err)
+
+	_, err = suite.usecase.UpdateChecklistItem(suite.admin, task.ID.Hex(), primitive.NewObjectID().Hex(), domain.ChecklistItemUpdate{Done: &done})
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateRefusesProgressWhileBlocked() {
+	blocker := domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+	task := domain.Task{ID: primitive.NewObjectID(), Status: "pending", BlockedBy: []primitive.ObjectID{blocker.ID}}
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{IDs: task.BlockedBy}).Return([]domain.Task{blocker}, nil)
+
+	_, err := suite.usecase.Update(suite.admin, task.ID.Hex(), domain.Task{Status: "in-progress"})
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskUpdateAllowedOnceBlockersResolve() {
+	blocker := domain.Task{ID: primitive.NewObjectID(), Status: "completed"}
+	task := domain.Task{ID: primitive.NewObjectID(), Status: "pending", BlockedBy: []primitive.ObjectID{blocker.ID}}
+
+	suite.mockRepo.On("Fetch", suite.tenant, task.ID.Hex()).Return(task, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{IDs: task.BlockedBy}).Return([]domain.Task{blocker}, nil)
+	suite.mockRepo.On("Update", suite.tenant, task.ID.Hex(), domain.Task{Status: "in-progress"}).Return(task, nil)
+
+	_, err := suite.usecase.Update(suite.admin, task.ID.Hex(), domain.Task{Status: "in-progress"})
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskAddDependencyRejectsCycle() {
+	first := domain.Task{ID: primitive.NewObjectID()}
+	second := domain.Task{ID: primitive.NewObjectID(), BlockedBy: []primitive.ObjectID{first.ID}}
+
+	suite.mockRepo.On("Fetch", suite.tenant, first.ID.Hex()).Return(first, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, second.ID.Hex()).Return(second, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: second.ID}).Return([]domain.Task{}, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{IDs: second.BlockedBy}).Return([]domain.Task{first}, nil)
+
+	_, err := suite.usecase.AddDependency(suite.admin, first.ID.Hex(), second.ID.Hex())
+	suite.Error(err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateDependencies", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TaskTestSuite) TestTaskAddDependency() {
+	first := domain.Task{ID: primitive.NewObjectID()}
+	second := domain.Task{ID: primitive.NewObjectID()}
+
+	suite.mockRepo.On("Fetch", suite.tenant, first.ID.Hex()).Return(first, nil)
+	suite.mockRepo.On("Fetch", suite.tenant, second.ID.Hex()).Return(second, nil)
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{ParentID: first.ID}).Return([]domain.Task{}, nil)
+	suite.mockRepo.On("UpdateDependencies", suite.tenant, second.ID.Hex(), []primitive.ObjectID{first.ID}).Return(second, nil)
+
+	_, err := suite.usecase.AddDependency(suite.admin, second.ID.Hex(), first.ID.Hex())
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskTestSuite) TestTaskDependencyGraphOrder() {
+	first := domain.Task{ID: primitive.NewObjectID()}
+	second := domain.Task{ID: primitive.NewObjectID(), BlockedBy: []primitive.ObjectID{first.ID}}
+	third := domain.Task{ID: primitive.NewObjectID(), BlockedBy: []primitive.ObjectID{second.ID, first.ID}}
+
+	suite.mockRepo.On("FetchAll", suite.tenant, domain.TaskFilter{}).Return([]domain.Task{third, second, first}, nil)
 
-	err := suite.usecase.Remove(taskID.Hex())
+	graph, err := suite.usecase.DependencyGraph(suite.admin, nil)
 	suite.NoError(err)
+	suite.Equal([]primitive.ObjectID{first.ID, second.ID, third.ID}, graph.Order)
+	suite.Len(graph.Edges, 3)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskViewTestSuite struct {
+	suite.Suite
+	mockViewRepo    *mocks.MockTaskViewRepo
+	mockTaskRepo    *mocks.MockTaskRepo
+	mockProjectRepo *mocks.MockProjectRepo
+	mockLabelRepo   *mocks.MockLabelRepo
+	usecase         *usecases.TaskViewUsecase
+	tenant          string
+	owner           domain.Actor
+	member          domain.Actor
+	admin           domain.Actor
+}
+
+func (suite *TaskViewTestSuite) SetupTest() {
+	suite.mockViewRepo = new(mocks.MockTaskViewRepo)
+	suite.mockTaskRepo = new(mocks.MockTaskRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepo)
+	suite.mockLabelRepo = new(mocks.MockLabelRepo)
+	tasks := usecases.NewTaskUsecase(suite.mockTaskRepo, suite.mockProjectRepo, suite.mockLabelRepo, nil)
+	suite.usecase = usecases.NewTaskViewUsecase(suite.mockViewRepo, tasks)
+	suite.tenant = primitive.NewObjectID().Hex()
+	suite.owner = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "user", OrganizationID: suite.tenant, OrganizationRole: "member"}
+	suite.member = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "user", OrganizationID: suite.tenant, OrganizationRole: "member"}
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "user", OrganizationID: suite.tenant, OrganizationRole: "admin"}
+	suite.mockLabelRepo.On("FetchAll", suite.tenant).Return([]domain.Label{}, nil)
+}
+
+func (suite *TaskViewTestSuite) view(owner domain.Actor, visibility string) domain.TaskView {
+	ownerID, _ := primitive.ObjectIDFromHex(owner.UserID)
+	return domain.TaskView{ID: primitive.NewObjectID(), OwnerID: ownerID, Name: "Mine", Visibility: visibility}
+}
+
+func (suite *TaskViewTestSuite) TestCreateDefaultsToPrivateView() {
+	suite.mockViewRepo.On("Create", suite.tenant, mock.AnythingOfType("*domain.TaskView")).Return(domain.TaskView{}, nil)
+
+	view := domain.TaskView{Name: "Urgent work", Query: domain.TaskQuery{Expression: "priority:urgent"}, Sort: []string{"-due"}, Columns: []string{"title", "due_date"}}
+	_, err := suite.usecase.Create(suite.owner, &view)
+	suite.NoError(err)
+
+	suite.Equal("private", view.Visibility)
+	suite.Equal(suite.owner.UserID, view.OwnerID.Hex())
+	suite.False(view.CreatedAt.IsZero())
+}
+
+func (suite *TaskViewTestSuite) TestCreateRejectsInvalidViews() {
+	cases := map[string]domain.TaskView{
+		"view name is required":                    {Name: "  "},
+		"view name must be at most 100 characters": {Name: strings.Repeat("a", 101)},
+		"visibility must be private or global":     {Name: "Team", Visibility: "public"},
+		"cannot sort by colour":                    {Name: "Team", Sort: []string{"colour"}},
+		"unknown column secret":                    {Name: "Team", Columns: []string{"secret"}},
+		`invalid status "done" at position 8`:      {Name: "Team", Query: domain.TaskQuery{Expression: "status:done"}},
+	}
+
+	for message, view := range cases {
+		_, err := suite.usecase.Create(suite.owner, &view)
+		suite.EqualError(err, message)
+	}
+	suite.mockViewRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskViewTestSuite) TestPrivateViewsAreHiddenFromOtherUsers() {
+	private := suite.view(suite.owner, "private")
+	global := suite.view(suite.owner, "global")
+	suite.mockViewRepo.On("Fetch", suite.tenant, private.ID.Hex()).Return(private, nil)
+	suite.mockViewRepo.On("Fetch", suite.tenant, global.ID.Hex()).Return(global, nil)
+
+	_, err := suite.usecase.Fetch(suite.member, private.ID.Hex())
+	suite.EqualError(err, "view not found")
+
+	view, err := suite.usecase.Fetch(suite.member, global.ID.Hex())
+	suite.NoError(err)
+	suite.Equal(global.ID, view.ID)
+}
+
+func (suite *TaskViewTestSuite) TestOnlyOwnerOrAdminCanChangeGlobalView() {
+	global := suite.view(suite.owner, "global")
+	name := "Renamed"
+	update := domain.TaskViewUpdate{Name: &name}
+	suite.mockViewRepo.On("Fetch", suite.tenant, global.ID.Hex()).Return(global, nil)
+	suite.mockViewRepo.On("Update", suite.tenant, global.ID.Hex(), update).Return(global, nil)
+	suite.mockViewRepo.On("Remove", suite.tenant, global.ID.Hex()).Return(nil)
+
+	_, err := suite.usecase.Update(suite.member, global.ID.Hex(), update)
+	suite.EqualError(err, "only the owner can change this view")
+	suite.EqualError(suite.usecase.Remove(suite.member, global.ID.Hex()), "only the owner can change this view")
+
+	_, err = suite.usecase.Update(suite.admin, global.ID.Hex(), update)
+	suite.NoError(err)
+	suite.NoError(suite.usecase.Remove(suite.owner, global.ID.Hex()))
+}
+
+func (suite *TaskViewTestSuite) TestFetchAllOrdersByUserPositions() {
+	first := suite.view(suite.owner, "private")
+	second := suite.view(suite.member, "global")
+	unplaced := suite.view(suite.owner, "private")
+	first.Positions = map[string]int{suite.owner.UserID: 1}
+	second.Positions = map[string]int{suite.owner.UserID: 0, suite.member.UserID: 5}
+	suite.mockViewRepo.On("FetchVisible", suite.tenant, suite.owner.UserID).Return([]domain.TaskView{unplaced, first, second}, nil)
+
+	views, err := suite.usecase.FetchAll(suite.owner)
+	suite.NoError(err)
+	suite.Equal([]primitive.ObjectID{second.ID, first.ID, unplaced.ID}, []primitive.ObjectID{views[0].ID, views[1].ID, views[2].ID})
+	suite.Equal(0, *views[0].Position)
+	suite.Nil(views[2].Position)
+}
+
+func (suite *TaskViewTestSuite) TestReorderRejectsViewsTheUserCannotSee() {
+	visible := suite.view(suite.owner, "private")
+	suite.mockViewRepo.On("FetchVisible", suite.tenant, suite.owner.UserID).Return([]domain.TaskView{visible}, nil)
+
+	hidden := primitive.NewObjectID().Hex()
+	_, err := suite.usecase.Reorder(suite.owner, []string{visible.ID.Hex(), hidden})
+	suite.EqualError(err, "view not found: "+hidden)
+
+	_, err = suite.usecase.Reorder(suite.owner, []string{visible.ID.Hex(), visible.ID.Hex()})
+	suite.EqualError(err, "view listed twice: "+visible.ID.Hex())
+	suite.mockViewRepo.AssertNotCalled(suite.T(), "UpdatePositions", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.mockViewRepo.On("UpdatePositions", suite.tenant, suite.owner.UserID, []primitive.ObjectID{visible.ID}).Return(nil)
+	_, err = suite.usecase.Reorder(suite.owner, []string{visible.ID.Hex()})
+	suite.NoError(err)
+}
+
+func (suite *TaskViewTestSuite) TestFetchTasksAppliesQueryAndSort() {
+	view := suite.view(suite.admin, "private")
+	view.Query = domain.TaskQuery{Expression: "status:pending"}
+	view.Sort = []string{"-priority", "due"}
+	suite.mockViewRepo.On("Fetch", suite.tenant, view.ID.Hex()).Return(view, nil)
+
+	due := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	low := domain.Task{ID: primitive.NewObjectID(), Priority: "low", DueDate: due}
+	urgentLate := domain.Task{ID: primitive.NewObjectID(), Priority: "urgent", DueDate: due.AddDate(0, 0, 7)}
+	urgentUndated := domain.Task{ID: primitive.NewObjectID(), Priority: "urgent"}
+	urgentSoon := domain.Task{ID: primitive.NewObjectID(), Priority: "urgent", DueDate: due}
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Expression != nil && filter.ParentIDs == nil
+	})).Return([]domain.Task{low, urgentUndated, urgentLate, urgentSoon}, nil)
+	suite.mockTaskRepo.On("FetchAll", suite.tenant, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.ParentIDs != nil
+	})).Return([]domain.Task{}, nil)
+
+	tasks, fetched, err := suite.usecase.FetchTasks(suite.admin, view.ID.Hex())
+	suite.NoError(err)
+	suite.Equal(view.ID, fetched.ID)
+	suite.Equal([]primitive.ObjectID{urgentSoon.ID, urgentLate.ID, urgentUndated.ID, low.ID}, []primitive.ObjectID{tasks[0].ID, tasks[1].ID, tasks[2].ID, tasks[3].ID})
+}
+
+func TestTaskViewTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskViewTestSuite))
+}
@@ -7,6 +7,7 @@ import (
 	domain "github.com/abeni-al7/task_manager/Domain"
 	usecases "github.com/abeni-al7/task_manager/Usecases"
 	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,12 +17,14 @@ type UserTestSuite struct {
 	mockRepo *mocks.MockUserRepo
 	usecase  usecases.UserUsecase
 	mockinfra    *mocks.MockInfrastructure
+	mockOrgRepo *mocks.MockOrganizationRepo
 }
 
 func (suite *UserTestSuite) SetupTest() {
 	suite.mockRepo = new(mocks.MockUserRepo)
 	suite.mockinfra = new(mocks.MockInfrastructure)
-	suite.usecase = *usecases.NewUserUsecase(suite.mockRepo, suite.mockinfra)
+	suite.mockOrgRepo = new(mocks.MockOrganizationRepo)
+	suite.usecase = *usecases.NewUserUsecase(suite.mockRepo, suite.mockOrgRepo, suite.mockinfra, nil)
 }
 
 func (suite *UserTestSuite) TestRegularUserRegister() {
@@ -36,7 +39,7 @@ func (suite *UserTestSuite) TestRegularUserRegister() {
 	suite.mockinfra.On("HashPassword", user.Password).Return("hashedpassword", nil)
 	suite.mockRepo.On("Register", user).Return(*user, nil)
 
-	createdUser, err := suite.usecase.Register(user)
+	createdUser, err := suite.usecase.Register(user, "")
 	suite.NoError(err)
 	suite.Equal("testuser", createdUser.Username)
 
@@ -56,7 +59,7 @@ func (suite *UserTestSuite) TestAdminUserRegister() {
 	suite.mockinfra.On("HashPassword", user.Password).Return("hashedadminpassword", nil)
 	suite.mockRepo.On("Register", user).Return(*user, nil)
 
-	createdUser, err := suite.usecase.Register(user)
+	createdUser, err := suite.usecase.Register(user, "")
 	suite.NoError(err)
 	suite.Equal("adminuser", createdUser.Username)
 
@@ -71,7 +74,7 @@ func (suite *UserTestSuite) TestRegisterMissingFields() {
 		Email:    "testuser@example.com",
 	}
 	
-	createdUser, err := suite.usecase.Register(user)
+	createdUser, err := suite.usecase.Register(user, "")
 	suite.Error(err)
 	suite.Equal(domain.User{}, createdUser)
 
@@ -87,7 +90,7 @@ func (suite *UserTestSuite) TestDuplicateRegister() {
 	}
 
 	suite.mockRepo.On("FetchByUsername", user.Username).Return(*user, nil)
-	createdUser, err := suite.usecase.Register(user)
+	createdUser, err := suite.usecase.Register(user, "")
 	suite.Error(err)
 	suite.Equal(domain.User{}, createdUser)
 
@@ -108,18 +111,19 @@ func (suite *UserTestSuite) TestUserLogin() {
 	suite.mockinfra.On("ComparePassword", []byte(hashedPassword), []byte(user.Password)).Return(nil)
 	suite.mockinfra.On("GenerateJwtToken", user).Return("jwt_token", nil)
 
-	jwtToken, err := suite.usecase.Login(user.Username, user.Password)
+	result, err := suite.usecase.Login(user.Username, user.Password)
 	suite.NoError(err)
-	suite.Equal("jwt_token", jwtToken)
+	suite.Equal("jwt_token", result.Token)
+	suite.False(result.TwoFactorRequired)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.mockinfra.AssertExpectations(suite.T())
 }
 
 func (suite *UserTestSuite) TestLoginMissingFields() {
-	jwtToken, err := suite.usecase.Login("", "")
+	result, err := suite.usecase.Login("", "")
 	suite.Error(err)
-	suite.Equal("", jwtToken)
+	suite.Equal(domain.LoginResult{}, result)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.mockinfra.AssertExpectations(suite.T())
@@ -133,9 +137,9 @@ func (suite *UserTestSuite) TestLoginInvalidUsername() {
 	}
 
 	suite.mockRepo.On("FetchByUsername", user.Username).Return(domain.User{}, errors.New("not found"))
-	jwtToken, err := suite.usecase.Login(user.Username, user.Password)
+	result, err := suite.usecase.Login(user.Username, user.Password)
 	suite.Error(err)
-	suite.Equal("", jwtToken)
+	suite.Equal(domain.LoginResult{}, result)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.mockinfra.AssertExpectations(suite.T())
@@ -150,9 +154,270 @@ func (suite *UserTestSuite) TestLoginInvalidCredentials() {
 
 	suite.mockRepo.On("FetchByUsername", user.Username).Return(user, nil)
 	suite.mockinfra.On("ComparePassword", []byte(user.Password), []byte("wrong")).Return(errors.New("invalid password"))
-	jwtToken, err := suite.usecase.Login(user.Username, "wrong")
+	result, err := suite.usecase.Login(user.Username, "wrong")
 	suite.Error(err)
-	suite.Equal("", jwtToken)
+	suite.Equal(domain.LoginResult{}, result)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestLoginWithTwoFactorReturnsChallenge() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		Username:         "testuser",
+		Password:         "hashedpassword",
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+	}
+
+	suite.mockRepo.On("FetchByUsername", user.Username).Return(user, nil)
+	suite.mockinfra.On("ComparePassword", []byte(user.Password), []byte("password123")).Return(nil)
+	suite.mockinfra.On("GenerateChallengeToken", &user).Return("challenge_token", nil)
+
+	result, err := suite.usecase.Login(user.Username, "password123")
+	suite.NoError(err)
+	suite.True(result.TwoFactorRequired)
+	suite.Equal("challenge_token", result.Challenge)
+	suite.Equal("", result.Token)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestLoginTwoFactor() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		Username:         "testuser",
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+	}
+
+	suite.mockinfra.On("ValidateChallengeToken", "challenge_token").Return(user.ID.Hex(), nil)
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "123456").Return(int64(100), true)
+	suite.mockRepo.On("ClaimTotpCounter", user.ID.Hex(), int64(100)).Return(true, nil)
+	suite.mockinfra.On("GenerateJwtToken", &user).Return("jwt_token", nil)
+
+	result, err := suite.usecase.LoginTwoFactor("challenge_token", "123456")
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestLoginTwoFactorWithRecoveryCode() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		Username:         "testuser",
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+		RecoveryCodes:    []string{"hashed-a", "hashed-b"},
+	}
+
+	suite.mockinfra.On("ValidateChallengeToken", "challenge_token").Return(user.ID.Hex(), nil)
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "abcd-efgh").Return(int64(0), false)
+	suite.mockinfra.On("ComparePassword", []byte("hashed-a"), []byte("abcd-efgh")).Return(errors.New("mismatch"))
+	suite.mockinfra.On("ComparePassword", []byte("hashed-b"), []byte("abcd-efgh")).Return(nil)
+	suite.mockRepo.On("ConsumeRecoveryCode", user.ID.Hex(), "hashed-b").Return(true, nil)
+	suite.mockinfra.On("GenerateJwtToken", &user).Return("jwt_token", nil)
+
+	result, err := suite.usecase.LoginTwoFactor("challenge_token", "abcd-efgh")
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestLoginTwoFactorRejectsRecoveryCodeAlreadyConsumed() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		Username:         "testuser",
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+		RecoveryCodes:    []string{"hashed-a"},
+	}
+
+	suite.mockinfra.On("ValidateChallengeToken", "challenge_token").Return(user.ID.Hex(), nil)
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "abcd-efgh").Return(int64(0), false)
+	suite.mockinfra.On("ComparePassword", []byte("hashed-a"), []byte("abcd-efgh")).Return(nil)
+	suite.mockRepo.On("ConsumeRecoveryCode", user.ID.Hex(), "hashed-a").Return(false, nil)
+
+	_, err := suite.usecase.LoginTwoFactor("challenge_token", "abcd-efgh")
+	suite.Error(err)
+	suite.mockinfra.AssertNotCalled(suite.T(), "GenerateJwtToken", mock.Anything)
+}
+
+func (suite *UserTestSuite) TestLoginTwoFactorInvalidCode() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+	}
+
+	suite.mockinfra.On("ValidateChallengeToken", "challenge_token").Return(user.ID.Hex(), nil)
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "000000").Return(int64(0), false)
+
+	result, err := suite.usecase.LoginTwoFactor("challenge_token", "000000")
+	suite.Error(err)
+	suite.Equal(domain.LoginResult{}, result)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestRegisterProvisionsOrganization() {
+	user := &domain.User{
+		Username: "founder",
+		Password: "password123",
+		Email:    "founder@example.com",
+	}
+	organization := domain.Organization{ID: primitive.NewObjectID(), Name: "Acme"}
+
+	suite.mockRepo.On("FetchByUsername", user.Username).Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("CountUsers").Return(0, nil)
+	suite.mockinfra.On("HashPassword", user.Password).Return("hashedpassword", nil)
+	suite.mockOrgRepo.On("Create", mock.MatchedBy(func(o *domain.Organization) bool {
+		return o.Name == "Acme"
+	})).Return(organization, nil)
+	suite.mockRepo.On("Register", mock.MatchedBy(func(u *domain.User) bool {
+		return u.Role == "regular" && len(u.Memberships) == 1 && u.Memberships[0].OrganizationID == organization.ID && u.Memberships[0].Role == "admin"
+	})).Return(*user, nil)
+
+	_, err := suite.usecase.Register(user, " Acme ")
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockOrgRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestRegisterRemovesOrganizationWhenUserInsertFails() {
+	user := &domain.User{
+		Username: "founder",
+		Password: "password123",
+		Email:    "founder@example.com",
+	}
+	organization := domain.Organization{ID: primitive.NewObjectID(), Name: "Acme"}
+
+	suite.mockRepo.On("FetchByUsername", user.Username).Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("CountUsers").Return(3, nil)
+	suite.mockinfra.On("HashPassword", user.Password).Return("hashedpassword", nil)
+	suite.mockOrgRepo.On("Create", mock.Anything).Return(organization, nil)
+	suite.mockRepo.On("Register", mock.Anything).Return(domain.User{}, errors.New("cannot register user"))
+	suite.mockOrgRepo.On("Remove", organization.ID.Hex()).Return(nil)
+
+	_, err := suite.usecase.Register(user, "Acme")
+	suite.EqualError(err, "cannot register user")
+
+	suite.mockOrgRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestRegisterRejectsInvalidUsername() {
+	user := &domain.User{
+		Username: "walter white!",
+		Password: "password123",
+		Email:    "walter@example.com",
+	}
+
+	_, err := suite.usecase.Register(user, "")
+	suite.EqualError(err, "username must be 3 to 32 letters, digits, dots, dashes or underscores")
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Register", mock.Anything)
+}
+
+func (suite *UserTestSuite) TestLoginTwoFactorRejectsReplayedCode() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+		TotpLastCounter:  100,
+	}
+
+	suite.mockinfra.On("ValidateChallengeToken", "challenge_token").Return(user.ID.Hex(), nil)
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "123456").Return(int64(100), true).Once()
+
+	_, err := suite.usecase.LoginTwoFactor("challenge_token", "123456")
+	suite.Error(err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "ClaimTotpCounter", mock.Anything, mock.Anything)
+
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "654321").Return(int64(101), true).Once()
+	suite.mockRepo.On("ClaimTotpCounter", user.ID.Hex(), int64(101)).Return(false, nil)
+
+	_, err = suite.usecase.LoginTwoFactor("challenge_token", "654321")
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestEnrollTwoFactor() {
+	user := domain.User{ID: primitive.NewObjectID(), Username: "testuser"}
+
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("GenerateTotpSecret").Return("SECRET", nil)
+	suite.mockRepo.On("SetPendingTotpSecret", user.ID.Hex(), "SECRET").Return(nil)
+	suite.mockinfra.On("TotpProvisioningURI", "SECRET", "testuser").Return("otpauth://totp/x")
+
+	enrollment, err := suite.usecase.EnrollTwoFactor(user.ID.Hex())
+	suite.NoError(err)
+	suite.Equal("SECRET", enrollment.Secret)
+	suite.Equal("otpauth://totp/x", enrollment.ProvisioningURI)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestConfirmTwoFactor() {
+	user := domain.User{ID: primitive.NewObjectID(), PendingTotpSecret: "SECRET"}
+
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "123456").Return(int64(100), true)
+	suite.mockinfra.On("GenerateRecoveryCodes", 10).Return([]string{"code-a"}, nil)
+	suite.mockinfra.On("HashPassword", "code-a").Return("hashed-a", nil)
+	suite.mockRepo.On("EnableTwoFactor", user.ID.Hex(), "SECRET", int64(100), []string{"hashed-a"}).Return(nil)
+
+	codes, err := suite.usecase.ConfirmTwoFactor(user.ID.Hex(), "123456")
+	suite.NoError(err)
+	suite.Equal([]string{"code-a"}, codes)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestConfirmTwoFactorWithoutEnrollment() {
+	user := domain.User{ID: primitive.NewObjectID()}
+
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+
+	_, err := suite.usecase.ConfirmTwoFactor(user.ID.Hex(), "123456")
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockinfra.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestDisableTwoFactor() {
+	user := domain.User{
+		ID:               primitive.NewObjectID(),
+		Password:         "hashedpassword",
+		TwoFactorEnabled: true,
+		TotpSecret:       "SECRET",
+	}
+
+	suite.mockRepo.On("Fetch", user.ID.Hex()).Return(user, nil)
+	suite.mockinfra.On("ComparePassword", []byte("hashedpassword"), []byte("password123")).Return(nil)
+	suite.mockinfra.On("MatchTotpCode", "SECRET", "123456").Return(int64(100), true)
+	suite.mockRepo.On("ClaimTotpCounter", user.ID.Hex(), int64(100)).Return(true, nil)
+	suite.mockRepo.On("DisableTwoFactor", user.ID.Hex()).Return(nil)
+
+	err := suite.usecase.DisableTwoFactor(user.ID.Hex(), "password123", "123456")
+	suite.NoError(err)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 	suite.mockinfra.AssertExpectations(suite.T())
@@ -213,9 +478,11 @@ func (suite *UserTestSuite) TestUserUpdate() {
 		Password: "password123",
 		Email:    "testuser@example.com",
 	}
-	suite.mockRepo.On("Update", user.ID.Hex(), *user).Return(*user, nil)
+	email := "testuser@example.com"
+	update := domain.ProfileUpdate{Email: &email}
+	suite.mockRepo.On("Update", user.ID.Hex(), update).Return(*user, nil)
 
-	updatedUser, err := suite.usecase.Update(user.ID.Hex(), *user)
+	updatedUser, err := suite.usecase.Update(user.ID.Hex(), update)
 	suite.NoError(err)
 	suite.Equal(*user, updatedUser)
 
@@ -223,6 +490,84 @@ func (suite *UserTestSuite) TestUserUpdate() {
 	suite.mockinfra.AssertExpectations(suite.T())
 }
 
+func (suite *UserTestSuite) TestUserUpdateProfile() {
+	userID := primitive.NewObjectID()
+	username := " walter "
+	displayName := "Walter White"
+	avatarURL := "https://example.com/avatar.png"
+	timezone := "America/Denver"
+	locale := "en-us"
+	preferences := domain.NotificationPreferences{EmailOnDueSoon: true}
+
+	suite.mockRepo.On("FetchByUsername", "walter").Return(domain.User{}, errors.New("not found"))
+	suite.mockRepo.On("Update", userID.Hex(), mock.MatchedBy(func(update domain.ProfileUpdate) bool {
+		return *update.Username == "walter" && *update.Locale == "en-US" &&
+			*update.Timezone == timezone && update.NotificationPreferences.EmailOnDueSoon
+	})).Return(domain.User{ID: userID, Username: "walter"}, nil)
+
+	updatedUser, err := suite.usecase.Update(userID.Hex(), domain.ProfileUpdate{
+		Username:                &username,
+		DisplayName:             &displayName,
+		AvatarURL:               &avatarURL,
+		Timezone:                &timezone,
+		Locale:                  &locale,
+		NotificationPreferences: &preferences,
+	})
+	suite.NoError(err)
+	suite.Equal("walter", updatedUser.Username)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestUserUpdateTakenUsername() {
+	userID := primitive.NewObjectID()
+	username := "jesse"
+
+	suite.mockRepo.On("FetchByUsername", "jesse").Return(domain.User{ID: primitive.NewObjectID(), Username: "jesse"}, nil)
+
+	_, err := suite.usecase.Update(userID.Hex(), domain.ProfileUpdate{Username: &username})
+	suite.Error(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestUserUpdateKeepsOwnUsername() {
+	userID := primitive.NewObjectID()
+	username := "walter"
+	update := domain.ProfileUpdate{Username: &username}
+
+	suite.mockRepo.On("FetchByUsername", "walter").Return(domain.User{ID: userID, Username: "walter"}, nil)
+	suite.mockRepo.On("Update", userID.Hex(), update).Return(domain.User{ID: userID, Username: "walter"}, nil)
+
+	_, err := suite.usecase.Update(userID.Hex(), update)
+	suite.NoError(err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestUserUpdateInvalidFields() {
+	userID := primitive.NewObjectID().Hex()
+	invalidUsername := "a"
+	invalidEmail := "not-an-email"
+	invalidAvatar := "javascript:alert(1)"
+	invalidTimezone := "Mars/Olympus"
+	invalidLocale := "??"
+
+	updates := []domain.ProfileUpdate{
+		{Username: &invalidUsername},
+		{Email: &invalidEmail},
+		{AvatarURL: &invalidAvatar},
+		{Timezone: &invalidTimezone},
+		{Locale: &invalidLocale},
+	}
+	for _, update := range updates {
+		_, err := suite.usecase.Update(userID, update)
+		suite.Error(err)
+	}
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
 func (suite *UserTestSuite) TestChangePassword() {
 	userID := primitive.NewObjectID().Hex()
 
@@ -0,0 +1,275 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases"
+	"github.com/abeni-al7/task_manager/Usecases/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	mockWebhookRepo  *mocks.MockWebhookRepo
+	mockDeliveryRepo *mocks.MockWebhookDeliveryRepo
+	mockSender       *mocks.MockWebhookSender
+	mockInfra        *mocks.MockInfrastructure
+	usecase          usecases.WebhookUsecase
+	tenantID         primitive.ObjectID
+	tenant           string
+	admin            domain.Actor
+	member           domain.Actor
+	now              time.Time
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.mockWebhookRepo = new(mocks.MockWebhookRepo)
+	suite.mockDeliveryRepo = new(mocks.MockWebhookDeliveryRepo)
+	suite.mockSender = new(mocks.MockWebhookSender)
+	suite.mockInfra = new(mocks.MockInfrastructure)
+	suite.usecase = *usecases.NewWebhookUsecase(suite.mockWebhookRepo, suite.mockDeliveryRepo, suite.mockSender, suite.mockInfra)
+
+	suite.tenantID = primitive.NewObjectID()
+	suite.tenant = suite.tenantID.Hex()
+	suite.admin = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "admin"}
+	suite.member = domain.Actor{UserID: primitive.NewObjectID().Hex(), Role: "regular", OrganizationID: suite.tenant, OrganizationRole: "member"}
+	suite.now = time.Date(2026, time.April, 2, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *WebhookTestSuite) TestCreateGeneratesSecret() {
+	suite.mockInfra.On("GenerateWebhookSecret").Return("whsec_abc", nil)
+	suite.mockWebhookRepo.On("Create", suite.tenant, mock.MatchedBy(func(w *domain.Webhook) bool {
+		return w.Secret == "whsec_abc" && w.Active && w.URL == "https://ci.example.com/hook"
+	})).Return(domain.Webhook{Secret: "whsec_abc", Active: true}, nil)
+
+	webhook, err := suite.usecase.Create(suite.admin, &domain.Webhook{URL: "https://ci.example.com/hook", Events: []string{"task.created"}})
+	suite.NoError(err)
+	suite.Equal("whsec_abc", webhook.Secret)
+
+	suite.mockWebhookRepo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestCreateValidation() {
+	_, err := suite.usecase.Create(suite.member, &domain.Webhook{URL: "https://ci.example.com/hook", Events: []string{"task.created"}})
+	suite.EqualError(err, "only admins can manage webhooks")
+
+	_, err = suite.usecase.Create(suite.admin, &domain.Webhook{URL: "ftp://ci.example.com/hook", Events: []string{"task.created"}})
+	suite.EqualError(err, "webhook url must be an absolute http or https url")
+
+	_, err = suite.usecase.Create(suite.admin, &domain.Webhook{URL: "https://ci.example.com/hook", Events: []string{"task.archived"}})
+	suite.EqualError(err, "unknown webhook event task.archived")
+
+	_, err = suite.usecase.Create(suite.admin, &domain.Webhook{URL: "https://ci.example.com/hook", Events: []string{"user.registered"}})
+	suite.EqualError(err, "only global admins can subscribe to user.registered")
+
+	suite.mockWebhookRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestFetchAllHidesSecrets() {
+	suite.mockWebhookRepo.On("FetchAll", suite.tenant).Return([]domain.Webhook{{Secret: "whsec_abc"}}, nil)
+
+	webhooks, err := suite.usecase.FetchAll(suite.admin)
+	suite.NoError(err)
+	suite.Empty(webhooks[0].Secret)
+}
+
+func (suite *WebhookTestSuite) TestHandleEventQueuesDeliveryPerWebhook() {
+	first := domain.Webhook{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID}
+	second := domain.Webhook{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID}
+	event := domain.Event{
+		ID:             primitive.NewObjectID(),
+		Type:           domain.EventTaskCreated,
+		OrganizationID: suite.tenantID,
+		Payload:        `{"title":"Ship"}`,
+		OccurredAt:     suite.now,
+	}
+
+	suite.mockWebhookRepo.On("FetchSubscribed", suite.tenant, "task.created").Return([]domain.Webhook{first, second}, nil)
+	suite.mockDeliveryRepo.On("Create", suite.tenant, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		var envelope struct {
+			ID    string      `json:"id"`
+			Event string      `json:"event"`
+			Data  domain.Task `json:"data"`
+		}
+		return d.Status == "pending" &&
+			json.Unmarshal([]byte(d.Payload), &envelope) == nil &&
+			envelope.ID == event.ID.Hex() && envelope.Event == "task.created" && envelope.Data.Title == "Ship"
+	})).Return(domain.WebhookDelivery{}, nil).Twice()
+
+	err := suite.usecase.HandleEvent(event)
+	suite.NoError(err)
+
+	suite.mockDeliveryRepo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestHandleEventWithoutSubscribersQueuesNothing() {
+	suite.mockWebhookRepo.On("FetchSubscribed", "", "user.registered").Return([]domain.Webhook{}, nil)
+
+	err := suite.usecase.HandleEvent(domain.Event{ID: primitive.NewObjectID(), Type: domain.EventUserRegistered, Payload: `{}`})
+	suite.NoError(err)
+
+	suite.mockDeliveryRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) expectClaim(delivery domain.WebhookDelivery) {
+	suite.mockDeliveryRepo.On("ClaimDue", suite.now, time.Minute*2).Return(delivery, true, nil).Once()
+	suite.mockDeliveryRepo.On("ClaimDue", suite.now, time.Minute*2).Return(domain.WebhookDelivery{}, false, nil).Once()
+}
+
+func (suite *WebhookTestSuite) TestDeliverDueSignsAndSends() {
+	webhook := domain.Webhook{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, URL: "https://ci.example.com/hook", Secret: "whsec_abc", Active: true}
+	delivery := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, OrganizationID: suite.tenantID, Event: "task.created", Payload: `{"event":"task.created"}`}
+
+	suite.expectClaim(delivery)
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, webhook.ID.Hex()).Return(webhook, nil)
+	suite.mockInfra.On("SignWebhookPayload", "whsec_abc", suite.now.Unix(), delivery.Payload).Return("sha256=signed")
+	suite.mockSender.On("Send", webhook.URL, map[string]string{
+		"X-Webhook-Event":     "task.created",
+		"X-Webhook-Delivery":  delivery.ID.Hex(),
+		"X-Webhook-Timestamp": "1775131200",
+		"X-Webhook-Signature": "sha256=signed",
+	}, delivery.Payload).Return(204, nil)
+	suite.mockDeliveryRepo.On("RecordAttempt", suite.tenant, delivery.ID.Hex(), domain.WebhookAttempt{At: suite.now, ResponseStatus: 204}, "delivered", suite.now).Return(nil)
+
+	err := suite.usecase.DeliverDue(suite.now)
+	suite.NoError(err)
+
+	suite.mockSender.AssertExpectations(suite.T())
+	suite.mockDeliveryRepo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestFailedDeliveryBacksOffExponentially() {
+	webhook := domain.Webhook{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, URL: "https://ci.example.com/hook", Active: true}
+	delivery := domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		WebhookID:      webhook.ID,
+		OrganizationID: suite.tenantID,
+		Attempts:       []domain.WebhookAttempt{{}, {}},
+	}
+
+	suite.expectClaim(delivery)
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, webhook.ID.Hex()).Return(webhook, nil)
+	suite.mockInfra.On("SignWebhookPayload", mock.Anything, mock.Anything, mock.Anything).Return("sha256=signed")
+	suite.mockSender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(503, nil)
+	suite.mockDeliveryRepo.On("RecordAttempt", suite.tenant, delivery.ID.Hex(), domain.WebhookAttempt{
+		At:             suite.now,
+		ResponseStatus: 503,
+		Error:          "unexpected response status 503",
+	}, "pending", suite.now.Add(time.Minute*2)).Return(nil)
+
+	err := suite.usecase.DeliverDue(suite.now)
+	suite.NoError(err)
+
+	suite.mockDeliveryRepo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestExhaustedDeliveryIsDeadLettered() {
+	webhook := domain.Webhook{ID: primitive.NewObjectID(), OrganizationID: suite.tenantID, URL: "https://ci.example.com/hook", Active: true}
+	delivery := domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		WebhookID:      webhook.ID,
+		OrganizationID: suite.tenantID,
+		Attempts:       make([]domain.WebhookAttempt, 7),
+	}
+
+	suite.expectClaim(delivery)
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, webhook.ID.Hex()).Return(webhook, nil)
+	suite.mockInfra.On("SignWebhookPayload", mock.Anything, mock.Anything, mock.Anything).Return("sha256=signed")
+	suite.mockSender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("connection refused"))
+	suite.mockDeliveryRepo.On("RecordAttempt", suite.tenant, delivery.ID.Hex(), domain.WebhookAttempt{
+		At:    suite.now,
+		Error: "connection refused",
+	}, "dead", suite.now).Return(nil)
+
+	err := suite.usecase.DeliverDue(suite.now)
+	suite.NoError(err)
+
+	suite.mockDeliveryRepo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestDeliveryForRemovedWebhookIsDeadLettered() {
+	delivery := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: primitive.NewObjectID(), OrganizationID: suite.tenantID}
+
+	suite.expectClaim(delivery)
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, delivery.WebhookID.Hex()).Return(domain.Webhook{}, errors.New("webhook not found"))
+	suite.mockDeliveryRepo.On("RecordAttempt", suite.tenant, delivery.ID.Hex(), domain.WebhookAttempt{At: suite.now, Error: "webhook no longer exists"}, "dead", suite.now).Return(nil)
+
+	err := suite.usecase.DeliverDue(suite.now)
+	suite.NoError(err)
+
+	suite.mockSender.AssertNotCalled(suite.T(), "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestRetryRequeuesDeadDelivery() {
+	webhook := domain.Webhook{ID: primitive.NewObjectID()}
+	delivery := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, Status: "dead"}
+
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, webhook.ID.Hex()).Return(webhook, nil)
+	suite.mockDeliveryRepo.On("Fetch", suite.tenant, delivery.ID.Hex()).Return(delivery, nil)
+	suite.mockDeliveryRepo.On("Requeue", suite.tenant, delivery.ID.Hex(), mock.Anything).Return(domain.WebhookDelivery{Status: "pending"}, nil)
+
+	requeued, err := suite.usecase.Retry(suite.admin, webhook.ID.Hex(), delivery.ID.Hex())
+	suite.NoError(err)
+	suite.Equal("pending", requeued.Status)
+}
+
+func (suite *WebhookTestSuite) TestRetryRejectsDeliveryOfAnotherWebhook() {
+	webhook := domain.Webhook{ID: primitive.NewObjectID()}
+	delivery := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: primitive.NewObjectID()}
+
+	suite.mockWebhookRepo.On("Fetch", suite.tenant, webhook.ID.Hex()).Return(webhook, nil)
+	suite.mockDeliveryRepo.On("Fetch", suite.tenant, delivery.ID.Hex()).Return(delivery, nil)
+
+	_, err := suite.usecase.Retry(suite.admin, webhook.ID.Hex(), delivery.ID.Hex())
+	suite.EqualError(err, "webhook delivery not found")
+
+	suite.mockDeliveryRepo.AssertNotCalled(suite.T(), "Requeue", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestTaskUsecaseEmitsEvents() {
+	mockTaskRepo := new(mocks.MockTaskRepo)
+	mockPublisher := new(mocks.MockEventPublisher)
+	tasks := usecases.NewTaskUsecase(mockTaskRepo, new(mocks.MockProjectRepo), nil, mockPublisher)
+
+	existing := domain.Task{ID: primitive.NewObjectID(), Status: "pending"}
+	updated := domain.Task{ID: existing.ID, Status: "in-progress"}
+
+	mockTaskRepo.On("Fetch", suite.tenant, existing.ID.Hex()).Return(existing, nil)
+	mockTaskRepo.On("Update", suite.tenant, existing.ID.Hex(), mock.Anything).Return(updated, nil)
+	mockPublisher.On("Publish", suite.tenant, "task.updated", updated).Return(nil)
+	mockPublisher.On("Publish", suite.tenant, "task.status_changed", domain.TaskStatusChange{Task: updated, PreviousStatus: "pending"}).Return(nil)
+
+	_, err := tasks.Update(suite.admin, existing.ID.Hex(), domain.Task{Status: "in-progress"})
+	suite.NoError(err)
+
+	mockPublisher.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestUserRegistrationEmitsEvent() {
+	mockUserRepo := new(mocks.MockUserRepo)
+	mockPublisher := new(mocks.MockEventPublisher)
+	users := usecases.NewUserUsecase(mockUserRepo, nil, suite.mockInfra, mockPublisher)
+
+	registered := domain.User{ID: primitive.NewObjectID(), Username: "abebe", Role: "regular"}
+
+	mockUserRepo.On("FetchByUsername", "abebe").Return(domain.User{}, errors.New("user not found"))
+	mockUserRepo.On("CountUsers").Return(1, nil)
+	suite.mockInfra.On("HashPassword", "secret").Return("hashed", nil)
+	mockUserRepo.On("Register", mock.Anything).Return(registered, nil)
+	mockPublisher.On("Publish", "", "user.registered", registered).Return(nil)
+
+	_, err := users.Register(&domain.User{Username: "abebe", Email: "abebe@example.com", Password: "secret"}, "")
+	suite.NoError(err)
+
+	mockPublisher.AssertExpectations(suite.T())
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
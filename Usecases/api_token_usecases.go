@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const tokenPrefixLength = 12
+
+var apiTokenScopes = map[string]bool{
+	"tasks:read": true,
+	"tasks:write": true,
+	"users:read": true,
+	"users:write": true,
+	"webhooks": true,
+	"account": true,
+}
+
+type APITokenUsecase struct {
+	tokenRepo usecases.IAPITokenRepo
+	userRepo usecases.IUserRepo
+	infra usecases.IInfrastructure
+}
+
+func NewAPITokenUsecase(tr usecases.IAPITokenRepo, ur usecases.IUserRepo, infra usecases.IInfrastructure) *APITokenUsecase {
+	return &APITokenUsecase{
+		tokenRepo: tr,
+		userRepo: ur,
+		infra: infra,
+	}
+}
+
+func (au *APITokenUsecase) Create(userID string, name string, scopes []string, expiresAt time.Time, callerScopes []string) (string, domain.APIToken, error) {
+	if name == "" || len(scopes) == 0 {
+		return "", domain.APIToken{}, errors.New("missing required fields")
+	}
+
+	for _, scope := range scopes {
+		if !apiTokenScopes[scope] {
+			return "", domain.APIToken{}, errors.New("invalid scope: " + scope)
+		}
+		if callerScopes != nil && !containsString(callerScopes, scope) {
+			return "", domain.APIToken{}, errors.New("an API token cannot grant the " + scope + " scope it does not have")
+		}
+	}
+
+	if !expiresAt.IsZero() && expiresAt.Before(time.Now()) {
+		return "", domain.APIToken{}, errors.New("expiry must be in the future")
+	}
+
+	owner, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", domain.APIToken{}, errors.New("invalid id")
+	}
+
+	existingTokens, err := au.tokenRepo.FetchByUser(userID)
+	if err != nil {
+		return "", domain.APIToken{}, errors.New(err.Error())
+	}
+	for _, existing := range existingTokens {
+		if existing.Name == name {
+			return "", domain.APIToken{}, errors.New("token with this name already exists")
+		}
+	}
+
+	rawToken, err := au.infra.GenerateAPIToken()
+	if err != nil || len(rawToken) <= tokenPrefixLength {
+		return "", domain.APIToken{}, errors.New("unable to generate token")
+	}
+
+	token := domain.APIToken{
+		UserID: owner,
+		Name: name,
+		Prefix: rawToken[:tokenPrefixLength],
+		TokenHash: au.infra.HashAPIToken(rawToken),
+		Scopes: scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	token, err = au.tokenRepo.Create(&token)
+	if err != nil {
+		return "", domain.APIToken{}, errors.New(err.Error())
+	}
+	return rawToken, token, nil
+}
+
+func (au *APITokenUsecase) FetchAll(userID string) ([]domain.APIToken, error) {
+	tokens, err := au.tokenRepo.FetchByUser(userID)
+	if err != nil {
+		return []domain.APIToken{}, errors.New(err.Error())
+	}
+	return tokens, nil
+}
+
+func (au *APITokenUsecase) Revoke(userID string, tokenID string) error {
+	err := au.tokenRepo.Remove(userID, tokenID)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func (au *APITokenUsecase) Authenticate(rawToken string) (domain.User, domain.APIToken, error) {
+	token, err := au.tokenRepo.FetchByHash(au.infra.HashAPIToken(rawToken))
+	if err != nil {
+		return domain.User{}, domain.APIToken{}, errors.New("invalid token")
+	}
+
+	if !token.ExpiresAt.IsZero() && token.ExpiresAt.Before(time.Now()) {
+		return domain.User{}, domain.APIToken{}, errors.New("token has expired")
+	}
+
+	user, err := au.userRepo.Fetch(token.UserID.Hex())
+	if err != nil {
+		return domain.User{}, domain.APIToken{}, errors.New("invalid token")
+	}
+
+	token.LastUsedAt = time.Now()
+	if err := au.tokenRepo.UpdateLastUsed(token.ID.Hex(), token.LastUsedAt); err != nil {
+		return domain.User{}, domain.APIToken{}, errors.New(err.Error())
+	}
+	return user, token, nil
+}
\ No newline at end of file
@@ -0,0 +1,225 @@
+package usecases
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxFilenameLength = 255
+
+type AttachmentLimits struct {
+	MaxBytes int64
+	AllowedTypes []string
+}
+
+func DefaultAttachmentLimits() AttachmentLimits {
+	return AttachmentLimits{
+		MaxBytes: 10 << 20,
+		AllowedTypes: []string{
+			"image/png",
+			"image/jpeg",
+			"image/gif",
+			"image/webp",
+			"application/pdf",
+			"application/zip",
+			"application/json",
+			"text/plain",
+			"text/markdown",
+			"text/csv",
+		},
+	}
+}
+
+var textTypesByExtension = map[string]string{
+	".md": "text/markdown",
+	".markdown": "text/markdown",
+	".csv": "text/csv",
+	".json": "application/json",
+}
+
+type AttachmentUsecase struct {
+	taskRepo usecases.ITaskRepo
+	tasks *TaskUsecase
+	storage usecases.IBlobStorage
+	limits AttachmentLimits
+}
+
+func NewAttachmentUsecase(tr usecases.ITaskRepo, tasks *TaskUsecase, storage usecases.IBlobStorage, limits AttachmentLimits) *AttachmentUsecase {
+	return &AttachmentUsecase{
+		taskRepo: tr,
+		tasks: tasks,
+		storage: storage,
+		limits: limits,
+	}
+}
+
+func (au *AttachmentUsecase) MaxBytes() int64 {
+	return au.limits.MaxBytes
+}
+
+func (au *AttachmentUsecase) Upload(actor domain.Actor, taskID string, filename string, size int64, body io.Reader) (domain.Attachment, error) {
+	task, err := au.tasks.writableTask(actor, taskID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if size <= 0 {
+		return domain.Attachment{}, errors.New("file is empty")
+	}
+	if size > au.limits.MaxBytes {
+		return domain.Attachment{}, fmt.Errorf("file exceeds the %d byte limit", au.limits.MaxBytes)
+	}
+
+	filename = cleanFilename(filename)
+	if filename == "" {
+		return domain.Attachment{}, errors.New("missing file name")
+	}
+
+	uploaderID, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return domain.Attachment{}, errors.New("invalid user")
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return domain.Attachment{}, errors.New("unable to read file")
+	}
+	head = head[:n]
+
+	contentType := detectContentType(head, filename)
+	if !au.allowed(contentType) {
+		return domain.Attachment{}, errors.New("file type " + contentType + " is not allowed")
+	}
+
+	attachment := domain.Attachment{
+		ID: primitive.NewObjectID(),
+		Filename: filename,
+		ContentType: contentType,
+		Size: size,
+		UploadedBy: uploaderID,
+		CreatedAt: time.Now(),
+	}
+	attachment.StorageKey = actor.OrganizationID + "/" + task.ID.Hex() + "/" + attachment.ID.Hex()
+
+	if err := au.storage.Put(attachment.StorageKey, io.MultiReader(bytes.NewReader(head), body), size, contentType); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if _, err := au.taskRepo.AddAttachment(actor.OrganizationID, taskID, attachment); err != nil {
+		au.storage.Delete(attachment.StorageKey)
+		return domain.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+func (au *AttachmentUsecase) FetchAll(actor domain.Actor, taskID string) ([]domain.Attachment, error) {
+	task, err := au.tasks.Fetch(actor, taskID)
+	if err != nil {
+		return []domain.Attachment{}, err
+	}
+
+	if task.Attachments == nil {
+		return []domain.Attachment{}, nil
+	}
+	return task.Attachments, nil
+}
+
+func (au *AttachmentUsecase) Download(actor domain.Actor, taskID string, id string) (domain.Attachment, io.ReadCloser, error) {
+	task, err := au.tasks.Fetch(actor, taskID)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	attachment, err := findAttachment(task, id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	content, err := au.storage.Get(attachment.StorageKey)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (au *AttachmentUsecase) Remove(actor domain.Actor, taskID string, id string) error {
+	task, err := au.tasks.writableTask(actor, taskID)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := findAttachment(task, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := au.taskRepo.RemoveAttachment(actor.OrganizationID, taskID, id); err != nil {
+		return err
+	}
+	return au.storage.Delete(attachment.StorageKey)
+}
+
+func (au *AttachmentUsecase) allowed(contentType string) bool {
+	for _, allowedType := range au.limits.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(allowedType), contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func findAttachment(task domain.Task, id string) (domain.Attachment, error) {
+	for _, attachment := range task.Attachments {
+		if attachment.ID.Hex() == id {
+			return attachment, nil
+		}
+	}
+	return domain.Attachment{}, errors.New("attachment not found")
+}
+
+func detectContentType(head []byte, filename string) string {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+
+	if contentType == "text/plain" {
+		if textType, ok := textTypesByExtension[strings.ToLower(path.Ext(filename))]; ok {
+			return textType
+		}
+	}
+	return contentType
+}
+
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.TrimSpace(filename)
+
+	if filename == "." || filename == "/" || filename == ".." {
+		return ""
+	}
+
+	if len(filename) > maxFilenameLength {
+		filename = strings.ToValidUTF8(filename[:maxFilenameLength], "")
+	}
+	return filename
+}
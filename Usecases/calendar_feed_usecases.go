@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxFeedNameLength = 100
+	icsTimeLayout = "20060102T150405Z"
+	icsLineLength = 75
+)
+
+var vtodoStatuses = map[string]string{
+	"pending": "NEEDS-ACTION",
+	"in-progress": "IN-PROCESS",
+	"completed": "COMPLETED",
+	"canceled": "CANCELLED",
+}
+
+var icsPriorities = map[string]int{
+	"urgent": 1,
+	"high": 3,
+	"medium": 5,
+	"low": 9,
+}
+
+type CalendarFeedUsecase struct {
+	feedRepo usecases.ICalendarFeedRepo
+	userRepo usecases.IUserRepo
+	infra usecases.IInfrastructure
+	tasks *TaskUsecase
+}
+
+func NewCalendarFeedUsecase(fr usecases.ICalendarFeedRepo, ur usecases.IUserRepo, infra usecases.IInfrastructure, tasks *TaskUsecase) *CalendarFeedUsecase {
+	return &CalendarFeedUsecase{
+		feedRepo: fr,
+		userRepo: ur,
+		infra: infra,
+		tasks: tasks,
+	}
+}
+
+func (cu *CalendarFeedUsecase) Create(actor domain.Actor, name string) (string, domain.CalendarFeed, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Tasks"
+	}
+	if len(name) > maxFeedNameLength {
+		return "", domain.CalendarFeed{}, errors.New("feed name must be at most 100 characters")
+	}
+
+	tenantID, err := primitive.ObjectIDFromHex(actor.OrganizationID)
+	if err != nil {
+		return "", domain.CalendarFeed{}, errors.New("missing or invalid organization")
+	}
+	owner, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return "", domain.CalendarFeed{}, errors.New("invalid id")
+	}
+
+	rawToken, err := cu.infra.GenerateFeedToken()
+	if err != nil || len(rawToken) <= tokenPrefixLength {
+		return "", domain.CalendarFeed{}, errors.New("unable to generate token")
+	}
+
+	feed := domain.CalendarFeed{
+		UserID: owner,
+		OrganizationID: tenantID,
+		Name: name,
+		Prefix: rawToken[:tokenPrefixLength],
+		TokenHash: cu.infra.HashAPIToken(rawToken),
+		CreatedAt: time.Now(),
+	}
+
+	feed, err = cu.feedRepo.Create(&feed)
+	if err != nil {
+		return "", domain.CalendarFeed{}, err
+	}
+	return rawToken, feed, nil
+}
+
+func (cu *CalendarFeedUsecase) FetchAll(actor domain.Actor) ([]domain.CalendarFeed, error) {
+	return cu.feedRepo.FetchByUser(actor.OrganizationID, actor.UserID)
+}
+
+func (cu *CalendarFeedUsecase) Revoke(actor domain.Actor, id string) error {
+	return cu.feedRepo.Remove(actor.OrganizationID, actor.UserID, id)
+}
+
+func (cu *CalendarFeedUsecase) Render(rawToken string, component string) (domain.Calendar, error) {
+	if component == "" {
+		component = "todo"
+	}
+	if component != "todo" && component != "event" {
+		return domain.Calendar{}, errors.New("component must be todo or event")
+	}
+
+	feed, err := cu.feedRepo.FetchByHash(cu.infra.HashAPIToken(rawToken))
+	if err != nil {
+		return domain.Calendar{}, errors.New("feed not found")
+	}
+
+	user, err := cu.userRepo.Fetch(feed.UserID.Hex())
+	if err != nil {
+		return domain.Calendar{}, errors.New("feed not found")
+	}
+	actor := domain.Actor{UserID: user.ID.Hex(), Role: user.Role, OrganizationID: feed.OrganizationID.Hex()}
+	for _, membership := range user.Memberships {
+		if membership.OrganizationID == feed.OrganizationID {
+			actor.OrganizationRole = membership.Role
+		}
+	}
+	if actor.OrganizationRole == "" && user.Role != "admin" {
+		return domain.Calendar{}, errors.New("feed not found")
+	}
+
+	filter, err := cu.tasks.visibilityFilter(actor)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	filter.Expression = &domain.TaskExpression{Op: "gt", Field: "due_date", Value: time.Time{}}
+
+	lastModified := feed.CreatedAt
+	fingerprint := sha256.New()
+	fmt.Fprintf(fingerprint, "%s|%s", component, feed.Name)
+
+	var body strings.Builder
+	writeICSLine(&body, "BEGIN:VCALENDAR")
+	writeICSLine(&body, "VERSION:2.0")
+	writeICSLine(&body, "PRODID:-//Task Manager//Tasks//EN")
+	writeICSLine(&body, "CALSCALE:GREGORIAN")
+	writeICSLine(&body, "METHOD:PUBLISH")
+	writeICSLine(&body, "X-WR-CALNAME:"+escapeICSText(feed.Name))
+	err = cu.tasks.taskRepo.Each(actor.OrganizationID, filter, func(task domain.Task) error {
+		if task.UpdatedAt.After(lastModified) {
+			lastModified = task.UpdatedAt
+		}
+		fmt.Fprintf(fingerprint, "|%s:%d", task.ID.Hex(), task.UpdatedAt.UnixNano())
+		writeICSTask(&body, task, component)
+		return nil
+	})
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	writeICSLine(&body, "END:VCALENDAR")
+
+	if err := cu.feedRepo.UpdateLastUsed(feed.ID.Hex(), time.Now()); err != nil {
+		return domain.Calendar{}, err
+	}
+
+	return domain.Calendar{
+		Body: body.String(),
+		ETag: `"` + hex.EncodeToString(fingerprint.Sum(nil))[:32] + `"`,
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}, nil
+}
+
+func writeICSTask(body *strings.Builder, task domain.Task, component string) {
+	name := "VTODO"
+	if component == "event" {
+		name = "VEVENT"
+	}
+
+	writeICSLine(body, "BEGIN:"+name)
+	writeICSLine(body, "UID:"+task.ID.Hex()+"@task-manager")
+	writeICSLine(body, "DTSTAMP:"+task.UpdatedAt.UTC().Format(icsTimeLayout))
+	writeICSLine(body, "CREATED:"+task.CreatedAt.UTC().Format(icsTimeLayout))
+	writeICSLine(body, "LAST-MODIFIED:"+task.UpdatedAt.UTC().Format(icsTimeLayout))
+	writeICSLine(body, "SUMMARY:"+escapeICSText(task.Title))
+	if task.Description != "" {
+		writeICSLine(body, "DESCRIPTION:"+escapeICSText(task.Description))
+	}
+	if priority, ok := icsPriorities[task.Priority]; ok {
+		writeICSLine(body, fmt.Sprintf("PRIORITY:%d", priority))
+	}
+
+	due := task.DueDate.UTC().Format(icsTimeLayout)
+	if component == "event" {
+		writeICSLine(body, "DTSTART:"+due)
+		status := "CONFIRMED"
+		if task.Status == "canceled" {
+			status = "CANCELLED"
+		}
+		writeICSLine(body, "STATUS:"+status)
+	} else {
+		writeICSLine(body, "DUE:"+due)
+		if status, ok := vtodoStatuses[task.Status]; ok {
+			writeICSLine(body, "STATUS:"+status)
+		}
+		if task.Status == "completed" {
+			writeICSLine(body, "COMPLETED:"+task.UpdatedAt.UTC().Format(icsTimeLayout))
+		}
+	}
+	writeICSLine(body, "END:"+name)
+}
+
+func escapeICSText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+func writeICSLine(body *strings.Builder, line string) {
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > icsLineLength {
+			body.WriteString("\r\n ")
+			length = 1
+		}
+		body.WriteRune(r)
+		length += size
+	}
+	body.WriteString("\r\n")
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"errors"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxCommentLength = 10000
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_.@-])@([a-zA-Z0-9_.-]{3,32})`)
+
+type CommentUsecase struct {
+	commentRepo usecases.ICommentRepo
+	tasks *TaskUsecase
+	userRepo usecases.IUserRepo
+	infra usecases.IInfrastructure
+}
+
+func NewCommentUsecase(cr usecases.ICommentRepo, tasks *TaskUsecase, ur usecases.IUserRepo, infra usecases.IInfrastructure) *CommentUsecase {
+	return &CommentUsecase{
+		commentRepo: cr,
+		tasks: tasks,
+		userRepo: ur,
+		infra: infra,
+	}
+}
+
+func (cu *CommentUsecase) Create(actor domain.Actor, taskID string, body string) (domain.Comment, error) {
+	if err := validateCommentBody(body); err != nil {
+		return domain.Comment{}, err
+	}
+
+	task, err := cu.tasks.Fetch(actor, taskID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	authorID, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return domain.Comment{}, errors.New("invalid user")
+	}
+
+	comment := domain.Comment{
+		TaskID: task.ID,
+		AuthorID: authorID,
+		Body: body,
+		Mentions: cu.mentions(actor, body),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	newComment, err := cu.commentRepo.Create(actor.OrganizationID, &comment)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return cu.render(newComment), nil
+}
+
+func (cu *CommentUsecase) FetchAll(actor domain.Actor, taskID string) ([]domain.Comment, error) {
+	if _, err := cu.tasks.Fetch(actor, taskID); err != nil {
+		return []domain.Comment{}, err
+	}
+
+	comments, err := cu.commentRepo.FetchByTask(actor.OrganizationID, taskID)
+	if err != nil {
+		return []domain.Comment{}, err
+	}
+	for i := range comments {
+		comments[i] = cu.render(comments[i])
+	}
+	return comments, nil
+}
+
+func (cu *CommentUsecase) Update(actor domain.Actor, taskID string, id string, body string) (domain.Comment, error) {
+	if err := validateCommentBody(body); err != nil {
+		return domain.Comment{}, err
+	}
+
+	if _, err := cu.authorize(actor, taskID, id); err != nil {
+		return domain.Comment{}, err
+	}
+
+	comment, err := cu.commentRepo.Update(actor.OrganizationID, id, body, cu.mentions(actor, body))
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return cu.render(comment), nil
+}
+
+func (cu *CommentUsecase) Remove(actor domain.Actor, taskID string, id string) error {
+	if _, err := cu.authorize(actor, taskID, id); err != nil {
+		return err
+	}
+	return cu.commentRepo.Remove(actor.OrganizationID, id)
+}
+
+func (cu *CommentUsecase) HandleTaskDeleted(event domain.Event, task domain.Task) error {
+	return cu.commentRepo.RemoveByTask(event.OrganizationID.Hex(), task.ID.Hex())
+}
+
+func (cu *CommentUsecase) authorize(actor domain.Actor, taskID string, id string) (domain.Comment, error) {
+	task, err := cu.tasks.Fetch(actor, taskID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	comment, err := cu.commentRepo.Fetch(actor.OrganizationID, id)
+	if err != nil || comment.TaskID != task.ID {
+		return domain.Comment{}, errors.New("comment not found")
+	}
+
+	if comment.AuthorID.Hex() != actor.UserID && !isTenantAdmin(actor) {
+		return domain.Comment{}, errors.New("only the author or an admin can change this comment")
+	}
+	return comment, nil
+}
+
+func (cu *CommentUsecase) mentions(actor domain.Actor, body string) []primitive.ObjectID {
+	mentions := []primitive.ObjectID{}
+	seen := map[string]bool{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, err := cu.userRepo.FetchByUsername(username)
+		if err != nil || organizationRole(user, actor.OrganizationID) == "" {
+			continue
+		}
+		mentions = append(mentions, user.ID)
+	}
+	return mentions
+}
+
+func (cu *CommentUsecase) render(comment domain.Comment) domain.Comment {
+	comment.BodyHTML = cu.infra.RenderMarkdown(comment.Body)
+	return comment
+}
+
+func validateCommentBody(body string) error {
+	if body == "" {
+		return errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return errors.New("comment is too long")
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	allEvents = "*"
+	maxEventAttempts = 10
+	eventBaseBackoff = time.Second * 5
+	eventMaxBackoff = time.Minute * 30
+	eventClaimLease = time.Minute
+	eventBatchSize = 100
+)
+
+type EventHandler func(event domain.Event) error
+
+type eventSubscriber struct {
+	name string
+	eventType string
+	handler EventHandler
+}
+
+type EventBus struct {
+	outboxRepo usecases.IOutboxRepo
+
+	mu sync.RWMutex
+	syncSubscribers []eventSubscriber
+	asyncSubscribers []eventSubscriber
+	wake chan struct{}
+}
+
+func NewEventBus(or usecases.IOutboxRepo) *EventBus {
+	return &EventBus{
+		outboxRepo: or,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func Typed[T any](handler func(event domain.Event, payload T) error) EventHandler {
+	return func(event domain.Event) error {
+		var payload T
+		if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+			return fmt.Errorf("cannot decode %s payload: %v", event.Type, err)
+		}
+		return handler(event, payload)
+	}
+}
+
+func (eb *EventBus) Subscribe(eventType string, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.syncSubscribers = append(eb.syncSubscribers, eventSubscriber{eventType: eventType, handler: handler})
+}
+
+func (eb *EventBus) SubscribeAsync(name string, eventType string, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.asyncSubscribers = append(eb.asyncSubscribers, eventSubscriber{name: name, eventType: eventType, handler: handler})
+}
+
+func (eb *EventBus) Publish(tenantID string, eventType string, data interface{}) error {
+	event, err := eb.Record(nil, tenantID, eventType, data)
+	if err != nil {
+		return err
+	}
+	return eb.Dispatch([]domain.Event{event})
+}
+
+func (eb *EventBus) Record(outbox usecases.IOutboxRepo, tenantID string, eventType string, data interface{}) (domain.Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.Event{}, errors.New("cannot encode event payload")
+	}
+
+	now := time.Now()
+	event := domain.Event{
+		ID: primitive.NewObjectID(),
+		Type: eventType,
+		Payload: string(payload),
+		OccurredAt: now,
+		Status: "pending",
+		Handled: []string{},
+		NextAttemptAt: now,
+	}
+	if tenantID != "" {
+		event.OrganizationID, err = primitive.ObjectIDFromHex(tenantID)
+		if err != nil {
+			return domain.Event{}, errors.New("missing or invalid organization")
+		}
+	}
+
+	if outbox == nil {
+		outbox = eb.outboxRepo
+	}
+	if err := outbox.Append(&event); err != nil {
+		return domain.Event{}, err
+	}
+	return event, nil
+}
+
+func (eb *EventBus) Dispatch(events []domain.Event) error {
+	var firstErr error
+	for _, event := range events {
+		for _, subscriber := range eb.subscribers(false, event.Type) {
+			if err := runEventHandler(subscriber.handler, event); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	select {
+	case eb.wake <- struct{}{}:
+	default:
+	}
+	return firstErr
+}
+
+func (eb *EventBus) RelayPending(now time.Time) error {
+	var firstErr error
+	for i := 0; i < eventBatchSize; i++ {
+		event, ok, err := eb.outboxRepo.ClaimPending(now, eventClaimLease)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		if err := eb.dispatch(event, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (eb *EventBus) StartRelay(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second * 5)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-eb.wake:
+		}
+		if err := eb.RelayPending(time.Now()); err != nil {
+			log.Println("event relay failed:", err)
+		}
+	}
+}
+
+func (eb *EventBus) dispatch(event domain.Event, now time.Time) error {
+	handled := map[string]bool{}
+	for _, name := range event.Handled {
+		handled[name] = true
+	}
+
+	var failure error
+	for _, subscriber := range eb.subscribers(true, event.Type) {
+		if handled[subscriber.name] {
+			continue
+		}
+		if err := runEventHandler(subscriber.handler, event); err != nil {
+			if failure == nil {
+				failure = fmt.Errorf("%s: %v", subscriber.name, err)
+			}
+			continue
+		}
+		if err := eb.outboxRepo.MarkHandled(event.ID.Hex(), subscriber.name); err != nil {
+			return err
+		}
+	}
+
+	if failure == nil {
+		return eb.outboxRepo.Complete(event.ID.Hex(), now)
+	}
+
+	attempts := event.Attempts + 1
+	if attempts >= maxEventAttempts {
+		if err := eb.outboxRepo.Reschedule(event.ID.Hex(), "failed", attempts, now, failure.Error()); err != nil {
+			return err
+		}
+		return failure
+	}
+	if err := eb.outboxRepo.Reschedule(event.ID.Hex(), "pending", attempts, now.Add(exponentialBackoff(eventBaseBackoff, eventMaxBackoff, attempts)), failure.Error()); err != nil {
+		return err
+	}
+	return failure
+}
+
+func (eb *EventBus) subscribers(async bool, eventType string) []eventSubscriber {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	list := eb.syncSubscribers
+	if async {
+		list = eb.asyncSubscribers
+	}
+
+	matched := []eventSubscriber{}
+	for _, subscriber := range list {
+		if subscriber.eventType == eventType || subscriber.eventType == allEvents {
+			matched = append(matched, subscriber)
+		}
+	}
+	return matched
+}
+
+func runEventHandler(handler EventHandler, event domain.Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%s handler panicked: %v", event.Type, recovered)
+		}
+	}()
+	return handler(event)
+}
+
+type eventRecorder struct {
+	publisher usecases.IEventPublisher
+	events []domain.Event
+}
+
+func newEventRecorder(publisher usecases.IEventPublisher) *eventRecorder {
+	return &eventRecorder{publisher: publisher}
+}
+
+func (er *eventRecorder) record(outbox usecases.IOutboxRepo, tenantID string, eventType string, data interface{}) error {
+	if er.publisher == nil {
+		return nil
+	}
+	event, err := er.publisher.Record(outbox, tenantID, eventType, data)
+	if err != nil {
+		return err
+	}
+	er.events = append(er.events, event)
+	return nil
+}
+
+func (er *eventRecorder) dispatch() {
+	if er == nil || er.publisher == nil || len(er.events) == 0 {
+		return
+	}
+	if err := er.publisher.Dispatch(er.events); err != nil {
+		log.Println("dispatching events failed:", err)
+	}
+}
+
+func exponentialBackoff(base time.Duration, max time.Duration, attempts int) time.Duration {
+	backoff := base
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= max {
+			return max
+		}
+	}
+	return backoff
+}
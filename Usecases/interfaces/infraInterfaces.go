@@ -1,9 +1,48 @@
 package usecases
 
-import "github.com/abeni-al7/task_manager/Domain"
+import (
+	"io"
+
+	"github.com/abeni-al7/task_manager/Domain"
+)
 
 type IInfrastructure interface {
 	HashPassword(password string) (string, error)
 	ComparePassword(correctPassword []byte, inputPassword []byte) error
 	GenerateJwtToken(user *domain.User) (string, error)
-}
\ No newline at end of file
+	GenerateChallengeToken(user *domain.User) (string, error)
+	ValidateChallengeToken(challenge string) (string, error)
+	GenerateTotpSecret() (string, error)
+	TotpProvisioningURI(secret string, accountName string) string
+	MatchTotpCode(secret string, code string) (int64, bool)
+	GenerateRecoveryCodes(count int) ([]string, error)
+	GenerateAPIToken() (string, error)
+	HashAPIToken(token string) string
+	GenerateFeedToken() (string, error)
+	RenderMarkdown(source string) string
+	GenerateWebhookSecret() (string, error)
+	SignWebhookPayload(secret string, timestamp int64, payload []byte) string
+}
+
+type IOIDCProvider interface {
+	BeginAuth() (string, string, error)
+	CompleteAuth(sessionToken string, state string, code string) (domain.OIDCIdentity, error)
+}
+type IBlobStorage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+type INotifier interface {
+	Notify(notification domain.Notification) error
+}
+
+type IWebhookSender interface {
+	Send(url string, headers map[string]string, payload []byte) (int, error)
+}
+
+type IEventPublisher interface {
+	Record(outbox IOutboxRepo, tenantID string, eventType string, data interface{}) (domain.Event, error)
+	Dispatch(events []domain.Event) error
+}
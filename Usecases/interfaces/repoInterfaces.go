@@ -1,25 +1,165 @@
 package usecases
 
 import (
+	"time"
+
 	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type IUserRepo interface {
+	WithOutbox(fn func(users IUserRepo, outbox IOutboxRepo) error) error
 	Register(user *domain.User) (domain.User, error)
 	Promote(user *domain.User) (domain.User, error)
-	FetchAll() ([]domain.User, error)
+	FetchAll(tenantID string) ([]domain.User, error)
 	Fetch(idStr string) (domain.User, error)
-	Update(idStr string, updatedUser domain.User) (domain.User, error)
+	FetchMember(tenantID string, idStr string) (domain.User, error)
+	SetMembership(idStr string, membership domain.Membership) error
+	RemoveMembership(idStr string, tenantID string) error
+	Update(idStr string, update domain.ProfileUpdate) (domain.User, error)
 	ChangePassword(idStr string, prevPassword string, newPassword string) error
 	Remove(idStr string) error
 	FetchByUsername(username string) (domain.User, error)
+	FetchByExternalIdentity(issuer string, subject string) (domain.User, error)
+	UpdateRole(idStr string, role string) error
 	CountUsers() (int, error)
+	SetPendingTotpSecret(idStr string, secret string) error
+	EnableTwoFactor(idStr string, secret string, lastCounter int64, recoveryCodes []string) error
+	DisableTwoFactor(idStr string) error
+	ClaimTotpCounter(idStr string, counter int64) (bool, error)
+	UpdateRecoveryCodes(idStr string, recoveryCodes []string) error
+	ConsumeRecoveryCode(idStr string, hashedCode string) (bool, error)
 }
 
 type ITaskRepo interface {
-	Create(task *domain.Task) (domain.Task, error)
-	FetchAll() ([]domain.Task, error)
-	Fetch(idStr string ) (domain.Task, error)
-	Update(idStr string, task domain.Task) (domain.Task, error)
+	WithOutbox(fn func(tasks ITaskRepo, outbox IOutboxRepo) error) error
+	Create(tenantID string, task *domain.Task) (domain.Task, error)
+	FetchAll(tenantID string, filter domain.TaskFilter) ([]domain.Task, error)
+	Each(tenantID string, filter domain.TaskFilter, fn func(task domain.Task) error) error
+	Fetch(tenantID string, idStr string) (domain.Task, error)
+	Update(tenantID string, idStr string, task domain.Task) (domain.Task, error)
+	UpdateChecklist(tenantID string, idStr string, checklist []domain.ChecklistItem) (domain.Task, error)
+	UpdateDependencies(tenantID string, idStr string, blockedBy []primitive.ObjectID) (domain.Task, error)
+	AddAttachment(tenantID string, idStr string, attachment domain.Attachment) (domain.Task, error)
+	RemoveAttachment(tenantID string, idStr string, attachmentIDStr string) (domain.Task, error)
+	AddLabel(tenantID string, idStr string, labelID primitive.ObjectID) (domain.Task, error)
+	RemoveLabel(tenantID string, idStr string, labelID primitive.ObjectID) (domain.Task, error)
+	RemoveLabelFromAll(tenantID string, labelID primitive.ObjectID) error
+	UpdateSeries(tenantID string, filter domain.TaskFilter, task domain.Task) error
+	EndSeries(tenantID string, filter domain.TaskFilter) error
+	FetchRecurringDue(before time.Time) ([]domain.Task, error)
+	ClaimNextOccurrence(tenantID string, idStr string) (bool, error)
+	FetchPendingReminders(kind string, from time.Time, to time.Time) ([]domain.Task, error)
+	MarkReminded(tenantID string, idStr string, kind string) error
+	FlagOverdue(tenantID string, idStr string) error
+	Remove(tenantID string, idStr string) error
+}
+
+type ITaskTransactor interface {
+	WithTransaction(fn func(tasks ITaskRepo) error) error
+}
+
+type ILabelRepo interface {
+	Create(tenantID string, label *domain.Label) (domain.Label, error)
+	FetchAll(tenantID string) ([]domain.Label, error)
+	Fetch(tenantID string, idStr string) (domain.Label, error)
+	Update(tenantID string, idStr string, label domain.Label) (domain.Label, error)
+	Remove(tenantID string, idStr string) error
+}
+
+type ICommentRepo interface {
+	Create(tenantID string, comment *domain.Comment) (domain.Comment, error)
+	FetchByTask(tenantID string, taskIDStr string) ([]domain.Comment, error)
+	Fetch(tenantID string, idStr string) (domain.Comment, error)
+	Update(tenantID string, idStr string, body string, mentions []primitive.ObjectID) (domain.Comment, error)
+	Remove(tenantID string, idStr string) error
+	RemoveByTask(tenantID string, taskIDStr string) error
+}
+
+type IAPITokenRepo interface {
+	Create(token *domain.APIToken) (domain.APIToken, error)
+	FetchByUser(userIDStr string) ([]domain.APIToken, error)
+	FetchByHash(tokenHash string) (domain.APIToken, error)
+	UpdateLastUsed(idStr string, lastUsedAt time.Time) error
+	Remove(userIDStr string, idStr string) error
+}
+
+type ICalendarFeedRepo interface {
+	Create(feed *domain.CalendarFeed) (domain.CalendarFeed, error)
+	FetchByUser(tenantID string, userIDStr string) ([]domain.CalendarFeed, error)
+	FetchByHash(tokenHash string) (domain.CalendarFeed, error)
+	UpdateLastUsed(idStr string, lastUsedAt time.Time) error
+	Remove(tenantID string, userIDStr string, idStr string) error
+}
+
+type IProjectRepo interface {
+	Create(tenantID string, project *domain.Project) (domain.Project, error)
+	FetchAll(tenantID string) ([]domain.Project, error)
+	FetchByMember(tenantID string, userIDStr string) ([]domain.Project, error)
+	Fetch(tenantID string, idStr string) (domain.Project, error)
+	Update(tenantID string, idStr string, project domain.Project) (domain.Project, error)
+	UpdateMembers(tenantID string, idStr string, members []domain.ProjectMember) (domain.Project, error)
+	Remove(tenantID string, idStr string) error
+}
+
+type IWebhookRepo interface {
+	Create(tenantID string, webhook *domain.Webhook) (domain.Webhook, error)
+	FetchAll(tenantID string) ([]domain.Webhook, error)
+	FetchSubscribed(tenantID string, event string) ([]domain.Webhook, error)
+	Fetch(tenantID string, idStr string) (domain.Webhook, error)
+	Update(tenantID string, idStr string, update domain.WebhookUpdate) (domain.Webhook, error)
+	Remove(tenantID string, idStr string) error
+}
+
+type IWebhookDeliveryRepo interface {
+	Create(tenantID string, delivery *domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	Fetch(tenantID string, idStr string) (domain.WebhookDelivery, error)
+	FetchByWebhook(tenantID string, webhookIDStr string, limit int) ([]domain.WebhookDelivery, error)
+	FetchByStatus(tenantID string, status string) ([]domain.WebhookDelivery, error)
+	ClaimDue(now time.Time, lease time.Duration) (domain.WebhookDelivery, bool, error)
+	RecordAttempt(tenantID string, idStr string, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error
+	Requeue(tenantID string, idStr string, now time.Time) (domain.WebhookDelivery, error)
+	RemoveByWebhook(tenantID string, webhookIDStr string) error
+}
+
+type IOutboxRepo interface {
+	Append(event *domain.Event) error
+	ClaimPending(now time.Time, lease time.Duration) (domain.Event, bool, error)
+	MarkHandled(idStr string, subscriber string) error
+	Complete(idStr string, publishedAt time.Time) error
+	Reschedule(idStr string, status string, attempts int, nextAttemptAt time.Time, lastError string) error
+}
+
+type ITaskViewRepo interface {
+	Create(tenantID string, view *domain.TaskView) (domain.TaskView, error)
+	FetchVisible(tenantID string, userID string) ([]domain.TaskView, error)
+	Fetch(tenantID string, idStr string) (domain.TaskView, error)
+	Update(tenantID string, idStr string, update domain.TaskViewUpdate) (domain.TaskView, error)
+	Remove(tenantID string, idStr string) error
+	UpdatePositions(tenantID string, userID string, ids []primitive.ObjectID) error
+}
+
+type ITaskSearcher interface {
+	Search(tenantID string, filter domain.TaskFilter, search domain.TaskSearch, limit int) ([]domain.TaskSearchResult, error)
+}
+
+type ITaskReporter interface {
+	Report(tenantID string, filter domain.TaskFilter, query domain.TaskReportQuery) (domain.TaskReport, error)
+}
+
+type ITaskChangeSource interface {
+	Subscribe(tenantID string, lastEventID string) (<-chan domain.TaskChange, func(), error)
+}
+
+type ILockRepo interface {
+	Acquire(name string, owner string, now time.Time, ttl time.Duration) (bool, error)
+	Release(name string, owner string) error
+}
+
+type IOrganizationRepo interface {
+	Create(organization *domain.Organization) (domain.Organization, error)
+	FetchAll() ([]domain.Organization, error)
+	FetchByIDs(ids []primitive.ObjectID) ([]domain.Organization, error)
+	Fetch(idStr string) (domain.Organization, error)
 	Remove(idStr string) error
 }
\ No newline at end of file
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+)
+
+const maxLabelNameLength = 50
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelUsecase struct {
+	labelRepo usecases.ILabelRepo
+	taskRepo usecases.ITaskRepo
+	tasks *TaskUsecase
+}
+
+func NewLabelUsecase(lr usecases.ILabelRepo, tr usecases.ITaskRepo, tasks *TaskUsecase) *LabelUsecase {
+	return &LabelUsecase{
+		labelRepo: lr,
+		taskRepo: tr,
+		tasks: tasks,
+	}
+}
+
+func (lu *LabelUsecase) Create(actor domain.Actor, label *domain.Label) (domain.Label, error) {
+	if !isTenantAdmin(actor) {
+		return domain.Label{}, errors.New("only admins can manage labels")
+	}
+
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" || label.Color == "" {
+		return domain.Label{}, errors.New("missing required fields")
+	}
+	if err := validateLabel(*label); err != nil {
+		return domain.Label{}, err
+	}
+	label.Color = strings.ToLower(label.Color)
+
+	label.CreatedAt = time.Now()
+	label.UpdatedAt = time.Now()
+
+	newLabel, err := lu.labelRepo.Create(actor.OrganizationID, label)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	return newLabel, nil
+}
+
+func (lu *LabelUsecase) FetchAll(actor domain.Actor) ([]domain.Label, error) {
+	labels, err := lu.labelRepo.FetchAll(actor.OrganizationID)
+	if err != nil {
+		return []domain.Label{}, err
+	}
+	return labels, nil
+}
+
+func (lu *LabelUsecase) Update(actor domain.Actor, id string, label domain.Label) (domain.Label, error) {
+	if !isTenantAdmin(actor) {
+		return domain.Label{}, errors.New("only admins can manage labels")
+	}
+
+	label.Name = strings.TrimSpace(label.Name)
+	if err := validateLabel(label); err != nil {
+		return domain.Label{}, err
+	}
+	label.Color = strings.ToLower(label.Color)
+
+	updatedLabel, err := lu.labelRepo.Update(actor.OrganizationID, id, label)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	return updatedLabel, nil
+}
+
+func (lu *LabelUsecase) Remove(actor domain.Actor, id string) error {
+	if !isTenantAdmin(actor) {
+		return errors.New("only admins can manage labels")
+	}
+
+	label, err := lu.labelRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := lu.labelRepo.Remove(actor.OrganizationID, id); err != nil {
+		return err
+	}
+	return lu.taskRepo.RemoveLabelFromAll(actor.OrganizationID, label.ID)
+}
+
+func (lu *LabelUsecase) Assign(actor domain.Actor, taskID string, labelID string) (domain.Task, error) {
+	if _, err := lu.tasks.writableTask(actor, taskID); err != nil {
+		return domain.Task{}, err
+	}
+
+	label, err := lu.labelRepo.Fetch(actor.OrganizationID, labelID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	updatedTask, err := lu.taskRepo.AddLabel(actor.OrganizationID, taskID, label.ID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return updatedTask, nil
+}
+
+func (lu *LabelUsecase) Unassign(actor domain.Actor, taskID string, labelID string) (domain.Task, error) {
+	task, err := lu.tasks.writableTask(actor, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	for _, assigned := range task.Labels {
+		if assigned.Hex() == labelID {
+			updatedTask, err := lu.taskRepo.RemoveLabel(actor.OrganizationID, taskID, assigned)
+			if err != nil {
+				return domain.Task{}, err
+			}
+			return updatedTask, nil
+		}
+	}
+	return domain.Task{}, errors.New("label is not assigned to this task")
+}
+
+func validateLabel(label domain.Label) error {
+	if utf8.RuneCountInString(label.Name) > maxLabelNameLength {
+		return errors.New("label name is too long")
+	}
+	if label.Color != "" && !labelColorPattern.MatchString(label.Color) {
+		return errors.New("label color must be a hex color like #1f883d")
+	}
+	return nil
+}
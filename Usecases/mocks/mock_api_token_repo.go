@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPITokenRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRepo) Create(token *domain.APIToken) (domain.APIToken, error) {
+	args := m.Called(token)
+	return args.Get(0).(domain.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepo) FetchByUser(userIDStr string) ([]domain.APIToken, error) {
+	args := m.Called(userIDStr)
+	return args.Get(0).([]domain.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepo) FetchByHash(tokenHash string) (domain.APIToken, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(domain.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepo) UpdateLastUsed(idStr string, lastUsedAt time.Time) error {
+	args := m.Called(idStr, lastUsedAt)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepo) Remove(userIDStr string, idStr string) error {
+	args := m.Called(userIDStr, idStr)
+	return args.Error(0)
+}
\ No newline at end of file
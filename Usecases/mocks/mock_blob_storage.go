@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBlobStorage struct {
+	mock.Mock
+}
+
+func (m *MockBlobStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	args := m.Called(key, string(content), size, contentType)
+	return args.Error(0)
+}
+
+func (m *MockBlobStorage) Get(key string) (io.ReadCloser, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockBlobStorage) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
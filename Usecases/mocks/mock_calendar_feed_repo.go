@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCalendarFeedRepo struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedRepo) Create(feed *domain.CalendarFeed) (domain.CalendarFeed, error) {
+	args := m.Called(feed)
+	return args.Get(0).(domain.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepo) FetchByUser(tenantID string, userIDStr string) ([]domain.CalendarFeed, error) {
+	args := m.Called(tenantID, userIDStr)
+	return args.Get(0).([]domain.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepo) FetchByHash(tokenHash string) (domain.CalendarFeed, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(domain.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepo) UpdateLastUsed(idStr string, lastUsedAt time.Time) error {
+	args := m.Called(idStr, lastUsedAt)
+	return args.Error(0)
+}
+
+func (m *MockCalendarFeedRepo) Remove(tenantID string, userIDStr string, idStr string) error {
+	args := m.Called(tenantID, userIDStr, idStr)
+	return args.Error(0)
+}
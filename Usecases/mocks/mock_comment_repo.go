@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockCommentRepo struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepo) Create(tenantID string, comment *domain.Comment) (domain.Comment, error) {
+	args := m.Called(tenantID, comment)
+	return args.Get(0).(domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepo) FetchByTask(tenantID string, taskIDStr string) ([]domain.Comment, error) {
+	args := m.Called(tenantID, taskIDStr)
+	return args.Get(0).([]domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepo) Fetch(tenantID string, idStr string) (domain.Comment, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepo) Update(tenantID string, idStr string, body string, mentions []primitive.ObjectID) (domain.Comment, error) {
+	args := m.Called(tenantID, idStr, body, mentions)
+	return args.Get(0).(domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) RemoveByTask(tenantID string, taskIDStr string) error {
+	args := m.Called(tenantID, taskIDStr)
+	return args.Error(0)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Record(outbox usecases.IOutboxRepo, tenantID string, eventType string, data interface{}) (domain.Event, error) {
+	return domain.Event{Type: eventType}, m.Publish(tenantID, eventType, data)
+}
+
+func (m *MockEventPublisher) Dispatch(events []domain.Event) error {
+	return nil
+}
+
+func (m *MockEventPublisher) Publish(tenantID string, eventType string, data interface{}) error {
+	args := m.Called(tenantID, eventType, data)
+	return args.Error(0)
+}
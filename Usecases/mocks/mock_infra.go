@@ -22,4 +22,63 @@ func (m *MockInfrastructure) ComparePassword(correctPassword []byte, inputPasswo
 func (m *MockInfrastructure) GenerateJwtToken(user *domain.User) (string, error) {
 	args := m.Called(user)
 	return args.String(0), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *MockInfrastructure) GenerateChallengeToken(user *domain.User) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInfrastructure) ValidateChallengeToken(challenge string) (string, error) {
+	args := m.Called(challenge)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInfrastructure) GenerateTotpSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInfrastructure) TotpProvisioningURI(secret string, accountName string) string {
+	args := m.Called(secret, accountName)
+	return args.String(0)
+}
+
+func (m *MockInfrastructure) MatchTotpCode(secret string, code string) (int64, bool) {
+	args := m.Called(secret, code)
+	return args.Get(0).(int64), args.Bool(1)
+}
+
+func (m *MockInfrastructure) GenerateRecoveryCodes(count int) ([]string, error) {
+	args := m.Called(count)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockInfrastructure) GenerateAPIToken() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInfrastructure) HashAPIToken(token string) string {
+	args := m.Called(token)
+	return args.String(0)
+}
+
+func (m *MockInfrastructure) GenerateFeedToken() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+func (m *MockInfrastructure) RenderMarkdown(source string) string {
+	args := m.Called(source)
+	return args.String(0)
+}
+
+func (m *MockInfrastructure) GenerateWebhookSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInfrastructure) SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	args := m.Called(secret, timestamp, string(payload))
+	return args.String(0)
+}
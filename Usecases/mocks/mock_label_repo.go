@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLabelRepo struct {
+	mock.Mock
+}
+
+func (m *MockLabelRepo) Create(tenantID string, label *domain.Label) (domain.Label, error) {
+	args := m.Called(tenantID, label)
+	return args.Get(0).(domain.Label), args.Error(1)
+}
+
+func (m *MockLabelRepo) FetchAll(tenantID string) ([]domain.Label, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).([]domain.Label), args.Error(1)
+}
+
+func (m *MockLabelRepo) Fetch(tenantID string, idStr string) (domain.Label, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.Label), args.Error(1)
+}
+
+func (m *MockLabelRepo) Update(tenantID string, idStr string, label domain.Label) (domain.Label, error) {
+	args := m.Called(tenantID, idStr, label)
+	return args.Get(0).(domain.Label), args.Error(1)
+}
+
+func (m *MockLabelRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
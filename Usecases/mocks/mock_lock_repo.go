@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLockRepo struct {
+	mock.Mock
+}
+
+func (m *MockLockRepo) Acquire(name string, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	args := m.Called(name, owner, now, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLockRepo) Release(name string, owner string) error {
+	args := m.Called(name, owner)
+	return args.Error(0)
+}
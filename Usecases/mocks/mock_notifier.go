@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(notification domain.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
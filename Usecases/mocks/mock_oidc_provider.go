@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOIDCProvider struct {
+	mock.Mock
+}
+
+func (m *MockOIDCProvider) BeginAuth() (string, string, error) {
+	args := m.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockOIDCProvider) CompleteAuth(sessionToken string, state string, code string) (domain.OIDCIdentity, error) {
+	args := m.Called(sessionToken, state, code)
+	return args.Get(0).(domain.OIDCIdentity), args.Error(1)
+}
\ No newline at end of file
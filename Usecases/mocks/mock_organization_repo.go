@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockOrganizationRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepo) Create(organization *domain.Organization) (domain.Organization, error) {
+	args := m.Called(organization)
+	return args.Get(0).(domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) FetchAll() ([]domain.Organization, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) FetchByIDs(ids []primitive.ObjectID) ([]domain.Organization, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) Fetch(idStr string) (domain.Organization, error) {
+	args := m.Called(idStr)
+	return args.Get(0).(domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) Remove(idStr string) error {
+	args := m.Called(idStr)
+	return args.Error(0)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepo) Append(event *domain.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) ClaimPending(now time.Time, lease time.Duration) (domain.Event, bool, error) {
+	args := m.Called(now, lease)
+	return args.Get(0).(domain.Event), args.Bool(1), args.Error(2)
+}
+
+func (m *MockOutboxRepo) MarkHandled(idStr string, subscriber string) error {
+	args := m.Called(idStr, subscriber)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) Complete(idStr string, publishedAt time.Time) error {
+	args := m.Called(idStr, publishedAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) Reschedule(idStr string, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	args := m.Called(idStr, status, attempts, nextAttemptAt, lastError)
+	return args.Error(0)
+}
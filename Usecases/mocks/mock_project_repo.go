@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProjectRepo struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepo) Create(tenantID string, project *domain.Project) (domain.Project, error) {
+	args := m.Called(tenantID, project)
+	return args.Get(0).(domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) FetchAll(tenantID string) ([]domain.Project, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).([]domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) FetchByMember(tenantID string, userIDStr string) ([]domain.Project, error) {
+	args := m.Called(tenantID, userIDStr)
+	return args.Get(0).([]domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) Fetch(tenantID string, idStr string) (domain.Project, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) Update(tenantID string, idStr string, project domain.Project) (domain.Project, error) {
+	args := m.Called(tenantID, idStr, project)
+	return args.Get(0).(domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) UpdateMembers(tenantID string, idStr string, members []domain.ProjectMember) (domain.Project, error) {
+	args := m.Called(tenantID, idStr, members)
+	return args.Get(0).(domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
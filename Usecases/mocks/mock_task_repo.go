@@ -1,35 +1,123 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type MockTaskRepo struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepo) Create(task *domain.Task) (domain.Task, error) {
-	args := m.Called(task)
+func (m *MockTaskRepo) WithOutbox(fn func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error) error {
+	return fn(m, nil)
+}
+
+func (m *MockTaskRepo) Create(tenantID string, task *domain.Task) (domain.Task, error) {
+	args := m.Called(tenantID, task)
 	return args.Get(0).(domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepo) FetchAll() ([]domain.Task, error) {
-	args := m.Called()
+func (m *MockTaskRepo) FetchAll(tenantID string, filter domain.TaskFilter) ([]domain.Task, error) {
+	args := m.Called(tenantID, filter)
 	return args.Get(0).([]domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepo) Fetch(idStr string) (domain.Task, error) {
-	args := m.Called(idStr)
+func (m *MockTaskRepo) Each(tenantID string, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	args := m.Called(tenantID, filter)
+	for _, task := range args.Get(0).([]domain.Task) {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTaskRepo) Fetch(tenantID string, idStr string) (domain.Task, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) Update(tenantID string, idStr string, task domain.Task) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, task)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) UpdateChecklist(tenantID string, idStr string, checklist []domain.ChecklistItem) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, checklist)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) UpdateDependencies(tenantID string, idStr string, blockedBy []primitive.ObjectID) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, blockedBy)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) AddAttachment(tenantID string, idStr string, attachment domain.Attachment) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, attachment)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) RemoveAttachment(tenantID string, idStr string, attachmentIDStr string) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, attachmentIDStr)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) AddLabel(tenantID string, idStr string, labelID primitive.ObjectID) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, labelID)
 	return args.Get(0).(domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepo) Update(idStr string, task domain.Task) (domain.Task, error) {
-	args := m.Called(idStr, task)
+func (m *MockTaskRepo) RemoveLabel(tenantID string, idStr string, labelID primitive.ObjectID) (domain.Task, error) {
+	args := m.Called(tenantID, idStr, labelID)
 	return args.Get(0).(domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepo) Remove(idStr string) error {
-	args := m.Called(idStr)
+func (m *MockTaskRepo) RemoveLabelFromAll(tenantID string, labelID primitive.ObjectID) error {
+	args := m.Called(tenantID, labelID)
 	return args.Error(0)
-}
\ No newline at end of file
+}
+
+func (m *MockTaskRepo) UpdateSeries(tenantID string, filter domain.TaskFilter, task domain.Task) error {
+	args := m.Called(tenantID, filter, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) EndSeries(tenantID string, filter domain.TaskFilter) error {
+	args := m.Called(tenantID, filter)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) FetchRecurringDue(before time.Time) ([]domain.Task, error) {
+	args := m.Called(before)
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) ClaimNextOccurrence(tenantID string, idStr string) (bool, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) FetchPendingReminders(kind string, from time.Time, to time.Time) ([]domain.Task, error) {
+	args := m.Called(kind, from, to)
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepo) MarkReminded(tenantID string, idStr string, kind string) error {
+	args := m.Called(tenantID, idStr, kind)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) FlagOverdue(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaskReporter struct {
+	mock.Mock
+}
+
+func (m *MockTaskReporter) Report(tenantID string, filter domain.TaskFilter, query domain.TaskReportQuery) (domain.TaskReport, error) {
+	args := m.Called(tenantID, filter, query)
+	return args.Get(0).(domain.TaskReport), args.Error(1)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaskSearcher struct {
+	mock.Mock
+}
+
+func (m *MockTaskSearcher) Search(tenantID string, filter domain.TaskFilter, search domain.TaskSearch, limit int) ([]domain.TaskSearchResult, error) {
+	args := m.Called(tenantID, filter, search, limit)
+	return args.Get(0).([]domain.TaskSearchResult), args.Error(1)
+}
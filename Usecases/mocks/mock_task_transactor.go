@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaskTransactor struct {
+	mock.Mock
+	Tasks usecases.ITaskRepo
+}
+
+func (m *MockTaskTransactor) WithTransaction(fn func(tasks usecases.ITaskRepo) error) error {
+	args := m.Called()
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m.Tasks)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockTaskViewRepo struct {
+	mock.Mock
+}
+
+func (m *MockTaskViewRepo) Create(tenantID string, view *domain.TaskView) (domain.TaskView, error) {
+	args := m.Called(tenantID, view)
+	return args.Get(0).(domain.TaskView), args.Error(1)
+}
+
+func (m *MockTaskViewRepo) FetchVisible(tenantID string, userID string) ([]domain.TaskView, error) {
+	args := m.Called(tenantID, userID)
+	return args.Get(0).([]domain.TaskView), args.Error(1)
+}
+
+func (m *MockTaskViewRepo) Fetch(tenantID string, idStr string) (domain.TaskView, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.TaskView), args.Error(1)
+}
+
+func (m *MockTaskViewRepo) Update(tenantID string, idStr string, update domain.TaskViewUpdate) (domain.TaskView, error) {
+	args := m.Called(tenantID, idStr, update)
+	return args.Get(0).(domain.TaskView), args.Error(1)
+}
+
+func (m *MockTaskViewRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
+
+func (m *MockTaskViewRepo) UpdatePositions(tenantID string, userID string, ids []primitive.ObjectID) error {
+	args := m.Called(tenantID, userID, ids)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	domain "github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,6 +10,10 @@ type MockUserRepo struct {
 	mock.Mock
 }
 
+func (m *MockUserRepo) WithOutbox(fn func(users usecases.IUserRepo, outbox usecases.IOutboxRepo) error) error {
+	return fn(m, nil)
+}
+
 func (m *MockUserRepo) FetchByUsername(username string) (domain.User, error) {
 	args := m.Called(username)
 	result := args.Get(0)
@@ -41,8 +46,8 @@ func(m *MockUserRepo) Promote(user *domain.User) (domain.User, error) {
 	return result.(domain.User), args.Error(1)
 }
 
-func(m *MockUserRepo) FetchAll() ([]domain.User, error) {
-	args := m.Called()
+func(m *MockUserRepo) FetchAll(tenantID string) ([]domain.User, error) {
+	args := m.Called(tenantID)
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
@@ -51,8 +56,23 @@ func(m *MockUserRepo) Fetch(idStr string) (domain.User, error) {
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
-func(m *MockUserRepo) Update(idStr string, updatedUser domain.User) (domain.User, error) {
-	args := m.Called(idStr, updatedUser)
+func(m *MockUserRepo) FetchMember(tenantID string, idStr string) (domain.User, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func(m *MockUserRepo) SetMembership(idStr string, membership domain.Membership) error {
+	args := m.Called(idStr, membership)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) RemoveMembership(idStr string, tenantID string) error {
+	args := m.Called(idStr, tenantID)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) Update(idStr string, update domain.ProfileUpdate) (domain.User, error) {
+	args := m.Called(idStr, update)
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
@@ -64,4 +84,44 @@ func(m *MockUserRepo) ChangePassword(idStr string, prevPassword string, newPassw
 func(m *MockUserRepo) Remove(idStr string) error {
 	args := m.Called(idStr)
 	return args.Error(0)
+}
+
+func(m *MockUserRepo) SetPendingTotpSecret(idStr string, secret string) error {
+	args := m.Called(idStr, secret)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) EnableTwoFactor(idStr string, secret string, lastCounter int64, recoveryCodes []string) error {
+	args := m.Called(idStr, secret, lastCounter, recoveryCodes)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) ClaimTotpCounter(idStr string, counter int64) (bool, error) {
+	args := m.Called(idStr, counter)
+	return args.Bool(0), args.Error(1)
+}
+
+func(m *MockUserRepo) DisableTwoFactor(idStr string) error {
+	args := m.Called(idStr)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) UpdateRecoveryCodes(idStr string, recoveryCodes []string) error {
+	args := m.Called(idStr, recoveryCodes)
+	return args.Error(0)
+}
+
+func(m *MockUserRepo) ConsumeRecoveryCode(idStr string, hashedCode string) (bool, error) {
+	args := m.Called(idStr, hashedCode)
+	return args.Bool(0), args.Error(1)
+}
+
+func(m *MockUserRepo) FetchByExternalIdentity(issuer string, subject string) (domain.User, error) {
+	args := m.Called(issuer, subject)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func(m *MockUserRepo) UpdateRole(idStr string, role string) error {
+	args := m.Called(idStr, role)
+	return args.Error(0)
 }
\ No newline at end of file
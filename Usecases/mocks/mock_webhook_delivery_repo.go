@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookDeliveryRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepo) Create(tenantID string, delivery *domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	args := m.Called(tenantID, delivery)
+	return args.Get(0).(domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepo) Fetch(tenantID string, idStr string) (domain.WebhookDelivery, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepo) FetchByWebhook(tenantID string, webhookIDStr string, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(tenantID, webhookIDStr, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepo) FetchByStatus(tenantID string, status string) ([]domain.WebhookDelivery, error) {
+	args := m.Called(tenantID, status)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepo) ClaimDue(now time.Time, lease time.Duration) (domain.WebhookDelivery, bool, error) {
+	args := m.Called(now, lease)
+	return args.Get(0).(domain.WebhookDelivery), args.Bool(1), args.Error(2)
+}
+
+func (m *MockWebhookDeliveryRepo) RecordAttempt(tenantID string, idStr string, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	args := m.Called(tenantID, idStr, attempt, status, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepo) Requeue(tenantID string, idStr string, now time.Time) (domain.WebhookDelivery, error) {
+	args := m.Called(tenantID, idStr, now)
+	return args.Get(0).(domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepo) RemoveByWebhook(tenantID string, webhookIDStr string) error {
+	args := m.Called(tenantID, webhookIDStr)
+	return args.Error(0)
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) Create(tenantID string, webhook *domain.Webhook) (domain.Webhook, error) {
+	args := m.Called(tenantID, webhook)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) FetchAll(tenantID string) ([]domain.Webhook, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) FetchSubscribed(tenantID string, event string) ([]domain.Webhook, error) {
+	args := m.Called(tenantID, event)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) Fetch(tenantID string, idStr string) (domain.Webhook, error) {
+	args := m.Called(tenantID, idStr)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) Update(tenantID string, idStr string, update domain.WebhookUpdate) (domain.Webhook, error) {
+	args := m.Called(tenantID, idStr, update)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) Remove(tenantID string, idStr string) error {
+	args := m.Called(tenantID, idStr)
+	return args.Error(0)
+}
@@ -0,0 +1,14 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(url string, headers map[string]string, payload []byte) (int, error) {
+	args := m.Called(url, headers, string(payload))
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+)
+
+type OIDCUsecase struct {
+	provider usecases.IOIDCProvider
+	userRepo usecases.IUserRepo
+	infra usecases.IInfrastructure
+	events usecases.IEventPublisher
+}
+
+func NewOIDCUsecase(provider usecases.IOIDCProvider, ur usecases.IUserRepo, infra usecases.IInfrastructure, events usecases.IEventPublisher) *OIDCUsecase {
+	return &OIDCUsecase{
+		provider: provider,
+		userRepo: ur,
+		infra: infra,
+		events: events,
+	}
+}
+
+func (ou *OIDCUsecase) Begin() (string, string, error) {
+	authURL, sessionToken, err := ou.provider.BeginAuth()
+	if err != nil {
+		return "", "", errors.New(err.Error())
+	}
+	return authURL, sessionToken, nil
+}
+
+func (ou *OIDCUsecase) Callback(sessionToken string, state string, code string) (domain.LoginResult, error) {
+	if sessionToken == "" || code == "" {
+		return domain.LoginResult{}, errors.New("missing sign in session or code")
+	}
+
+	identity, err := ou.provider.CompleteAuth(sessionToken, state, code)
+	if err != nil {
+		return domain.LoginResult{}, errors.New(err.Error())
+	}
+
+	user, err := ou.userRepo.FetchByExternalIdentity(identity.Issuer, identity.Subject)
+	if err != nil {
+		user, err = ou.provision(identity)
+		if err != nil {
+			return domain.LoginResult{}, errors.New(err.Error())
+		}
+	} else if identity.Role != "" && identity.Role != user.Role {
+		if err := ou.userRepo.UpdateRole(user.ID.Hex(), identity.Role); err != nil {
+			return domain.LoginResult{}, errors.New(err.Error())
+		}
+		user.Role = identity.Role
+	}
+
+	return issueLoginResult(ou.infra, &user)
+}
+
+func (ou *OIDCUsecase) provision(identity domain.OIDCIdentity) (domain.User, error) {
+	username, err := ou.availableUsername(identity)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	role := identity.Role
+	if role == "" {
+		count, err := ou.userRepo.CountUsers()
+		if err != nil {
+			return domain.User{}, errors.New("unable to register user")
+		}
+		role = "regular"
+		if count == 0 {
+			role = "admin"
+		}
+	}
+
+	user := domain.User{
+		Username: username,
+		Email: identity.Email,
+		Role: role,
+		ExternalIdentities: []domain.ExternalIdentity{
+			{Issuer: identity.Issuer, Subject: identity.Subject},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	var registered domain.User
+	var events *eventRecorder
+	err = ou.userRepo.WithOutbox(func(users usecases.IUserRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(ou.events)
+		registered, err = users.Register(&user)
+		if err != nil {
+			return err
+		}
+		return events.record(outbox, "", domain.EventUserRegistered, registered)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	events.dispatch()
+	return registered, nil
+}
+
+func (ou *OIDCUsecase) availableUsername(identity domain.OIDCIdentity) (string, error) {
+	base := usernameBase(identity.Username)
+	if base == "" && identity.Email != "" {
+		base = usernameBase(strings.Split(identity.Email, "@")[0])
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	for i := 0; i <= 20; i++ {
+		candidate := base
+		if i > 0 {
+			suffix := strconv.Itoa(i)
+			candidate = base[:min(len(base), 32-len(suffix))] + suffix
+		}
+		if !usernamePattern.MatchString(candidate) {
+			continue
+		}
+		if _, err := ou.userRepo.FetchByUsername(candidate); err != nil {
+			return candidate, nil
+		}
+	}
+	return "", errors.New("unable to pick a username")
+}
+
+func usernameBase(name string) string {
+	base := strings.Map(func(r rune) rune {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-') {
+			return r
+		}
+		return -1
+	}, name)
+	if len(base) < 3 {
+		return ""
+	}
+	return base[:min(len(base), 32)]
+}
\ No newline at end of file
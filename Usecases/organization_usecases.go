@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrganizationUsecase struct {
+	organizationRepo usecases.IOrganizationRepo
+	userRepo usecases.IUserRepo
+}
+
+func NewOrganizationUsecase(or usecases.IOrganizationRepo, ur usecases.IUserRepo) *OrganizationUsecase {
+	return &OrganizationUsecase{
+		organizationRepo: or,
+		userRepo: ur,
+	}
+}
+
+func (ou *OrganizationUsecase) Create(actor domain.Actor, organization *domain.Organization) (domain.Organization, error) {
+	if organization.Name == "" {
+		return domain.Organization{}, errors.New("missing required fields")
+	}
+
+	organization.CreatedAt = time.Now()
+	organization.UpdatedAt = time.Now()
+
+	newOrganization, err := ou.organizationRepo.Create(organization)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	membership := domain.Membership{OrganizationID: newOrganization.ID, Role: "admin"}
+	if err := ou.userRepo.SetMembership(actor.UserID, membership); err != nil {
+		return domain.Organization{}, err
+	}
+	return newOrganization, nil
+}
+
+func (ou *OrganizationUsecase) FetchAll(actor domain.Actor) ([]domain.Organization, error) {
+	if actor.Role == "admin" {
+		return ou.organizationRepo.FetchAll()
+	}
+
+	user, err := ou.userRepo.Fetch(actor.UserID)
+	if err != nil {
+		return []domain.Organization{}, errors.New("user does not exist")
+	}
+
+	ids := []primitive.ObjectID{}
+	for _, membership := range user.Memberships {
+		ids = append(ids, membership.OrganizationID)
+	}
+	return ou.organizationRepo.FetchByIDs(ids)
+}
+
+func (ou *OrganizationUsecase) SetMember(actor domain.Actor, userID string, role string) (domain.User, error) {
+	if role != "member" && role != "admin" {
+		return domain.User{}, errors.New("invalid organization role")
+	}
+
+	tenantID, err := primitive.ObjectIDFromHex(actor.OrganizationID)
+	if err != nil {
+		return domain.User{}, errors.New("organization not found")
+	}
+
+	if _, err := ou.userRepo.Fetch(userID); err != nil {
+		return domain.User{}, errors.New("user does not exist")
+	}
+
+	if role != "admin" {
+		if err := ou.ensureOtherAdmin(actor, userID); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	if err := ou.userRepo.SetMembership(userID, domain.Membership{OrganizationID: tenantID, Role: role}); err != nil {
+		return domain.User{}, err
+	}
+	return ou.userRepo.FetchMember(actor.OrganizationID, userID)
+}
+
+func (ou *OrganizationUsecase) RemoveMember(actor domain.Actor, userID string) error {
+	if err := ou.ensureOtherAdmin(actor, userID); err != nil {
+		return err
+	}
+	return ou.userRepo.RemoveMembership(userID, actor.OrganizationID)
+}
+
+func (ou *OrganizationUsecase) ResolveTenant(userID string, role string, requested string) (string, string, error) {
+	if role == "admin" && requested != "" {
+		if _, err := ou.organizationRepo.Fetch(requested); err != nil {
+			return "", "", errors.New("organization not found")
+		}
+		return requested, "admin", nil
+	}
+
+	user, err := ou.userRepo.Fetch(userID)
+	if err != nil {
+		return "", "", errors.New("user does not exist")
+	}
+
+	if requested == "" {
+		if len(user.Memberships) != 1 {
+			return "", "", errors.New("select an organization with the X-Organization-ID header")
+		}
+		membership := user.Memberships[0]
+		return membership.OrganizationID.Hex(), membership.Role, nil
+	}
+
+	for _, membership := range user.Memberships {
+		if membership.OrganizationID.Hex() == requested {
+			return requested, membership.Role, nil
+		}
+	}
+	return "", "", errors.New("organization not found")
+}
+
+func (ou *OrganizationUsecase) ensureOtherAdmin(actor domain.Actor, userID string) error {
+	user, err := ou.userRepo.FetchMember(actor.OrganizationID, userID)
+	if err != nil {
+		return nil
+	}
+	if organizationRole(user, actor.OrganizationID) != "admin" {
+		return nil
+	}
+
+	members, err := ou.userRepo.FetchAll(actor.OrganizationID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if member.ID.Hex() != userID && organizationRole(member, actor.OrganizationID) == "admin" {
+			return nil
+		}
+	}
+	return errors.New("organization must keep at least one admin")
+}
+
+func organizationRole(user domain.User, tenantID string) string {
+	for _, membership := range user.Memberships {
+		if membership.OrganizationID.Hex() == tenantID {
+			return membership.Role
+		}
+	}
+	return ""
+}
+
+func isTenantAdmin(actor domain.Actor) bool {
+	return actor.Role == "admin" || actor.OrganizationRole == "admin"
+}
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectUsecase struct {
+	projectRepo usecases.IProjectRepo
+	taskRepo usecases.ITaskRepo
+	userRepo usecases.IUserRepo
+}
+
+func NewProjectUsecase(pr usecases.IProjectRepo, tr usecases.ITaskRepo, ur usecases.IUserRepo) *ProjectUsecase {
+	return &ProjectUsecase{
+		projectRepo: pr,
+		taskRepo: tr,
+		userRepo: ur,
+	}
+}
+
+func (pu *ProjectUsecase) Create(actor domain.Actor, project *domain.Project) (domain.Project, error) {
+	if project.Name == "" {
+		return domain.Project{}, errors.New("missing required fields")
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return domain.Project{}, errors.New("invalid user")
+	}
+
+	project.Members = []domain.ProjectMember{{UserID: ownerID, Role: "owner"}}
+	project.CreatedAt = time.Now()
+	project.UpdatedAt = time.Now()
+
+	newProject, err := pu.projectRepo.Create(actor.OrganizationID, project)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return newProject, nil
+}
+
+func (pu *ProjectUsecase) FetchAll(actor domain.Actor) ([]domain.Project, error) {
+	var projects []domain.Project
+	var err error
+
+	if isTenantAdmin(actor) {
+		projects, err = pu.projectRepo.FetchAll(actor.OrganizationID)
+	} else {
+		projects, err = pu.projectRepo.FetchByMember(actor.OrganizationID, actor.UserID)
+	}
+	if err != nil {
+		return []domain.Project{}, err
+	}
+	return projects, nil
+}
+
+func (pu *ProjectUsecase) Fetch(actor domain.Actor, id string) (domain.Project, error) {
+	return pu.authorize(actor, id, "viewer")
+}
+
+func (pu *ProjectUsecase) Update(actor domain.Actor, id string, project domain.Project) (domain.Project, error) {
+	if _, err := pu.authorize(actor, id, "owner"); err != nil {
+		return domain.Project{}, err
+	}
+
+	updatedProject, err := pu.projectRepo.Update(actor.OrganizationID, id, project)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return updatedProject, nil
+}
+
+func (pu *ProjectUsecase) Remove(actor domain.Actor, id string) error {
+	project, err := pu.authorize(actor, id, "owner")
+	if err != nil {
+		return err
+	}
+
+	tasks, err := pu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ProjectID: project.ID})
+	if err != nil {
+		return err
+	}
+	if len(tasks) > 0 {
+		return errors.New("project still has tasks")
+	}
+
+	return pu.projectRepo.Remove(actor.OrganizationID, id)
+}
+
+func (pu *ProjectUsecase) SetMember(actor domain.Actor, id string, member domain.ProjectMember) (domain.Project, error) {
+	project, err := pu.authorize(actor, id, "owner")
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if !validProjectRole(member.Role) {
+		return domain.Project{}, errors.New("invalid project role")
+	}
+
+	if _, err := pu.userRepo.FetchMember(actor.OrganizationID, member.UserID.Hex()); err != nil {
+		return domain.Project{}, errors.New("user is not a member of this organization")
+	}
+
+	members := []domain.ProjectMember{}
+	for _, existing := range project.Members {
+		if existing.UserID != member.UserID {
+			members = append(members, existing)
+		}
+	}
+	members = append(members, member)
+
+	if !hasProjectOwner(members) {
+		return domain.Project{}, errors.New("project must keep at least one owner")
+	}
+
+	updatedProject, err := pu.projectRepo.UpdateMembers(actor.OrganizationID, id, members)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return updatedProject, nil
+}
+
+func (pu *ProjectUsecase) RemoveMember(actor domain.Actor, id string, userID string) (domain.Project, error) {
+	project, err := pu.authorize(actor, id, "owner")
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	members := []domain.ProjectMember{}
+	for _, existing := range project.Members {
+		if existing.UserID.Hex() != userID {
+			members = append(members, existing)
+		}
+	}
+
+	if len(members) == len(project.Members) {
+		return domain.Project{}, errors.New("user is not a member of this project")
+	}
+	if !hasProjectOwner(members) {
+		return domain.Project{}, errors.New("project must keep at least one owner")
+	}
+
+	updatedProject, err := pu.projectRepo.UpdateMembers(actor.OrganizationID, id, members)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return updatedProject, nil
+}
+
+func (pu *ProjectUsecase) FetchTasks(actor domain.Actor, id string) ([]domain.Task, error) {
+	project, err := pu.authorize(actor, id, "viewer")
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	tasks, err := pu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ProjectID: project.ID})
+	if err != nil {
+		return []domain.Task{}, err
+	}
+	return tasks, nil
+}
+
+func (pu *ProjectUsecase) authorize(actor domain.Actor, id string, minimumRole string) (domain.Project, error) {
+	project, err := pu.projectRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return domain.Project{}, errors.New("project not found")
+	}
+
+	if isTenantAdmin(actor) {
+		return project, nil
+	}
+
+	role := projectRole(project, actor.UserID)
+	if role == "" {
+		return domain.Project{}, errors.New("project not found")
+	}
+	if projectRoleRank[role] < projectRoleRank[minimumRole] {
+		return domain.Project{}, errors.New("insufficient project permissions")
+	}
+	return project, nil
+}
+
+var projectRoleRank = map[string]int{
+	"viewer": 1,
+	"editor": 2,
+	"owner": 3,
+}
+
+func validProjectRole(role string) bool {
+	_, ok := projectRoleRank[role]
+	return ok
+}
+
+func projectRole(project domain.Project, userID string) string {
+	for _, member := range project.Members {
+		if member.UserID.Hex() == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+func hasProjectOwner(members []domain.ProjectMember) bool {
+	for _, member := range members {
+		if member.Role == "owner" {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
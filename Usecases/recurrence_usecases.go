@@ -0,0 +1,326 @@
+package usecases
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxRecurrenceSearchDays = 3700
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type RecurrenceRule struct {
+	Freq string
+	Interval int
+	ByDay []time.Weekday
+	ByMonthDay []int
+	Until time.Time
+}
+
+func ParseRecurrenceRule(rule string) (RecurrenceRule, error) {
+	parsed := RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return RecurrenceRule{}, errors.New("invalid recurrence rule part " + part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			parsed.Freq = strings.ToUpper(value)
+			if parsed.Freq != "DAILY" && parsed.Freq != "WEEKLY" && parsed.Freq != "MONTHLY" {
+				return RecurrenceRule{}, errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 1000 {
+				return RecurrenceRule{}, errors.New("INTERVAL must be between 1 and 1000")
+			}
+			parsed.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return RecurrenceRule{}, errors.New("invalid BYDAY value " + day)
+				}
+				parsed.ByDay = append(parsed.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return RecurrenceRule{}, errors.New("invalid BYMONTHDAY value " + day)
+				}
+				parsed.ByMonthDay = append(parsed.ByMonthDay, monthDay)
+			}
+		case "UNTIL":
+			until, err := parseRRuleTime(value)
+			if err != nil {
+				return RecurrenceRule{}, err
+			}
+			parsed.Until = until
+		default:
+			return RecurrenceRule{}, errors.New("unsupported recurrence rule part " + name)
+		}
+	}
+
+	if parsed.Freq == "" {
+		return RecurrenceRule{}, errors.New("recurrence rule needs a FREQ")
+	}
+	if len(parsed.ByDay) > 0 && parsed.Freq != "WEEKLY" {
+		return RecurrenceRule{}, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(parsed.ByMonthDay) > 0 && parsed.Freq != "MONTHLY" {
+		return RecurrenceRule{}, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return parsed, nil
+}
+
+func (r RecurrenceRule) Next(start time.Time, after time.Time) (time.Time, bool) {
+	from := after
+	if from.Before(start) {
+		from = start.Add(-time.Second)
+	}
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, start.Location())
+	for i := 0; i <= maxRecurrenceSearchDays; i++ {
+		candidate := time.Date(day.Year(), day.Month(), day.Day()+i, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		if !candidate.After(from) {
+			continue
+		}
+		if !r.Until.IsZero() && candidate.After(r.Until) {
+			return time.Time{}, false
+		}
+		if r.matches(start, candidate) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r RecurrenceRule) matches(start time.Time, candidate time.Time) bool {
+	switch r.Freq {
+	case "DAILY":
+		return civilDays(start, candidate)%r.Interval == 0
+	case "WEEKLY":
+		weeks := civilDays(weekStart(start), weekStart(candidate)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return candidate.Weekday() == start.Weekday()
+		}
+		for _, weekday := range r.ByDay {
+			if candidate.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case "MONTHLY":
+		months := (candidate.Year()-start.Year())*12 + int(candidate.Month()) - int(start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByMonthDay) == 0 {
+			return candidate.Day() == start.Day()
+		}
+		lastDay := time.Date(candidate.Year(), candidate.Month()+1, 0, 0, 0, 0, 0, candidate.Location()).Day()
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay < 0 {
+				monthDay = lastDay + monthDay + 1
+			}
+			if candidate.Day() == monthDay {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func (tu *TaskUsecase) UpdateSeries(actor domain.Actor, id string, update domain.Task) (domain.Task, error) {
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if task.SeriesID.IsZero() {
+		return domain.Task{}, errors.New("task is not part of a recurring series")
+	}
+
+	if err := validatePriority(update); err != nil {
+		return domain.Task{}, err
+	}
+	if update.Recurrence != nil {
+		if task.Recurrence == nil {
+			return domain.Task{}, errors.New("task series has ended")
+		}
+		if _, err := ParseRecurrenceRule(update.Recurrence.Rule); err != nil {
+			return domain.Task{}, err
+		}
+		update.Recurrence.Start = task.Recurrence.Start
+	}
+
+	series := domain.TaskFilter{
+		SeriesID: task.SeriesID,
+		ProjectID: task.ProjectID,
+		Statuses: []string{"pending", "in-progress"},
+	}
+	if err := tu.taskRepo.UpdateSeries(actor.OrganizationID, series, update); err != nil {
+		return domain.Task{}, err
+	}
+	return tu.taskRepo.Fetch(actor.OrganizationID, id)
+}
+
+func (tu *TaskUsecase) EndSeries(actor domain.Actor, id string) (domain.Task, error) {
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if task.Recurrence == nil {
+		return domain.Task{}, errors.New("task is not part of a recurring series")
+	}
+
+	series := domain.TaskFilter{SeriesID: task.SeriesID, ProjectID: task.ProjectID}
+	if err := tu.taskRepo.EndSeries(actor.OrganizationID, series); err != nil {
+		return domain.Task{}, err
+	}
+	return tu.taskRepo.Fetch(actor.OrganizationID, id)
+}
+
+func (tu *TaskUsecase) RollOverRecurring(now time.Time) error {
+	tasks, err := tu.taskRepo.FetchRecurringDue(now)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, task := range tasks {
+		if _, err := tu.spawnNextOccurrence(task.OrganizationID.Hex(), task, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (tu *TaskUsecase) StartRecurrenceRollover(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := tu.RollOverRecurring(time.Now()); err != nil {
+				log.Println("recurring task rollover failed:", err)
+			}
+		}
+	}
+}
+
+func (tu *TaskUsecase) spawnNextOccurrence(tenantID string, task domain.Task, now time.Time) (domain.Task, error) {
+	if task.Recurrence == nil {
+		return domain.Task{}, nil
+	}
+
+	rule, err := ParseRecurrenceRule(task.Recurrence.Rule)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	after := task.DueDate
+	if now.After(after) {
+		after = now
+	}
+	dueDate, ok := rule.Next(task.Recurrence.Start, after)
+	if !ok {
+		return domain.Task{}, nil
+	}
+
+	checklist := []domain.ChecklistItem{}
+	for _, item := range task.Checklist {
+		checklist = append(checklist, domain.ChecklistItem{ID: primitive.NewObjectID(), Title: item.Title})
+	}
+
+	next := domain.Task{
+		Title: task.Title,
+		Description: task.Description,
+		DueDate: dueDate,
+		Status: "pending",
+		Priority: task.Priority,
+		Rank: task.Rank,
+		ProjectID: task.ProjectID,
+		ParentID: task.ParentID,
+		AutoComplete: task.AutoComplete,
+		Checklist: checklist,
+		Labels: task.Labels,
+		Recurrence: task.Recurrence,
+		SeriesID: task.SeriesID,
+		CreatedBy: task.CreatedBy,
+		StatusChangedAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	var created domain.Task
+	var events *eventRecorder
+	err = tu.taskRepo.WithOutbox(func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(tu.events)
+		created = domain.Task{}
+		claimed, err := tasks.ClaimNextOccurrence(tenantID, task.ID.Hex())
+		if err != nil || !claimed {
+			return err
+		}
+		created, err = tasks.Create(tenantID, &next)
+		if err != nil {
+			return err
+		}
+		return events.record(outbox, tenantID, domain.EventTaskCreated, created)
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	events.dispatch()
+	return created, nil
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, errors.New("UNTIL must look like 20251231 or 20251231T235959Z")
+}
+
+func civilDays(from time.Time, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,184 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reminderLockName = "task_reminders"
+
+type ReminderConfig struct {
+	Interval time.Duration
+	DueSoonWindow time.Duration
+	LeaseDuration time.Duration
+	AutoFlagOverdue bool
+	Owner string
+	Now func() time.Time
+}
+
+type ReminderScheduler struct {
+	taskRepo usecases.ITaskRepo
+	projectRepo usecases.IProjectRepo
+	userRepo usecases.IUserRepo
+	lockRepo usecases.ILockRepo
+	notifier usecases.INotifier
+	config ReminderConfig
+}
+
+func DefaultReminderConfig() ReminderConfig {
+	return ReminderConfig{
+		Interval: time.Minute,
+		DueSoonWindow: time.Hour * 24,
+	}
+}
+
+func NewReminderScheduler(tr usecases.ITaskRepo, pr usecases.IProjectRepo, ur usecases.IUserRepo, lr usecases.ILockRepo, notifier usecases.INotifier, config ReminderConfig) *ReminderScheduler {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = config.Interval * 3
+	}
+	if config.Owner == "" {
+		config.Owner = primitive.NewObjectID().Hex()
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &ReminderScheduler{
+		taskRepo: tr,
+		projectRepo: pr,
+		userRepo: ur,
+		lockRepo: lr,
+		notifier: notifier,
+		config: config,
+	}
+}
+
+func (rs *ReminderScheduler) RunOnce() error {
+	now := rs.config.Now()
+
+	leader, err := rs.lockRepo.Acquire(reminderLockName, rs.config.Owner, now, rs.config.LeaseDuration)
+	if err != nil {
+		return err
+	}
+	if !leader {
+		return nil
+	}
+
+	recipients := map[string][]domain.User{}
+	var firstErr error
+
+	if rs.config.DueSoonWindow > 0 {
+		tasks, err := rs.taskRepo.FetchPendingReminders("due_soon", now, now.Add(rs.config.DueSoonWindow))
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := rs.remind("due_soon", task, now, recipients); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	tasks, err := rs.taskRepo.FetchPendingReminders("overdue", time.Time{}, now)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if rs.config.AutoFlagOverdue && !task.Overdue {
+			if err := rs.taskRepo.FlagOverdue(task.OrganizationID.Hex(), task.ID.Hex()); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			task.Overdue = true
+		}
+		if err := rs.remind("overdue", task, now, recipients); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (rs *ReminderScheduler) Start(stop <-chan struct{}) {
+	ticker := time.NewTicker(rs.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			if err := rs.lockRepo.Release(reminderLockName, rs.config.Owner); err != nil {
+				log.Println("releasing reminder lock failed:", err)
+			}
+			return
+		case <-ticker.C:
+			if err := rs.RunOnce(); err != nil {
+				log.Println("task reminders failed:", err)
+			}
+		}
+	}
+}
+
+func (rs *ReminderScheduler) remind(kind string, task domain.Task, now time.Time, cache map[string][]domain.User) error {
+	tenantID := task.OrganizationID.Hex()
+
+	key := tenantID + "/" + task.ProjectID.Hex()
+	audience, ok := cache[key]
+	if !ok {
+		var err error
+		audience, err = rs.audience(tenantID, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		cache[key] = audience
+	}
+
+	recipients := []domain.User{}
+	for _, user := range audience {
+		if kind == "due_soon" && user.NotificationPreferences.EmailOnDueSoon ||
+			kind == "overdue" && user.NotificationPreferences.EmailOnOverdue {
+			recipients = append(recipients, user)
+		}
+	}
+
+	notification := domain.Notification{
+		Kind: kind,
+		Task: task,
+		Recipients: recipients,
+		CreatedAt: now,
+	}
+	if err := rs.notifier.Notify(notification); err != nil {
+		return err
+	}
+	return rs.taskRepo.MarkReminded(tenantID, task.ID.Hex(), kind)
+}
+
+func (rs *ReminderScheduler) audience(tenantID string, projectID primitive.ObjectID) ([]domain.User, error) {
+	users, err := rs.userRepo.FetchAll(tenantID)
+	if err != nil {
+		return []domain.User{}, err
+	}
+	if projectID.IsZero() {
+		return users, nil
+	}
+
+	project, err := rs.projectRepo.Fetch(tenantID, projectID.Hex())
+	if err != nil {
+		return []domain.User{}, err
+	}
+
+	members := []domain.User{}
+	for _, user := range users {
+		if projectRole(project, user.ID.Hex()) != "" {
+			members = append(members, user)
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"errors"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+)
+
+const maxBulkOperations = 100
+
+type pendingEvent struct {
+	tenantID string
+	eventType string
+	data interface{}
+}
+
+type bufferedPublisher struct {
+	events []pendingEvent
+}
+
+func (bp *bufferedPublisher) Record(outbox usecases.IOutboxRepo, tenantID string, eventType string, data interface{}) (domain.Event, error) {
+	bp.events = append(bp.events, pendingEvent{tenantID: tenantID, eventType: eventType, data: data})
+	return domain.Event{Type: eventType}, nil
+}
+
+func (bp *bufferedPublisher) Dispatch(events []domain.Event) error {
+	return nil
+}
+
+type TaskBulkUsecase struct {
+	transactor usecases.ITaskTransactor
+	tasks *TaskUsecase
+}
+
+func NewTaskBulkUsecase(transactor usecases.ITaskTransactor, tasks *TaskUsecase) *TaskBulkUsecase {
+	return &TaskBulkUsecase{
+		transactor: transactor,
+		tasks: tasks,
+	}
+}
+
+func (bu *TaskBulkUsecase) Apply(actor domain.Actor, request domain.TaskBulkRequest) (domain.TaskBulkResponse, error) {
+	if request.Mode == "" {
+		request.Mode = "atomic"
+	}
+	if request.Mode != "atomic" && request.Mode != "best_effort" {
+		return domain.TaskBulkResponse{}, errors.New("mode must be atomic or best_effort")
+	}
+
+	operations, err := bu.operations(actor, request)
+	if err != nil {
+		return domain.TaskBulkResponse{}, err
+	}
+
+	if request.Mode == "best_effort" {
+		response := domain.TaskBulkResponse{Mode: request.Mode, Committed: true, Results: []domain.TaskBulkResult{}}
+		for i, operation := range operations {
+			response.Results = append(response.Results, bu.apply(bu.tasks, actor, i, operation))
+		}
+		return countBulkResults(response), nil
+	}
+
+	var results []domain.TaskBulkResult
+	var buffer *bufferedPublisher
+	var events *eventRecorder
+	failed := false
+	err = bu.transactor.WithTransaction(func(tx usecases.ITaskRepo) error {
+		results = []domain.TaskBulkResult{}
+		buffer = &bufferedPublisher{}
+		failed = false
+
+		tasks := *bu.tasks
+		tasks.taskRepo = tx
+		tasks.events = buffer
+		for i, operation := range operations {
+			result := bu.apply(&tasks, actor, i, operation)
+			results = append(results, result)
+			if result.Status == "failed" {
+				failed = true
+				return errors.New(result.Error)
+			}
+		}
+
+		return tx.WithOutbox(func(_ usecases.ITaskRepo, outbox usecases.IOutboxRepo) error {
+			events = newEventRecorder(bu.tasks.events)
+			for _, event := range buffer.events {
+				if err := events.record(outbox, event.tenantID, event.eventType, event.data); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil && !failed {
+		return domain.TaskBulkResponse{}, err
+	}
+
+	response := domain.TaskBulkResponse{Mode: request.Mode, Committed: !failed, Results: results}
+	if failed {
+		for i := range response.Results[:len(results)-1] {
+			response.Results[i].Status = "rolled_back"
+			response.Results[i].Task = nil
+		}
+		for i := len(results); i < len(operations); i++ {
+			response.Results = append(response.Results, domain.TaskBulkResult{Index: i, Op: operations[i].Op, ID: operations[i].ID, Status: "skipped"})
+		}
+		return countBulkResults(response), nil
+	}
+
+	events.dispatch()
+	return countBulkResults(response), nil
+}
+
+func (bu *TaskBulkUsecase) operations(actor domain.Actor, request domain.TaskBulkRequest) ([]domain.TaskBulkOperation, error) {
+	if request.Filter == nil && request.Patch == nil {
+		if len(request.Operations) == 0 {
+			return nil, errors.New("send operations or a filter with a patch")
+		}
+		if len(request.Operations) > maxBulkOperations {
+			return nil, errors.New("at most 100 operations can be sent at once")
+		}
+		return request.Operations, nil
+	}
+
+	if len(request.Operations) > 0 {
+		return nil, errors.New("operations cannot be combined with a filter")
+	}
+	if request.Filter == nil || request.Patch == nil {
+		return nil, errors.New("a filter needs a patch and a patch needs a filter")
+	}
+	if len(request.Filter.Labels) == 0 && request.Filter.Expression == "" {
+		return nil, errors.New("filter needs labels or a query")
+	}
+
+	tasks, err := bu.tasks.FetchAll(actor, *request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > maxBulkOperations {
+		return nil, errors.New("filter matches more than 100 tasks")
+	}
+
+	operations := []domain.TaskBulkOperation{}
+	for _, task := range tasks {
+		operations = append(operations, domain.TaskBulkOperation{Op: "update", ID: task.ID.Hex(), Task: *request.Patch})
+	}
+	return operations, nil
+}
+
+func (bu *TaskBulkUsecase) apply(tasks *TaskUsecase, actor domain.Actor, index int, operation domain.TaskBulkOperation) domain.TaskBulkResult {
+	result := domain.TaskBulkResult{Index: index, Op: operation.Op, ID: operation.ID}
+
+	var task domain.Task
+	var err error
+	switch operation.Op {
+	case "create":
+		task, err = tasks.Create(actor, &operation.Task)
+	case "update":
+		if operation.Task.Status == "" {
+			var existingTask domain.Task
+			existingTask, err = tasks.taskRepo.Fetch(actor.OrganizationID, operation.ID)
+			if err != nil {
+				break
+			}
+			operation.Task.Status = existingTask.Status
+		}
+		task, err = tasks.Update(actor, operation.ID, operation.Task)
+	case "delete":
+		err = tasks.Remove(actor, operation.ID)
+	default:
+		err = errors.New("op must be create, update or delete")
+	}
+
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Status = "succeeded"
+	if operation.Op != "delete" {
+		result.ID = task.ID.Hex()
+		result.Task = &task
+	}
+	return result
+}
+
+func countBulkResults(response domain.TaskBulkResponse) domain.TaskBulkResponse {
+	for _, result := range response.Results {
+		switch result.Status {
+		case "succeeded":
+			response.Succeeded++
+		case "failed":
+			response.Failed++
+		}
+	}
+	return response
+}
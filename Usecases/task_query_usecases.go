@@ -0,0 +1,557 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxTaskExpressionLength = 1000
+	maxTaskExpressionDepth = 16
+)
+
+var taskExpressionFields = map[string]string{
+	"status": "status",
+	"priority": "priority",
+	"title": "title",
+	"description": "description",
+	"due": "due_date",
+	"created": "created_at",
+	"updated": "updated_at",
+	"label": "labels",
+	"project": "project_id",
+	"overdue": "overdue",
+}
+
+var taskExpressionOperators = map[string]string{
+	":": "eq",
+	"=": "eq",
+	"!=": "ne",
+	"<": "lt",
+	"<=": "lte",
+	">": "gt",
+	">=": "gte",
+	"~": "contains",
+}
+
+var taskExpressionFieldOperators = map[string][]string{
+	"status": {"eq", "ne"},
+	"priority": {"eq", "ne", "lt", "lte", "gt", "gte"},
+	"title": {"eq", "ne", "contains"},
+	"description": {"eq", "ne", "contains"},
+	"due_date": {"eq", "ne", "lt", "lte", "gt", "gte"},
+	"created_at": {"eq", "ne", "lt", "lte", "gt", "gte"},
+	"updated_at": {"eq", "ne", "lt", "lte", "gt", "gte"},
+	"labels": {"eq", "ne"},
+	"project_id": {"eq", "ne"},
+	"overdue": {"eq", "ne"},
+}
+
+var taskStatuses = []string{"pending", "in-progress", "completed", "canceled"}
+
+var taskPriorities = []string{"low", "medium", "high", "urgent"}
+
+type taskExpressionParser struct {
+	input []rune
+	pos int
+	depth int
+	lookupLabel func(value string) (primitive.ObjectID, error)
+}
+
+func parseTaskExpression(input string, lookupLabel func(value string) (primitive.ObjectID, error)) (domain.TaskExpression, error) {
+	if len(input) > maxTaskExpressionLength {
+		return domain.TaskExpression{}, errors.New("query is too long")
+	}
+
+	parser := &taskExpressionParser{input: []rune(input), lookupLabel: lookupLabel}
+	expression, err := parser.parseOr()
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+
+	parser.skipSpace()
+	if parser.pos < len(parser.input) {
+		return domain.TaskExpression{}, parser.errorAt(parser.pos, "unexpected %q", string(parser.input[parser.pos]))
+	}
+	return expression, nil
+}
+
+func (tp *taskExpressionParser) parseOr() (domain.TaskExpression, error) {
+	first, err := tp.parseAnd()
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+
+	children := []domain.TaskExpression{first}
+	for tp.keyword("OR") {
+		next, err := tp.parseAnd()
+		if err != nil {
+			return domain.TaskExpression{}, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return domain.TaskExpression{Op: "or", Children: children}, nil
+}
+
+func (tp *taskExpressionParser) parseAnd() (domain.TaskExpression, error) {
+	first, err := tp.parseUnary()
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+
+	children := []domain.TaskExpression{first}
+	for {
+		tp.skipSpace()
+		if tp.pos >= len(tp.input) || tp.input[tp.pos] == ')' || tp.peekKeyword("OR") {
+			break
+		}
+		tp.keyword("AND")
+
+		next, err := tp.parseUnary()
+		if err != nil {
+			return domain.TaskExpression{}, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return domain.TaskExpression{Op: "and", Children: children}, nil
+}
+
+func (tp *taskExpressionParser) parseUnary() (domain.TaskExpression, error) {
+	tp.skipSpace()
+	negated := tp.keyword("NOT")
+	if !negated && tp.pos < len(tp.input) && tp.input[tp.pos] == '-' {
+		tp.pos++
+		negated = true
+	}
+	if !negated {
+		return tp.parsePrimary()
+	}
+
+	tp.depth++
+	if tp.depth > maxTaskExpressionDepth {
+		return domain.TaskExpression{}, tp.errorAt(tp.pos, "query is nested too deeply")
+	}
+	child, err := tp.parseUnary()
+	tp.depth--
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+	return domain.TaskExpression{Op: "not", Children: []domain.TaskExpression{child}}, nil
+}
+
+func (tp *taskExpressionParser) parsePrimary() (domain.TaskExpression, error) {
+	tp.skipSpace()
+	if tp.pos >= len(tp.input) {
+		return domain.TaskExpression{}, tp.errorAt(tp.pos, "expected a condition")
+	}
+	if tp.input[tp.pos] != '(' {
+		return tp.parseCondition()
+	}
+
+	open := tp.pos
+	tp.pos++
+	tp.depth++
+	if tp.depth > maxTaskExpressionDepth {
+		return domain.TaskExpression{}, tp.errorAt(open, "query is nested too deeply")
+	}
+	expression, err := tp.parseOr()
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+	tp.depth--
+
+	tp.skipSpace()
+	if tp.pos >= len(tp.input) || tp.input[tp.pos] != ')' {
+		return domain.TaskExpression{}, tp.errorAt(open, "unclosed parenthesis")
+	}
+	tp.pos++
+	return expression, nil
+}
+
+func (tp *taskExpressionParser) parseCondition() (domain.TaskExpression, error) {
+	fieldPos := tp.pos
+	for tp.pos < len(tp.input) && (unicode.IsLetter(tp.input[tp.pos]) || tp.input[tp.pos] == '_') {
+		tp.pos++
+	}
+	name := strings.ToLower(string(tp.input[fieldPos:tp.pos]))
+	if name == "" {
+		return domain.TaskExpression{}, tp.errorAt(fieldPos, "expected a field name")
+	}
+	field, ok := taskExpressionFields[name]
+	if !ok {
+		return domain.TaskExpression{}, tp.errorAt(fieldPos, "unknown field %q", name)
+	}
+
+	tp.skipSpace()
+	operatorPos := tp.pos
+	operator := ""
+	for _, candidate := range []string{"!=", "<=", ">=", ":", "=", "<", ">", "~"} {
+		if strings.HasPrefix(string(tp.input[tp.pos:]), candidate) {
+			operator = candidate
+			tp.pos += len(candidate)
+			break
+		}
+	}
+	if operator == "" {
+		return domain.TaskExpression{}, tp.errorAt(operatorPos, "expected an operator after %q", name)
+	}
+	op := taskExpressionOperators[operator]
+	if !containsString(taskExpressionFieldOperators[field], op) {
+		return domain.TaskExpression{}, tp.errorAt(operatorPos, "operator %q is not supported for %s", operator, name)
+	}
+
+	tp.skipSpace()
+	valuePos := tp.pos
+	value, err := tp.parseValue()
+	if err != nil {
+		return domain.TaskExpression{}, err
+	}
+	if value == "" {
+		return domain.TaskExpression{}, tp.errorAt(valuePos, "expected a value after %q", name+operator)
+	}
+
+	condition, err := tp.condition(field, op, value)
+	if err != nil {
+		return domain.TaskExpression{}, tp.errorAt(valuePos, "%s", err.Error())
+	}
+	return condition, nil
+}
+
+func (tp *taskExpressionParser) parseValue() (string, error) {
+	if tp.pos >= len(tp.input) || tp.input[tp.pos] != '"' {
+		start := tp.pos
+		for tp.pos < len(tp.input) && !unicode.IsSpace(tp.input[tp.pos]) && tp.input[tp.pos] != '(' && tp.input[tp.pos] != ')' {
+			tp.pos++
+		}
+		return string(tp.input[start:tp.pos]), nil
+	}
+
+	open := tp.pos
+	tp.pos++
+	var value strings.Builder
+	for tp.pos < len(tp.input) {
+		r := tp.input[tp.pos]
+		tp.pos++
+		switch {
+		case r == '\\' && tp.pos < len(tp.input):
+			value.WriteRune(tp.input[tp.pos])
+			tp.pos++
+		case r == '"':
+			return value.String(), nil
+		default:
+			value.WriteRune(r)
+		}
+	}
+	return "", tp.errorAt(open, "unterminated string")
+}
+
+func (tp *taskExpressionParser) condition(field string, op string, value string) (domain.TaskExpression, error) {
+	switch field {
+	case "status":
+		if !containsString(taskStatuses, value) {
+			return domain.TaskExpression{}, fmt.Errorf("invalid status %q", value)
+		}
+		return domain.TaskExpression{Op: op, Field: field, Value: value}, nil
+
+	case "priority":
+		points, ok := priorityPoints[value]
+		if !ok {
+			return domain.TaskExpression{}, fmt.Errorf("invalid priority %q", value)
+		}
+		if op == "ne" {
+			return domain.TaskExpression{Op: "nin", Field: field, Value: priorityEquivalents(value)}, nil
+		}
+		matched := []string{}
+		for _, priority := range taskPriorities {
+			if compareFloat(priorityPoints[priority], points, op) {
+				matched = append(matched, priority)
+				if priority == "medium" {
+					matched = append(matched, "")
+				}
+			}
+		}
+		return domain.TaskExpression{Op: "in", Field: field, Value: matched}, nil
+
+	case "title", "description":
+		return domain.TaskExpression{Op: op, Field: field, Value: value}, nil
+
+	case "due_date", "created_at", "updated_at":
+		return dateCondition(field, op, value)
+
+	case "labels":
+		if tp.lookupLabel == nil {
+			return domain.TaskExpression{}, errors.New("labels are not available")
+		}
+		id, err := tp.lookupLabel(value)
+		if err != nil {
+			return domain.TaskExpression{}, err
+		}
+		return domain.TaskExpression{Op: op, Field: field, Value: id}, nil
+
+	case "project_id":
+		if value == "none" {
+			return domain.TaskExpression{Op: "exists", Field: field, Value: op == "ne"}, nil
+		}
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return domain.TaskExpression{}, fmt.Errorf("invalid project id %q", value)
+		}
+		return domain.TaskExpression{Op: op, Field: field, Value: id}, nil
+
+	case "overdue":
+		if value != "true" && value != "false" {
+			return domain.TaskExpression{}, fmt.Errorf("overdue must be true or false, got %q", value)
+		}
+		if (value == "true") == (op == "eq") {
+			return domain.TaskExpression{Op: "eq", Field: field, Value: true}, nil
+		}
+		return domain.TaskExpression{Op: "ne", Field: field, Value: true}, nil
+	}
+	return domain.TaskExpression{}, fmt.Errorf("unknown field %q", field)
+}
+
+func dateCondition(field string, op string, value string) (domain.TaskExpression, error) {
+	if value == "none" && field == "due_date" && (op == "eq" || op == "ne") {
+		return domain.TaskExpression{Op: op, Field: field, Value: time.Time{}}, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	dayOnly := false
+	if err != nil {
+		at, err = time.Parse("2006-01-02", value)
+		dayOnly = true
+	}
+	if err != nil {
+		return domain.TaskExpression{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or RFC 3339", value)
+	}
+
+	condition := func(op string, at time.Time) domain.TaskExpression {
+		return domain.TaskExpression{Op: op, Field: field, Value: at}
+	}
+
+	var expression domain.TaskExpression
+	if !dayOnly {
+		expression = condition(op, at)
+	} else {
+		next := at.AddDate(0, 0, 1)
+		switch op {
+		case "eq":
+			expression = domain.TaskExpression{Op: "and", Children: []domain.TaskExpression{condition("gte", at), condition("lt", next)}}
+		case "ne":
+			expression = domain.TaskExpression{Op: "or", Children: []domain.TaskExpression{condition("lt", at), condition("gte", next)}}
+		case "lt":
+			expression = condition("lt", at)
+		case "lte":
+			expression = condition("lt", next)
+		case "gt":
+			expression = condition("gte", next)
+		case "gte":
+			expression = condition("gte", at)
+		}
+	}
+
+	if field == "due_date" && (op == "lt" || op == "lte") {
+		return domain.TaskExpression{Op: "and", Children: []domain.TaskExpression{condition("gt", time.Time{}), expression}}, nil
+	}
+	return expression, nil
+}
+
+func (tp *taskExpressionParser) skipSpace() {
+	for tp.pos < len(tp.input) && unicode.IsSpace(tp.input[tp.pos]) {
+		tp.pos++
+	}
+}
+
+func (tp *taskExpressionParser) peekKeyword(word string) bool {
+	start := tp.pos
+	found := tp.keyword(word)
+	tp.pos = start
+	return found
+}
+
+func (tp *taskExpressionParser) keyword(word string) bool {
+	start := tp.pos
+	tp.skipSpace()
+	end := tp.pos + len(word)
+	if end <= len(tp.input) && strings.EqualFold(string(tp.input[tp.pos:end]), word) &&
+	(end == len(tp.input) || unicode.IsSpace(tp.input[end]) || tp.input[end] == '(') {
+		tp.pos = end
+		return true
+	}
+	tp.pos = start
+	return false
+}
+
+func (tp *taskExpressionParser) errorAt(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), pos+1)
+}
+
+func (tu *TaskUsecase) taskExpression(actor domain.Actor, input string) (domain.TaskExpression, error) {
+	var labels map[string]primitive.ObjectID
+	lookupLabel := func(value string) (primitive.ObjectID, error) {
+		if labels == nil {
+			if tu.labelRepo == nil {
+				return primitive.NilObjectID, errors.New("labels are not available")
+			}
+			found, err := tu.labelRepo.FetchAll(actor.OrganizationID)
+			if err != nil {
+				return primitive.NilObjectID, err
+			}
+			labels = map[string]primitive.ObjectID{}
+			for _, label := range found {
+				labels[strings.ToLower(label.Name)] = label.ID
+				labels[label.ID.Hex()] = label.ID
+			}
+		}
+
+		id, ok := labels[strings.ToLower(value)]
+		if !ok {
+			return primitive.NilObjectID, fmt.Errorf("unknown label %q", value)
+		}
+		return id, nil
+	}
+	return parseTaskExpression(input, lookupLabel)
+}
+
+func matchTaskExpression(expression domain.TaskExpression, task domain.Task) bool {
+	switch expression.Op {
+	case "and":
+		for _, child := range expression.Children {
+			if !matchTaskExpression(child, task) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, child := range expression.Children {
+			if matchTaskExpression(child, task) {
+				return true
+			}
+		}
+		return false
+	case "not":
+		return !matchTaskExpression(expression.Children[0], task)
+	}
+
+	switch field := taskFieldValue(task, expression.Field).(type) {
+	case string:
+		switch expression.Op {
+		case "eq":
+			return field == expression.Value
+		case "ne":
+			return field != expression.Value
+		case "contains":
+			return strings.Contains(strings.ToLower(field), strings.ToLower(expression.Value.(string)))
+		case "in":
+			return containsString(expression.Value.([]string), field)
+		case "nin":
+			return !containsString(expression.Value.([]string), field)
+		}
+	case time.Time:
+		value := expression.Value.(time.Time)
+		switch expression.Op {
+		case "eq":
+			return field.Equal(value)
+		case "ne":
+			return !field.Equal(value)
+		case "lt":
+			return field.Before(value)
+		case "lte":
+			return !field.After(value)
+		case "gt":
+			return field.After(value)
+		case "gte":
+			return !field.Before(value)
+		}
+	case []primitive.ObjectID:
+		found := false
+		for _, id := range field {
+			found = found || id == expression.Value
+		}
+		return found == (expression.Op == "eq")
+	case primitive.ObjectID:
+		switch expression.Op {
+		case "exists":
+			return !field.IsZero() == expression.Value
+		case "eq":
+			return field == expression.Value
+		case "ne":
+			return field != expression.Value
+		}
+	case bool:
+		return (field == expression.Value) == (expression.Op == "eq")
+	}
+	return false
+}
+
+func taskFieldValue(task domain.Task, field string) interface{} {
+	switch field {
+	case "status":
+		return task.Status
+	case "priority":
+		return task.Priority
+	case "title":
+		return task.Title
+	case "description":
+		return task.Description
+	case "due_date":
+		return task.DueDate
+	case "created_at":
+		return task.CreatedAt
+	case "updated_at":
+		return task.UpdatedAt
+	case "labels":
+		return task.Labels
+	case "project_id":
+		return task.ProjectID
+	case "overdue":
+		return task.Overdue
+	}
+	return nil
+}
+
+func priorityEquivalents(priority string) []string {
+	if priority == "medium" {
+		return []string{"medium", ""}
+	}
+	return []string{priority}
+}
+
+func compareFloat(a float64, b float64, op string) bool {
+	switch op {
+	case "eq":
+		return a == b
+	case "ne":
+		return a != b
+	case "lt":
+		return a < b
+	case "lte":
+		return a <= b
+	case "gt":
+		return a > b
+	case "gte":
+		return a >= b
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userReportTotals struct {
+	report domain.TaskUserReport
+	cycleHours float64
+}
+
+type TaskReportAggregator struct {
+	taskRepo usecases.ITaskRepo
+}
+
+func NewTaskReportAggregator(tr usecases.ITaskRepo) *TaskReportAggregator {
+	return &TaskReportAggregator{
+		taskRepo: tr,
+	}
+}
+
+func (ra *TaskReportAggregator) Report(tenantID string, filter domain.TaskFilter, query domain.TaskReportQuery) (domain.TaskReport, error) {
+	report := domain.TaskReport{ByStatus: map[string]int{}, Throughput: []domain.TaskReportBucket{}, Users: []domain.TaskUserReport{}}
+	throughput := map[time.Time]int{}
+	buckets := []time.Time{}
+	cycleHours := 0.0
+	users := map[primitive.ObjectID]*userReportTotals{}
+	order := []primitive.ObjectID{}
+
+	err := ra.taskRepo.Each(tenantID, filter, func(task domain.Task) error {
+		report.ByStatus[task.Status]++
+
+		overdue := !task.DueDate.IsZero() && task.DueDate.Before(query.Now) && (task.Status == "pending" || task.Status == "in-progress")
+		if overdue {
+			report.Overdue++
+		}
+
+		completed := task.Status == "completed" && !task.CompletedAt.Before(query.From) && task.CompletedAt.Before(query.To)
+		hours := task.CompletedAt.Sub(task.CreatedAt).Hours()
+		if completed {
+			start := reportBucketStart(task.CompletedAt, query.Bucket)
+			if _, ok := throughput[start]; !ok {
+				buckets = append(buckets, start)
+			}
+			throughput[start]++
+			report.CycleTime.Completed++
+			cycleHours += hours
+		}
+
+		if task.CreatedBy.IsZero() {
+			return nil
+		}
+		totals, ok := users[task.CreatedBy]
+		if !ok {
+			totals = &userReportTotals{report: domain.TaskUserReport{UserID: task.CreatedBy}}
+			users[task.CreatedBy] = totals
+			order = append(order, task.CreatedBy)
+		}
+		totals.report.Total++
+		if overdue {
+			totals.report.Overdue++
+		}
+		if completed {
+			totals.report.Completed++
+			totals.cycleHours += hours
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.TaskReport{}, err
+	}
+
+	for _, start := range buckets {
+		report.Throughput = append(report.Throughput, domain.TaskReportBucket{Start: start, Completed: throughput[start]})
+	}
+	if report.CycleTime.Completed > 0 {
+		report.CycleTime.AverageHours = cycleHours / float64(report.CycleTime.Completed)
+	}
+	for _, userID := range order {
+		totals := users[userID]
+		if totals.report.Completed > 0 {
+			totals.report.AverageCycleHours = totals.cycleHours / float64(totals.report.Completed)
+		}
+		report.Users = append(report.Users, totals.report)
+	}
+	return report, nil
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+)
+
+const (
+	defaultReportDays = 30
+	maxReportBuckets = 366
+)
+
+type TaskReportUsecase struct {
+	reporter usecases.ITaskReporter
+	tasks *TaskUsecase
+}
+
+func NewTaskReportUsecase(reporter usecases.ITaskReporter, tasks *TaskUsecase) *TaskReportUsecase {
+	return &TaskReportUsecase{
+		reporter: reporter,
+		tasks: tasks,
+	}
+}
+
+func (ru *TaskReportUsecase) Report(actor domain.Actor, query domain.TaskQuery, from string, to string, bucket string) (domain.TaskReport, error) {
+	params := domain.TaskReportQuery{Bucket: bucket, Now: time.Now().UTC()}
+	if params.Bucket == "" {
+		params.Bucket = "week"
+	}
+	if params.Bucket != "day" && params.Bucket != "week" && params.Bucket != "month" {
+		return domain.TaskReport{}, errors.New("bucket must be day, week or month")
+	}
+
+	var err error
+	params.To = params.Now
+	if to != "" {
+		if params.To, err = parseReportDate("to", to, true); err != nil {
+			return domain.TaskReport{}, err
+		}
+	}
+	params.From = params.To.AddDate(0, 0, -defaultReportDays)
+	if from != "" {
+		if params.From, err = parseReportDate("from", from, false); err != nil {
+			return domain.TaskReport{}, err
+		}
+	}
+	if !params.From.Before(params.To) {
+		return domain.TaskReport{}, errors.New("from must be before to")
+	}
+	if len(reportBuckets(params)) > maxReportBuckets {
+		return domain.TaskReport{}, errors.New("the range spans more than 366 buckets, use a larger bucket")
+	}
+
+	filter, err := ru.tasks.queryFilter(actor, query)
+	if err != nil {
+		return domain.TaskReport{}, err
+	}
+
+	report, err := ru.reporter.Report(actor.OrganizationID, filter, params)
+	if err != nil {
+		return domain.TaskReport{}, err
+	}
+	return completeTaskReport(report, params), nil
+}
+
+func completeTaskReport(report domain.TaskReport, params domain.TaskReportQuery) domain.TaskReport {
+	report.From = params.From
+	report.To = params.To
+	report.Bucket = params.Bucket
+
+	byStatus := map[string]int{}
+	for _, status := range taskStatuses {
+		byStatus[status] = 0
+	}
+	report.Total = 0
+	for status, count := range report.ByStatus {
+		byStatus[status] = count
+		report.Total += count
+	}
+	report.ByStatus = byStatus
+
+	completed := map[time.Time]int{}
+	for _, bucket := range report.Throughput {
+		completed[bucket.Start.UTC()] += bucket.Completed
+	}
+	report.Throughput = []domain.TaskReportBucket{}
+	for _, start := range reportBuckets(params) {
+		report.Throughput = append(report.Throughput, domain.TaskReportBucket{Start: start, Completed: completed[start]})
+	}
+
+	report.CycleTime.AverageHours = roundHours(report.CycleTime.AverageHours)
+	users := []domain.TaskUserReport{}
+	for _, user := range report.Users {
+		user.AverageCycleHours = roundHours(user.AverageCycleHours)
+		users = append(users, user)
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if users[i].Completed != users[j].Completed {
+			return users[i].Completed > users[j].Completed
+		}
+		if users[i].Total != users[j].Total {
+			return users[i].Total > users[j].Total
+		}
+		return users[i].UserID.Hex() < users[j].UserID.Hex()
+	})
+	report.Users = users
+	return report
+}
+
+func reportBuckets(params domain.TaskReportQuery) []time.Time {
+	buckets := []time.Time{}
+	for start := reportBucketStart(params.From, params.Bucket); start.Before(params.To); start = nextReportBucket(start, params.Bucket) {
+		buckets = append(buckets, start)
+		if len(buckets) > maxReportBuckets {
+			break
+		}
+	}
+	return buckets
+}
+
+func reportBucketStart(at time.Time, bucket string) time.Time {
+	at = at.UTC()
+	switch bucket {
+	case "week":
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func nextReportBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func parseReportDate(name string, value string, endOfDay bool) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at.UTC(), nil
+	}
+	at, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, use YYYY-MM-DD or RFC 3339", name, value)
+	}
+	if endOfDay {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
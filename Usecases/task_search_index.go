@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var searchFieldWeights = map[string]float64{
+	"title": 5,
+	"description": 1,
+}
+
+type indexedTask struct {
+	task domain.Task
+	fields map[string][]string
+}
+
+type tenantSearchIndex struct {
+	tasks map[primitive.ObjectID]*indexedTask
+	postings map[string]map[primitive.ObjectID]bool
+}
+
+type TaskSearchIndex struct {
+	taskRepo usecases.ITaskRepo
+
+	mu sync.Mutex
+	tenants map[string]*tenantSearchIndex
+}
+
+func NewTaskSearchIndex(tr usecases.ITaskRepo) *TaskSearchIndex {
+	return &TaskSearchIndex{
+		taskRepo: tr,
+		tenants: map[string]*tenantSearchIndex{},
+	}
+}
+
+func (si *TaskSearchIndex) HandleEvent(event domain.Event) error {
+	if event.Type != domain.EventTaskCreated && event.Type != domain.EventTaskUpdated && event.Type != domain.EventTaskDeleted {
+		return nil
+	}
+
+	var task domain.Task
+	if err := json.Unmarshal([]byte(event.Payload), &task); err != nil {
+		return fmt.Errorf("cannot decode %s payload: %v", event.Type, err)
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	index, ok := si.tenants[event.OrganizationID.Hex()]
+	if !ok {
+		return nil
+	}
+	index.remove(task.ID)
+	if event.Type != domain.EventTaskDeleted {
+		index.add(task)
+	}
+	return nil
+}
+
+func (si *TaskSearchIndex) Search(tenantID string, filter domain.TaskFilter, search domain.TaskSearch, limit int) ([]domain.TaskSearchResult, error) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	index, ok := si.tenants[tenantID]
+	if !ok {
+		tasks, err := si.taskRepo.FetchAll(tenantID, domain.TaskFilter{})
+		if err != nil {
+			return []domain.TaskSearchResult{}, err
+		}
+		index = &tenantSearchIndex{tasks: map[primitive.ObjectID]*indexedTask{}, postings: map[string]map[primitive.ObjectID]bool{}}
+		for _, task := range tasks {
+			index.add(task)
+		}
+		si.tenants[tenantID] = index
+	}
+	return index.search(filter, search, limit), nil
+}
+
+func (ti *tenantSearchIndex) add(task domain.Task) {
+	entry := &indexedTask{task: task, fields: map[string][]string{
+		"title": tokenize(task.Title),
+		"description": tokenize(task.Description),
+	}}
+	ti.tasks[task.ID] = entry
+
+	for _, tokens := range entry.fields {
+		for _, token := range tokens {
+			if ti.postings[token] == nil {
+				ti.postings[token] = map[primitive.ObjectID]bool{}
+			}
+			ti.postings[token][task.ID] = true
+		}
+	}
+}
+
+func (ti *tenantSearchIndex) remove(id primitive.ObjectID) {
+	entry, ok := ti.tasks[id]
+	if !ok {
+		return
+	}
+	for _, tokens := range entry.fields {
+		for _, token := range tokens {
+			delete(ti.postings[token], id)
+			if len(ti.postings[token]) == 0 {
+				delete(ti.postings, token)
+			}
+		}
+	}
+	delete(ti.tasks, id)
+}
+
+func (ti *tenantSearchIndex) search(filter domain.TaskFilter, search domain.TaskSearch, limit int) []domain.TaskSearchResult {
+	terms := []string{}
+	for _, term := range search.Terms {
+		terms = append(terms, tokenize(term)...)
+	}
+	phrases := [][]string{}
+	for _, phrase := range search.Phrases {
+		tokens := tokenize(phrase)
+		phrases = append(phrases, tokens)
+		terms = append(terms, tokens...)
+	}
+	excluded := [][]string{}
+	for _, text := range search.Excluded {
+		excluded = append(excluded, tokenize(text))
+	}
+
+	visible := map[primitive.ObjectID]bool{}
+	for _, id := range filter.VisibleProjectIDs {
+		visible[id] = true
+	}
+
+	scores := map[primitive.ObjectID]float64{}
+	for _, term := range terms {
+		documents := ti.postings[term]
+		if len(documents) == 0 {
+			continue
+		}
+		idf := 1 + math.Log(float64(len(ti.tasks))/float64(len(documents)))
+		for id := range documents {
+			for field, tokens := range ti.tasks[id].fields {
+				scores[id] += searchFieldWeights[field] * float64(countToken(tokens, term)) * idf
+			}
+		}
+	}
+
+	results := []domain.TaskSearchResult{}
+	for id, score := range scores {
+		entry := ti.tasks[id]
+		if filter.Restricted && !entry.task.ProjectID.IsZero() && !visible[entry.task.ProjectID] {
+			continue
+		}
+		if filter.Expression != nil && !matchTaskExpression(*filter.Expression, entry.task) {
+			continue
+		}
+		if !entry.containsAll(phrases) || entry.containsAny(excluded) {
+			continue
+		}
+		results = append(results, domain.TaskSearchResult{Task: entry.task, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.ID.Hex() < results[j].Task.ID.Hex()
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func (it *indexedTask) containsAll(phrases [][]string) bool {
+	for _, phrase := range phrases {
+		if !it.contains(phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+func (it *indexedTask) containsAny(phrases [][]string) bool {
+	for _, phrase := range phrases {
+		if it.contains(phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *indexedTask) contains(phrase []string) bool {
+	for _, tokens := range it.fields {
+		for start := 0; start+len(phrase) <= len(tokens); start++ {
+			matched := true
+			for offset, token := range phrase {
+				if tokens[start+offset] != token {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countToken(tokens []string, token string) int {
+	count := 0
+	for _, candidate := range tokens {
+		if candidate == token {
+			count++
+		}
+	}
+	return count
+}
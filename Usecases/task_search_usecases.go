@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit = 100
+	maxSearchQueryLength = 256
+	snippetRadius = 60
+)
+
+type TaskSearchUsecase struct {
+	searcher usecases.ITaskSearcher
+	tasks *TaskUsecase
+}
+
+func NewTaskSearchUsecase(searcher usecases.ITaskSearcher, tasks *TaskUsecase) *TaskSearchUsecase {
+	return &TaskSearchUsecase{
+		searcher: searcher,
+		tasks: tasks,
+	}
+}
+
+func (su *TaskSearchUsecase) Search(actor domain.Actor, query string, expression string, limit int) ([]domain.TaskSearchResult, error) {
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return []domain.TaskSearchResult{}, errors.New("limit must be between 1 and 100")
+	}
+
+	search, err := parseTaskSearch(query)
+	if err != nil {
+		return []domain.TaskSearchResult{}, err
+	}
+
+	filter, err := su.tasks.visibilityFilter(actor)
+	if err != nil {
+		return []domain.TaskSearchResult{}, err
+	}
+	if expression != "" {
+		parsed, err := su.tasks.taskExpression(actor, expression)
+		if err != nil {
+			return []domain.TaskSearchResult{}, err
+		}
+		filter.Expression = &parsed
+	}
+
+	results, err := su.searcher.Search(actor.OrganizationID, filter, search, limit)
+	if err != nil {
+		return []domain.TaskSearchResult{}, err
+	}
+
+	terms := map[string]bool{}
+	for _, term := range append(append([]string{}, search.Terms...), search.Phrases...) {
+		for _, token := range tokenize(term) {
+			terms[token] = true
+		}
+	}
+	for i := range results {
+		highlights := map[string]string{}
+		if snippet := highlight(results[i].Task.Title, terms); snippet != "" {
+			highlights["title"] = snippet
+		}
+		if snippet := highlight(results[i].Task.Description, terms); snippet != "" {
+			highlights["description"] = snippet
+		}
+		results[i].Highlights = highlights
+	}
+	return results, nil
+}
+
+func parseTaskSearch(query string) (domain.TaskSearch, error) {
+	search := domain.TaskSearch{}
+	if len(query) > maxSearchQueryLength {
+		return search, errors.New("search query is too long")
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negated = true
+			i++
+		}
+
+		var text string
+		quoted := runes[i] == '"'
+		if quoted {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			text = strings.Join(strings.Fields(string(runes[i+1:end])), " ")
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			text = string(runes[i:end])
+			i = end
+		}
+
+		if len(tokenize(text)) == 0 {
+			continue
+		}
+		switch {
+		case negated:
+			search.Excluded = append(search.Excluded, text)
+		case quoted:
+			search.Phrases = append(search.Phrases, text)
+		default:
+			search.Terms = append(search.Terms, text)
+		}
+	}
+
+	if len(search.Terms) == 0 && len(search.Phrases) == 0 {
+		return search, errors.New("search query needs at least one term")
+	}
+	return search, nil
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func highlight(text string, terms map[string]bool) string {
+	runes := []rune(text)
+
+	type span struct{ start, end int }
+	matches := []span{}
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) && !unicode.IsDigit(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+			end++
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		for term := range terms {
+			if strings.HasPrefix(word, term) {
+				matches = append(matches, span{start, end})
+				break
+			}
+		}
+		start = end
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	from := matches[0].start - snippetRadius
+	if from < 0 {
+		from = 0
+	}
+	for from > 0 && !unicode.IsSpace(runes[from-1]) && from < matches[0].start {
+		from++
+	}
+	to := matches[0].end + snippetRadius
+	if to > len(runes) {
+		to = len(runes)
+	}
+	for to < len(runes) && !unicode.IsSpace(runes[to]) && to > matches[0].end {
+		to--
+	}
+
+	var snippet strings.Builder
+	if from > 0 {
+		snippet.WriteString("…")
+	}
+	position := from
+	for _, match := range matches {
+		if match.start < from || match.end > to {
+			continue
+		}
+		snippet.WriteString(html.EscapeString(string(runes[position:match.start])))
+		snippet.WriteString("<mark>" + html.EscapeString(string(runes[match.start:match.end])) + "</mark>")
+		position = match.end
+	}
+	snippet.WriteString(html.EscapeString(string(runes[position:to])))
+	if to < len(runes) {
+		snippet.WriteString("…")
+	}
+	return strings.TrimSpace(snippet.String())
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	taskChangeHistory = 1000
+	taskChangeBuffer = 64
+)
+
+type taskChangeSubscriber struct {
+	tenantID string
+	changes chan domain.TaskChange
+}
+
+type bufferedTaskChange struct {
+	seq uint64
+	tenantID string
+	change domain.TaskChange
+}
+
+type TaskChangeHub struct {
+	mu sync.Mutex
+	prefix string
+	seq uint64
+	history []bufferedTaskChange
+	subscribers map[*taskChangeSubscriber]bool
+}
+
+func NewTaskChangeHub() *TaskChangeHub {
+	return &TaskChangeHub{
+		prefix: primitive.NewObjectID().Hex(),
+		subscribers: map[*taskChangeSubscriber]bool{},
+	}
+}
+
+func (th *TaskChangeHub) HandleEvent(event domain.Event) error {
+	if event.Type != domain.EventTaskCreated && event.Type != domain.EventTaskUpdated && event.Type != domain.EventTaskDeleted {
+		return nil
+	}
+
+	var task domain.Task
+	if err := json.Unmarshal([]byte(event.Payload), &task); err != nil {
+		return fmt.Errorf("cannot decode %s payload: %v", event.Type, err)
+	}
+	th.broadcast(event.OrganizationID.Hex(), domain.TaskChange{Type: event.Type, Task: task})
+	return nil
+}
+
+func (th *TaskChangeHub) Subscribe(tenantID string, lastEventID string) (<-chan domain.TaskChange, func(), error) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	subscriber := &taskChangeSubscriber{tenantID: tenantID, changes: make(chan domain.TaskChange, taskChangeBuffer+taskChangeHistory)}
+	if seq, ok := th.parseID(lastEventID); ok {
+		for _, buffered := range th.history {
+			if buffered.seq > seq && buffered.tenantID == tenantID {
+				subscriber.changes <- buffered.change
+			}
+		}
+	}
+	th.subscribers[subscriber] = true
+
+	cancel := func() {
+		th.mu.Lock()
+		defer th.mu.Unlock()
+		th.drop(subscriber)
+	}
+	return subscriber.changes, cancel, nil
+}
+
+func (th *TaskChangeHub) broadcast(tenantID string, change domain.TaskChange) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	th.seq++
+	change.ID = th.prefix + "-" + strconv.FormatUint(th.seq, 10)
+	th.history = append(th.history, bufferedTaskChange{seq: th.seq, tenantID: tenantID, change: change})
+	if len(th.history) > taskChangeHistory {
+		th.history = th.history[len(th.history)-taskChangeHistory:]
+	}
+
+	for subscriber := range th.subscribers {
+		if subscriber.tenantID != tenantID {
+			continue
+		}
+		select {
+		case subscriber.changes <- change:
+		default:
+			th.drop(subscriber)
+		}
+	}
+}
+
+func (th *TaskChangeHub) drop(subscriber *taskChangeSubscriber) {
+	if th.subscribers[subscriber] {
+		delete(th.subscribers, subscriber)
+		close(subscriber.changes)
+	}
+}
+
+func (th *TaskChangeHub) parseID(id string) (uint64, bool) {
+	prefix, seq, found := strings.Cut(id, "-")
+	if !found || prefix != th.prefix {
+		return 0, false
+	}
+	parsed, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return parsed, true
+}
+
+type TaskStreamUsecase struct {
+	source usecases.ITaskChangeSource
+	tasks *TaskUsecase
+}
+
+func NewTaskStreamUsecase(source usecases.ITaskChangeSource, tasks *TaskUsecase) *TaskStreamUsecase {
+	return &TaskStreamUsecase{
+		source: source,
+		tasks: tasks,
+	}
+}
+
+func (su *TaskStreamUsecase) Stream(actor domain.Actor, lastEventID string) (<-chan domain.TaskChange, func(), error) {
+	if actor.OrganizationID == "" {
+		return nil, nil, errors.New("missing or invalid organization")
+	}
+
+	changes, stop, err := su.source.Subscribe(actor.OrganizationID, lastEventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	visible := make(chan domain.TaskChange)
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			stop()
+		})
+	}
+
+	go func() {
+		defer close(visible)
+		for {
+			select {
+			case <-done:
+				return
+			case change, ok := <-changes:
+				if !ok {
+					return
+				}
+				if !su.tasks.canView(actor, change.Task) {
+					continue
+				}
+				select {
+				case visible <- change:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return visible, cancel, nil
+}
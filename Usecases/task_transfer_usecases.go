@@ -0,0 +1,391 @@
+package usecases
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxImportRows = 1000
+
+var taskExportColumns = []string{
+	"id",
+	"external_id",
+	"title",
+	"description",
+	"status",
+	"priority",
+	"rank",
+	"due_date",
+	"project_id",
+	"parent_id",
+	"labels",
+	"created_at",
+	"updated_at",
+}
+
+var taskImportFields = []string{
+	"external_id",
+	"title",
+	"description",
+	"status",
+	"priority",
+	"rank",
+	"due_date",
+	"project_id",
+	"parent_id",
+}
+
+type taskRecord struct {
+	row int
+	values map[string]string
+}
+
+type dryRunTaskRepo struct {
+	usecases.ITaskRepo
+}
+
+func (dr dryRunTaskRepo) WithOutbox(fn func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error) error {
+	return fn(dr, nil)
+}
+
+func (dr dryRunTaskRepo) Create(tenantID string, task *domain.Task) (domain.Task, error) {
+	task.ID = primitive.NewObjectID()
+	return *task, nil
+}
+
+func (tu *TaskUsecase) Export(actor domain.Actor, query domain.TaskQuery, format string) (func(w io.Writer) error, error) {
+	if format != "csv" && format != "json" && format != "ndjson" {
+		return nil, errors.New("format must be csv, json or ndjson")
+	}
+
+	filter, err := tu.queryFilter(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "csv":
+		return func(w io.Writer) error {
+			writer := csv.NewWriter(w)
+			if err := writer.Write(taskExportColumns); err != nil {
+				return err
+			}
+			err := tu.taskRepo.Each(actor.OrganizationID, filter, func(task domain.Task) error {
+				return writer.Write(taskExportRecord(task))
+			})
+			writer.Flush()
+			if err != nil {
+				return err
+			}
+			return writer.Error()
+		}, nil
+	case "json":
+		return func(w io.Writer) error {
+			separator := "["
+			err := tu.taskRepo.Each(actor.OrganizationID, filter, func(task domain.Task) error {
+				encoded, err := json.Marshal(task)
+				if err != nil {
+					return err
+				}
+				if _, err := io.WriteString(w, separator); err != nil {
+					return err
+				}
+				separator = ","
+				_, err = w.Write(encoded)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if separator == "[" {
+				_, err = io.WriteString(w, "[]\n")
+				return err
+			}
+			_, err = io.WriteString(w, "]\n")
+			return err
+		}, nil
+	}
+
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		return tu.taskRepo.Each(actor.OrganizationID, filter, func(task domain.Task) error {
+			return encoder.Encode(task)
+		})
+	}, nil
+}
+
+func (tu *TaskUsecase) Import(actor domain.Actor, format string, input io.Reader, mapping map[string]string, dryRun bool) (domain.TaskImportReport, error) {
+	columns := map[string]string{}
+	for _, field := range taskImportFields {
+		columns[field] = field
+	}
+	for column, field := range mapping {
+		if !containsString(taskImportFields, field) {
+			return domain.TaskImportReport{}, fmt.Errorf("cannot map %q to unknown task field %q", column, field)
+		}
+		columns[field] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	records, header, err := readTaskRecords(format, input)
+	if err != nil {
+		return domain.TaskImportReport{}, err
+	}
+	if len(records) == 0 {
+		return domain.TaskImportReport{}, errors.New("import has no rows")
+	}
+	if len(records) > maxImportRows {
+		return domain.TaskImportReport{}, errors.New("at most 1000 rows can be imported at once")
+	}
+	for column := range mapping {
+		if header != nil && !containsString(header, strings.ToLower(strings.TrimSpace(column))) {
+			return domain.TaskImportReport{}, fmt.Errorf("column %q is missing", column)
+		}
+	}
+
+	externalIDs := []string{}
+	for _, record := range records {
+		if externalID := strings.TrimSpace(record.values[columns["external_id"]]); externalID != "" {
+			externalIDs = append(externalIDs, externalID)
+		}
+	}
+	existing := map[string]domain.Task{}
+	if len(externalIDs) > 0 {
+		tasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ExternalIDs: externalIDs})
+		if err != nil {
+			return domain.TaskImportReport{}, err
+		}
+		for _, task := range tasks {
+			existing[task.ExternalID] = task
+		}
+	}
+
+	importer := tu
+	if dryRun {
+		dryRunUsecase := *tu
+		dryRunUsecase.taskRepo = dryRunTaskRepo{tu.taskRepo}
+		dryRunUsecase.events = nil
+		importer = &dryRunUsecase
+	}
+
+	report := domain.TaskImportReport{DryRun: dryRun, Rows: []domain.TaskImportRow{}}
+	imported := map[string]bool{}
+	for _, record := range records {
+		row := domain.TaskImportRow{Row: record.row}
+
+		task, err := taskFromRecord(record.values, columns)
+		row.ExternalID = task.ExternalID
+		if err == nil {
+			if existingTask, ok := existing[task.ExternalID]; ok {
+				row.Status = "skipped"
+				if tu.canView(actor, existingTask) {
+					row.ID = existingTask.ID.Hex()
+				}
+				report.Skipped++
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+			if imported[task.ExternalID] {
+				err = errors.New("external id is used by an earlier row")
+			}
+		}
+
+		var created domain.Task
+		if err == nil {
+			created, err = importer.Create(actor, &task)
+		}
+		if err != nil {
+			row.Status = "failed"
+			row.Error = err.Error()
+			report.Failed++
+			report.Rows = append(report.Rows, row)
+			continue
+		}
+
+		if task.ExternalID != "" {
+			imported[task.ExternalID] = true
+		}
+		row.Status = "created"
+		if dryRun {
+			row.Status = "valid"
+		} else {
+			row.ID = created.ID.Hex()
+		}
+		report.Created++
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+func taskExportRecord(task domain.Task) []string {
+	record := []string{
+		task.ID.Hex(),
+		task.ExternalID,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Priority,
+		"",
+		"",
+		"",
+		"",
+		"",
+		task.CreatedAt.UTC().Format(time.RFC3339),
+		task.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if task.Rank != nil {
+		record[6] = strconv.Itoa(*task.Rank)
+	}
+	if !task.DueDate.IsZero() {
+		record[7] = task.DueDate.UTC().Format(time.RFC3339)
+	}
+	if !task.ProjectID.IsZero() {
+		record[8] = task.ProjectID.Hex()
+	}
+	if !task.ParentID.IsZero() {
+		record[9] = task.ParentID.Hex()
+	}
+	labels := []string{}
+	for _, label := range task.Labels {
+		labels = append(labels, label.Hex())
+	}
+	record[10] = strings.Join(labels, ";")
+	return record
+}
+
+func taskFromRecord(values map[string]string, columns map[string]string) (domain.Task, error) {
+	value := func(field string) string {
+		return strings.TrimSpace(values[columns[field]])
+	}
+
+	task := domain.Task{
+		ExternalID: value("external_id"),
+		Title: value("title"),
+		Description: value("description"),
+		Status: strings.ToLower(value("status")),
+		Priority: strings.ToLower(value("priority")),
+	}
+
+	if rank := value("rank"); rank != "" {
+		parsed, err := strconv.Atoi(rank)
+		if err != nil {
+			return task, fmt.Errorf("invalid rank %q", rank)
+		}
+		task.Rank = &parsed
+	}
+	if dueDate := value("due_date"); dueDate != "" {
+		parsed, err := time.Parse(time.RFC3339, dueDate)
+		if err != nil {
+			parsed, err = time.Parse("2006-01-02", dueDate)
+		}
+		if err != nil {
+			return task, fmt.Errorf("invalid due_date %q, use YYYY-MM-DD or RFC 3339", dueDate)
+		}
+		task.DueDate = parsed
+	}
+	for field, target := range map[string]*primitive.ObjectID{"project_id": &task.ProjectID, "parent_id": &task.ParentID} {
+		if id := value(field); id != "" {
+			parsed, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return task, fmt.Errorf("invalid %s %q", field, id)
+			}
+			*target = parsed
+		}
+	}
+	return task, nil
+}
+
+func readTaskRecords(format string, input io.Reader) ([]taskRecord, []string, error) {
+	switch format {
+	case "csv":
+		reader := csv.NewReader(input)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err == io.EOF {
+			return []taskRecord{}, []string{}, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		}
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+		}
+
+		records := []taskRecord{}
+		for len(records) <= maxImportRows {
+			fields, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+			}
+			line, _ := reader.FieldPos(0)
+			record := taskRecord{row: line, values: map[string]string{}}
+			for i, column := range header {
+				if i < len(fields) {
+					record.values[column] = fields[i]
+				}
+			}
+			records = append(records, record)
+		}
+		return records, header, nil
+	case "json", "ndjson":
+		decoder := json.NewDecoder(input)
+		if format == "json" {
+			if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+				return nil, nil, errors.New("invalid JSON: expected an array of tasks")
+			}
+		}
+
+		records := []taskRecord{}
+		for len(records) <= maxImportRows {
+			if format == "json" && !decoder.More() {
+				break
+			}
+			var object map[string]interface{}
+			err := decoder.Decode(&object)
+			if err == io.EOF && format == "ndjson" {
+				break
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid JSON on row %d: %v", len(records)+1, err)
+			}
+
+			record := taskRecord{row: len(records) + 1, values: map[string]string{}}
+			for key, value := range object {
+				record.values[strings.ToLower(strings.TrimSpace(key))] = recordValue(value)
+			}
+			records = append(records, record)
+		}
+		return records, nil, nil
+	}
+	return nil, nil, errors.New("format must be csv, json or ndjson")
+}
+
+func recordValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -2,23 +2,45 @@ package usecases
 
 import (
 	"errors"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	maxTaskDepth = 5
+	defaultNextUpLimit = 10
+	maxNextUpLimit = 100
+)
+
+var priorityPoints = map[string]float64{
+	"low": 10,
+	"medium": 20,
+	"high": 30,
+	"urgent": 40,
+}
+
 type TaskUsecase struct {
 	taskRepo usecases.ITaskRepo
+	projectRepo usecases.IProjectRepo
+	labelRepo usecases.ILabelRepo
+	events usecases.IEventPublisher
 }
 
-func NewTaskUsecase(tr usecases.ITaskRepo) *TaskUsecase {
+func NewTaskUsecase(tr usecases.ITaskRepo, pr usecases.IProjectRepo, lr usecases.ILabelRepo, events usecases.IEventPublisher) *TaskUsecase {
 	return &TaskUsecase{
 		taskRepo: tr,
+		projectRepo: pr,
+		labelRepo: lr,
+		events: events,
 	}
 }
 
-func (tu *TaskUsecase) Create(task *domain.Task) (domain.Task, error) {
+func (tu *TaskUsecase) Create(actor domain.Actor, task *domain.Task) (domain.Task, error) {
 	if task.Title == "" || task.Description == "" || 
 	time.Time.IsZero(task.DueDate) || task.Status == "" {
 		return domain.Task{}, errors.New("missing required fields")
@@ -30,46 +52,818 @@ func (tu *TaskUsecase) Create(task *domain.Task) (domain.Task, error) {
 		return domain.Task{}, errors.New("invalid status")
 	}
 
+	if task.Priority == "" {
+		task.Priority = "medium"
+	}
+	if err := validatePriority(*task); err != nil {
+		return domain.Task{}, err
+	}
+
+	task.SeriesID = primitive.NilObjectID
+	task.NextGenerated = false
+	if task.Recurrence != nil {
+		if _, err := ParseRecurrenceRule(task.Recurrence.Rule); err != nil {
+			return domain.Task{}, err
+		}
+		task.Recurrence.Start = task.DueDate
+		task.SeriesID = primitive.NewObjectID()
+	}
+
+	if !task.ParentID.IsZero() {
+		parent, err := tu.Fetch(actor, task.ParentID.Hex())
+		if err != nil {
+			return domain.Task{}, errors.New("parent task not found")
+		}
+		if task.ProjectID.IsZero() {
+			task.ProjectID = parent.ProjectID
+		}
+		if task.ProjectID != parent.ProjectID {
+			return domain.Task{}, errors.New("subtasks must belong to the parent's project")
+		}
+
+		depth, err := tu.depth(actor, parent)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		if depth+1 > maxTaskDepth {
+			return domain.Task{}, errors.New("subtasks cannot be nested this deep")
+		}
+	}
+
+	if err := tu.authorizeWrite(actor, task.ProjectID); err != nil {
+		return domain.Task{}, err
+	}
+
+	checklist := []domain.ChecklistItem{}
+	for _, item := range task.Checklist {
+		if item.Title == "" {
+			return domain.Task{}, errors.New("checklist items need a title")
+		}
+		checklist = append(checklist, domain.ChecklistItem{ID: primitive.NewObjectID(), Title: item.Title, Done: item.Done})
+	}
+	task.Checklist = checklist
+	task.BlockedBy = nil
+	task.Attachments = nil
+	task.Labels = nil
+	task.Progress = nil
+	task.Overdue = false
+
+	task.CreatedBy, _ = primitive.ObjectIDFromHex(actor.UserID)
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
-	newTask, err := tu.taskRepo.Create(task)
+	task.StatusChangedAt = task.CreatedAt
+	task.CompletedAt = time.Time{}
+	if task.Status == "completed" {
+		task.CompletedAt = task.CreatedAt
+	}
+	var newTask domain.Task
+	var events *eventRecorder
+	err := tu.taskRepo.WithOutbox(func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(tu.events)
+		created, err := tasks.Create(actor.OrganizationID, task)
+		if err != nil {
+			return err
+		}
+		newTask = created
+		return events.record(outbox, actor.OrganizationID, domain.EventTaskCreated, newTask)
+	})
 	if err != nil {
 		return domain.Task{}, err
 	}
+	events.dispatch()
 	return newTask, nil
 }
 
-func (tu *TaskUsecase) FetchAll() ([]domain.Task, error) {
-	tasks, err := tu.taskRepo.FetchAll()
+func (tu *TaskUsecase) FetchAll(actor domain.Actor, query domain.TaskQuery) ([]domain.Task, error) {
+	filter, err := tu.queryFilter(actor, query)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	tasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, filter)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	candidates := tasks
+	if (len(filter.Labels) > 0 || filter.Expression != nil) && len(tasks) > 0 {
+		parentIDs := []primitive.ObjectID{}
+		for _, task := range tasks {
+			parentIDs = append(parentIDs, task.ID)
+		}
+
+		childFilter := filter
+		childFilter.Labels = nil
+		childFilter.MatchAllLabels = false
+		childFilter.Expression = nil
+		childFilter.ParentIDs = parentIDs
+
+		candidates, err = tu.taskRepo.FetchAll(actor.OrganizationID, childFilter)
+		if err != nil {
+			return []domain.Task{}, err
+		}
+	}
+
+	children := map[primitive.ObjectID][]domain.Task{}
+	for _, task := range candidates {
+		if !task.ParentID.IsZero() {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		}
+	}
+	for i := range tasks {
+		tasks[i].Progress = taskProgress(tasks[i], children[tasks[i].ID])
+	}
+	return tasks, nil
+}
+
+func (tu *TaskUsecase) NextUp(actor domain.Actor, limit int) ([]domain.Task, error) {
+	if limit == 0 {
+		limit = defaultNextUpLimit
+	}
+	if limit < 0 || limit > maxNextUpLimit {
+		return []domain.Task{}, errors.New("limit must be between 1 and 100")
+	}
+
+	filter, err := tu.visibilityFilter(actor)
 	if err != nil {
 		return []domain.Task{}, err
 	}
+	filter.Statuses = []string{"pending", "in-progress"}
+
+	tasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, filter)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	now := time.Now()
+	for i := range tasks {
+		score := taskScore(tasks[i], now)
+		tasks[i].Score = &score
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if *a.Score != *b.Score {
+			return *a.Score > *b.Score
+		}
+		if (a.Rank == nil) != (b.Rank == nil) {
+			return a.Rank != nil
+		}
+		if a.Rank != nil && *a.Rank != *b.Rank {
+			return *a.Rank < *b.Rank
+		}
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
 	return tasks, nil
 }
 
-func (tu *TaskUsecase) Fetch(id string) (domain.Task, error) {
-	task, err := tu.taskRepo.Fetch(id)
+func (tu *TaskUsecase) Fetch(actor domain.Actor, id string) (domain.Task, error) {
+	task, err := tu.taskRepo.Fetch(actor.OrganizationID, id)
 	if err != nil {
 		return domain.Task{}, err
 	}
+
+	if !tu.canView(actor, task) {
+		return domain.Task{}, errors.New("task not found")
+	}
+
+	children, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: task.ID})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	task.Progress = taskProgress(task, children)
 	return task, nil
 }
 
-func(tu *TaskUsecase) Update(id string, task domain.Task) (domain.Task, error) {
+func (tu *TaskUsecase) FetchSubtasks(actor domain.Actor, id string) ([]domain.Task, error) {
+	task, err := tu.Fetch(actor, id)
+	if err != nil {
+		return []domain.Task{}, err
+	}
+
+	subtasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: task.ID})
+	if err != nil {
+		return []domain.Task{}, err
+	}
+	return subtasks, nil
+}
+
+func(tu *TaskUsecase) Update(actor domain.Actor, id string, task domain.Task) (domain.Task, error) {
 	status := task.Status
 	if status != "completed" && status != "in-progress" &&
 	status != "pending" && status != "canceled" {
 		return domain.Task{}, errors.New("invalid task status value")
 	}
+
+	if err := validatePriority(task); err != nil {
+		return domain.Task{}, err
+	}
+
+	existingTask, err := tu.taskRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := tu.authorizeWrite(actor, existingTask.ProjectID); err != nil {
+		return domain.Task{}, err
+	}
+	if (status == "completed" || status == "in-progress") && status != existingTask.Status {
+		if err := tu.ensureUnblocked(actor, existingTask); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	if !task.ProjectID.IsZero() && task.ProjectID != existingTask.ProjectID {
+		if err := tu.authorizeWrite(actor, task.ProjectID); err != nil {
+			return domain.Task{}, err
+		}
+
+		subtasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: existingTask.ID})
+		if err != nil {
+			return domain.Task{}, err
+		}
+		if len(subtasks) > 0 || (!existingTask.ParentID.IsZero() && task.ParentID.IsZero()) {
+			return domain.Task{}, errors.New("subtasks must belong to the parent's project")
+		}
+	}
+	if !task.ParentID.IsZero() && task.ParentID != existingTask.ParentID {
+		projectID := existingTask.ProjectID
+		if !task.ProjectID.IsZero() {
+			projectID = task.ProjectID
+		}
+		if err := tu.validateParent(actor, existingTask, projectID, task.ParentID); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	task.Checklist = nil
+	task.BlockedBy = nil
+	task.Attachments = nil
+	task.Labels = nil
+	task.Recurrence = nil
 	
-	task, err := tu.taskRepo.Update(id, task)
+	var events *eventRecorder
+	err = tu.taskRepo.WithOutbox(func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(tu.events)
+		updated, err := tasks.Update(actor.OrganizationID, id, task)
+		if err != nil {
+			return err
+		}
+		if err := events.record(outbox, actor.OrganizationID, domain.EventTaskUpdated, updated); err != nil {
+			return err
+		}
+		if updated.Status != existingTask.Status {
+			if err := events.record(outbox, actor.OrganizationID, domain.EventTaskStatusChanged, domain.TaskStatusChange{Task: updated, PreviousStatus: existingTask.Status}); err != nil {
+				return err
+			}
+		}
+		task = updated
+		return nil
+	})
 	if err != nil {
 		return domain.Task{}, err
 	}
+	events.dispatch()
+
+	if closedStatus(task.Status) && !closedStatus(existingTask.Status) {
+		if _, err := tu.spawnNextOccurrence(actor.OrganizationID, task, time.Now()); err != nil {
+			return domain.Task{}, err
+		}
+	}
+
+	if closedStatus(task.Status) && !closedStatus(existingTask.Status) && !task.ParentID.IsZero() {
+		if err := tu.completeParentIfDone(actor, task.ParentID); err != nil {
+			return domain.Task{}, err
+		}
+	}
 	return task, nil
 }
 
-func (tu *TaskUsecase) Remove(id string) error {
-	err := tu.taskRepo.Remove(id)
+func (tu *TaskUsecase) AddChecklistItem(actor domain.Actor, id string, title string) (domain.Task, error) {
+	if title == "" {
+		return domain.Task{}, errors.New("checklist items need a title")
+	}
+
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	checklist := append(task.Checklist, domain.ChecklistItem{ID: primitive.NewObjectID(), Title: title})
+	return tu.taskRepo.UpdateChecklist(actor.OrganizationID, id, checklist)
+}
+
+func (tu *TaskUsecase) UpdateChecklistItem(actor domain.Actor, id string, itemID string, update domain.ChecklistItemUpdate) (domain.Task, error) {
+	if update.Title != nil && *update.Title == "" {
+		return domain.Task{}, errors.New("checklist items need a title")
+	}
+
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	found := false
+	for i, item := range task.Checklist {
+		if item.ID.Hex() != itemID {
+			continue
+		}
+		if update.Title != nil {
+			task.Checklist[i].Title = *update.Title
+		}
+		if update.Done != nil {
+			task.Checklist[i].Done = *update.Done
+		}
+		found = true
+	}
+	if !found {
+		return domain.Task{}, errors.New("checklist item not found")
+	}
+
+	return tu.taskRepo.UpdateChecklist(actor.OrganizationID, id, task.Checklist)
+}
+
+func (tu *TaskUsecase) RemoveChecklistItem(actor domain.Actor, id string, itemID string) (domain.Task, error) {
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	checklist := []domain.ChecklistItem{}
+	for _, item := range task.Checklist {
+		if item.ID.Hex() != itemID {
+			checklist = append(checklist, item)
+		}
+	}
+	if len(checklist) == len(task.Checklist) {
+		return domain.Task{}, errors.New("checklist item not found")
+	}
+
+	return tu.taskRepo.UpdateChecklist(actor.OrganizationID, id, checklist)
+}
+
+func (tu *TaskUsecase) AddDependency(actor domain.Actor, id string, blockerID string) (domain.Task, error) {
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	blocker, err := tu.Fetch(actor, blockerID)
+	if err != nil {
+		return domain.Task{}, errors.New("blocking task not found")
+	}
+	if blocker.ID == task.ID {
+		return domain.Task{}, errors.New("a task cannot block itself")
+	}
+
+	for _, existing := range task.BlockedBy {
+		if existing == blocker.ID {
+			return task, nil
+		}
+	}
+
+	if err := tu.ensureNoDependencyCycle(actor, blocker, task.ID); err != nil {
+		return domain.Task{}, err
+	}
+
+	return tu.taskRepo.UpdateDependencies(actor.OrganizationID, id, append(task.BlockedBy, blocker.ID))
+}
+
+func (tu *TaskUsecase) RemoveDependency(actor domain.Actor, id string, blockerID string) (domain.Task, error) {
+	task, err := tu.writableTask(actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	blockedBy := []primitive.ObjectID{}
+	for _, existing := range task.BlockedBy {
+		if existing.Hex() != blockerID {
+			blockedBy = append(blockedBy, existing)
+		}
+	}
+	if len(blockedBy) == len(task.BlockedBy) {
+		return domain.Task{}, errors.New("task is not blocked by that task")
+	}
+
+	return tu.taskRepo.UpdateDependencies(actor.OrganizationID, id, blockedBy)
+}
+
+func (tu *TaskUsecase) DependencyGraph(actor domain.Actor, ids []string) (domain.DependencyGraph, error) {
+	filter, err := tu.visibilityFilter(actor)
+	if err != nil {
+		return domain.DependencyGraph{}, err
+	}
+
+	if len(ids) > 0 {
+		filter.IDs = []primitive.ObjectID{}
+		for _, id := range ids {
+			objectID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return domain.DependencyGraph{}, errors.New("invalid id " + id)
+			}
+			filter.IDs = append(filter.IDs, objectID)
+		}
+	}
+
+	tasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, filter)
+	if err != nil {
+		return domain.DependencyGraph{}, err
+	}
+
+	graph := domain.DependencyGraph{
+		Tasks: tasks,
+		Edges: []domain.DependencyEdge{},
+		Order: []primitive.ObjectID{},
+	}
+
+	inSet := map[primitive.ObjectID]bool{}
+	for _, task := range tasks {
+		inSet[task.ID] = true
+	}
+
+	remaining := map[primitive.ObjectID]int{}
+	dependents := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, task := range tasks {
+		for _, blockerID := range task.BlockedBy {
+			if !inSet[blockerID] {
+				continue
+			}
+			graph.Edges = append(graph.Edges, domain.DependencyEdge{BlockerID: blockerID, BlockedID: task.ID})
+			remaining[task.ID]++
+			dependents[blockerID] = append(dependents[blockerID], task.ID)
+		}
+	}
+
+	queue := []primitive.ObjectID{}
+	for _, task := range tasks {
+		if remaining[task.ID] == 0 {
+			queue = append(queue, task.ID)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		graph.Order = append(graph.Order, current)
+
+		for _, dependent := range dependents[current] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if len(graph.Order) != len(tasks) {
+		return domain.DependencyGraph{}, errors.New("task dependencies contain a cycle")
+	}
+	return graph, nil
+}
+
+func (tu *TaskUsecase) Remove(actor domain.Actor, id string) error {
+	existingTask, err := tu.taskRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tu.authorizeWrite(actor, existingTask.ProjectID); err != nil {
+		return err
+	}
+
+	subtasks, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: existingTask.ID})
+	if err != nil {
+		return err
+	}
+	if len(subtasks) > 0 {
+		return errors.New("task still has subtasks")
+	}
+	if len(existingTask.Attachments) > 0 {
+		return errors.New("task still has attachments")
+	}
+
+	var events *eventRecorder
+	err = tu.taskRepo.WithOutbox(func(tasks usecases.ITaskRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(tu.events)
+		if err := tasks.Remove(actor.OrganizationID, id); err != nil {
+			return err
+		}
+		return events.record(outbox, actor.OrganizationID, domain.EventTaskDeleted, existingTask)
+	})
+	if err != nil {
+		return err
+	}
+	events.dispatch()
+	return nil
+}
+
+func (tu *TaskUsecase) ensureUnblocked(actor domain.Actor, task domain.Task) error {
+	if len(task.BlockedBy) == 0 {
+		return nil
+	}
+
+	blockers, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{IDs: task.BlockedBy})
+	if err != nil {
+		return err
+	}
+	for _, blocker := range blockers {
+		if blocker.Status != "completed" && blocker.Status != "canceled" {
+			return errors.New("task is blocked by unresolved tasks")
+		}
+	}
+	return nil
+}
+
+func (tu *TaskUsecase) ensureNoDependencyCycle(actor domain.Actor, blocker domain.Task, blockedID primitive.ObjectID) error {
+	visited := map[primitive.ObjectID]bool{}
+	stack := []domain.Task{blocker}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current.ID == blockedID {
+			return errors.New("dependency would create a cycle")
+		}
+		if visited[current.ID] {
+			continue
+		}
+		visited[current.ID] = true
+
+		if len(current.BlockedBy) == 0 {
+			continue
+		}
+		blockers, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{IDs: current.BlockedBy})
+		if err != nil {
+			return err
+		}
+		stack = append(stack, blockers...)
+	}
+	return nil
+}
+
+func (tu *TaskUsecase) writableTask(actor domain.Actor, id string) (domain.Task, error) {
+	task, err := tu.taskRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := tu.authorizeWrite(actor, task.ProjectID); err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+func (tu *TaskUsecase) validateParent(actor domain.Actor, task domain.Task, projectID primitive.ObjectID, parentID primitive.ObjectID) error {
+	parent, err := tu.taskRepo.Fetch(actor.OrganizationID, parentID.Hex())
+	if err != nil {
+		return errors.New("parent task not found")
+	}
+	if parent.ProjectID != projectID {
+		return errors.New("subtasks must belong to the parent's project")
+	}
+
+	ancestor := parent
+	for i := 0; i <= maxTaskDepth; i++ {
+		if ancestor.ID == task.ID {
+			return errors.New("a task cannot be nested under its own subtask")
+		}
+		if ancestor.ParentID.IsZero() {
+			break
+		}
+		ancestor, err = tu.taskRepo.Fetch(actor.OrganizationID, ancestor.ParentID.Hex())
+		if err != nil {
+			return errors.New("parent task not found")
+		}
+	}
+
+	depth, err := tu.depth(actor, parent)
+	if err != nil {
+		return err
+	}
+	height, err := tu.height(actor, task, maxTaskDepth)
+	if err != nil {
+		return err
+	}
+	if depth+height > maxTaskDepth {
+		return errors.New("subtasks cannot be nested this deep")
+	}
+	return nil
+}
+
+func (tu *TaskUsecase) depth(actor domain.Actor, task domain.Task) (int, error) {
+	depth := 1
+	for !task.ParentID.IsZero() {
+		if depth > maxTaskDepth {
+			return 0, errors.New("subtasks cannot be nested this deep")
+		}
+
+		parent, err := tu.taskRepo.Fetch(actor.OrganizationID, task.ParentID.Hex())
+		if err != nil {
+			return 0, errors.New("parent task not found")
+		}
+		task = parent
+		depth++
+	}
+	return depth, nil
+}
+
+func (tu *TaskUsecase) height(actor domain.Actor, task domain.Task, limit int) (int, error) {
+	if limit == 0 {
+		return 0, errors.New("subtasks cannot be nested this deep")
+	}
+
+	children, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: task.ID})
+	if err != nil {
+		return 0, err
+	}
+
+	height := 1
+	for _, child := range children {
+		childHeight, err := tu.height(actor, child, limit-1)
+		if err != nil {
+			return 0, err
+		}
+		if childHeight+1 > height {
+			height = childHeight + 1
+		}
+	}
+	return height, nil
+}
+
+func (tu *TaskUsecase) completeParentIfDone(actor domain.Actor, parentID primitive.ObjectID) error {
+	parent, err := tu.taskRepo.Fetch(actor.OrganizationID, parentID.Hex())
+	if err != nil {
+		return err
+	}
+	if parent.AutoComplete == nil || !*parent.AutoComplete || parent.Status == "completed" {
+		return nil
+	}
+
+	children, err := tu.taskRepo.FetchAll(actor.OrganizationID, domain.TaskFilter{ParentID: parent.ID})
+	if err != nil {
+		return err
+	}
+	completed := 0
+	for _, child := range children {
+		if !closedStatus(child.Status) {
+			return nil
+		}
+		if child.Status == "completed" {
+			completed++
+		}
+	}
+	if completed == 0 {
+		return nil
+	}
+	if tu.ensureUnblocked(actor, parent) != nil {
+		return nil
+	}
+
+	_, err = tu.Update(actor, parent.ID.Hex(), domain.Task{Status: "completed"})
 	return err
+}
+
+func taskProgress(task domain.Task, children []domain.Task) *domain.TaskProgress {
+	progress := &domain.TaskProgress{
+		TotalSubtasks: len(children),
+		TotalChecklistItems: len(task.Checklist),
+	}
+	for _, child := range children {
+		if child.Status == "completed" {
+			progress.CompletedSubtasks++
+		}
+	}
+	for _, item := range task.Checklist {
+		if item.Done {
+			progress.CompletedChecklistItems++
+		}
+	}
+	return progress
+}
+
+func closedStatus(status string) bool {
+	return status == "completed" || status == "canceled"
+}
+
+func validatePriority(task domain.Task) error {
+	if task.Priority != "" {
+		if _, ok := priorityPoints[task.Priority]; !ok {
+			return errors.New("invalid priority")
+		}
+	}
+	if task.Rank != nil && *task.Rank < 0 {
+		return errors.New("rank must not be negative")
+	}
+	return nil
+}
+
+func taskScore(task domain.Task, now time.Time) float64 {
+	score, ok := priorityPoints[task.Priority]
+	if !ok {
+		score = priorityPoints["medium"]
+	}
+
+	days := task.DueDate.Sub(now).Hours() / 24
+	switch {
+	case days < 0:
+		score += 25 + math.Min(-days, 15)
+	case days < 14:
+		score += 14 - days
+	}
+	return math.Round(score*100) / 100
+}
+
+func applyTaskQuery(filter *domain.TaskFilter, query domain.TaskQuery) error {
+	switch query.LabelMatch {
+	case "", "any":
+		filter.MatchAllLabels = false
+	case "all":
+		filter.MatchAllLabels = true
+	default:
+		return errors.New("label_match must be any or all")
+	}
+
+	for _, labelID := range query.Labels {
+		id, err := primitive.ObjectIDFromHex(labelID)
+		if err != nil {
+			return errors.New("invalid label id " + labelID)
+		}
+		filter.Labels = append(filter.Labels, id)
+	}
+	return nil
+}
+
+func (tu *TaskUsecase) visibilityFilter(actor domain.Actor) (domain.TaskFilter, error) {
+	if isTenantAdmin(actor) {
+		return domain.TaskFilter{}, nil
+	}
+
+	projects, err := tu.projectRepo.FetchByMember(actor.OrganizationID, actor.UserID)
+	if err != nil {
+		return domain.TaskFilter{}, err
+	}
+
+	projectIDs := []primitive.ObjectID{}
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	return domain.TaskFilter{Restricted: true, VisibleProjectIDs: projectIDs}, nil
+}
+
+func (tu *TaskUsecase) queryFilter(actor domain.Actor, query domain.TaskQuery) (domain.TaskFilter, error) {
+	filter, err := tu.visibilityFilter(actor)
+	if err != nil {
+		return domain.TaskFilter{}, err
+	}
+
+	if err := applyTaskQuery(&filter, query); err != nil {
+		return domain.TaskFilter{}, err
+	}
+
+	if query.Expression != "" {
+		expression, err := tu.taskExpression(actor, query.Expression)
+		if err != nil {
+			return domain.TaskFilter{}, err
+		}
+		filter.Expression = &expression
+	}
+	return filter, nil
+}
+
+func (tu *TaskUsecase) canView(actor domain.Actor, task domain.Task) bool {
+	if isTenantAdmin(actor) || task.ProjectID.IsZero() {
+		return true
+	}
+
+	project, err := tu.projectRepo.Fetch(actor.OrganizationID, task.ProjectID.Hex())
+	if err != nil {
+		return false
+	}
+	return projectRole(project, actor.UserID) != ""
+}
+
+func (tu *TaskUsecase) authorizeWrite(actor domain.Actor, projectID primitive.ObjectID) error {
+	if projectID.IsZero() {
+		if !isTenantAdmin(actor) {
+			return errors.New("only admins can manage tasks outside a project")
+		}
+		return nil
+	}
+
+	project, err := tu.projectRepo.Fetch(actor.OrganizationID, projectID.Hex())
+	if err != nil {
+		return errors.New("project not found")
+	}
+
+	if isTenantAdmin(actor) {
+		return nil
+	}
+	if projectRoleRank[projectRole(project, actor.UserID)] < projectRoleRank["editor"] {
+		return errors.New("insufficient project permissions")
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,325 @@
+package usecases
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxViewNameLength = 100
+
+var taskViewSortFields = map[string]bool{
+	"title": true,
+	"status": true,
+	"priority": true,
+	"due": true,
+	"created": true,
+	"updated": true,
+	"rank": true,
+}
+
+var taskViewColumns = map[string]bool{
+	"title": true,
+	"description": true,
+	"status": true,
+	"priority": true,
+	"rank": true,
+	"due_date": true,
+	"labels": true,
+	"project_id": true,
+	"parent_id": true,
+	"progress": true,
+	"overdue": true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+type TaskViewUsecase struct {
+	viewRepo usecases.ITaskViewRepo
+	tasks *TaskUsecase
+}
+
+func NewTaskViewUsecase(vr usecases.ITaskViewRepo, tasks *TaskUsecase) *TaskViewUsecase {
+	return &TaskViewUsecase{
+		viewRepo: vr,
+		tasks: tasks,
+	}
+}
+
+func (vu *TaskViewUsecase) Create(actor domain.Actor, view *domain.TaskView) (domain.TaskView, error) {
+	if view.Visibility == "" {
+		view.Visibility = "private"
+	}
+	if view.Sort == nil {
+		view.Sort = []string{}
+	}
+	if view.Columns == nil {
+		view.Columns = []string{}
+	}
+	if err := vu.validate(actor, view.Name, view.Visibility, view.Query, view.Sort, view.Columns); err != nil {
+		return domain.TaskView{}, err
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return domain.TaskView{}, errors.New("invalid user")
+	}
+
+	view.OwnerID = ownerID
+	view.Positions = nil
+	view.CreatedAt = time.Now()
+	view.UpdatedAt = time.Now()
+	return vu.viewRepo.Create(actor.OrganizationID, view)
+}
+
+func (vu *TaskViewUsecase) FetchAll(actor domain.Actor) ([]domain.TaskView, error) {
+	views, err := vu.viewRepo.FetchVisible(actor.OrganizationID, actor.UserID)
+	if err != nil {
+		return []domain.TaskView{}, err
+	}
+
+	for i := range views {
+		if position, ok := views[i].Positions[actor.UserID]; ok {
+			views[i].Position = &position
+		}
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i].Position, views[j].Position
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		return a != nil && *a < *b
+	})
+	return views, nil
+}
+
+func (vu *TaskViewUsecase) Fetch(actor domain.Actor, id string) (domain.TaskView, error) {
+	view, err := vu.viewRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+	if view.Visibility != "global" && view.OwnerID.Hex() != actor.UserID {
+		return domain.TaskView{}, errors.New("view not found")
+	}
+
+	if position, ok := view.Positions[actor.UserID]; ok {
+		view.Position = &position
+	}
+	return view, nil
+}
+
+func (vu *TaskViewUsecase) Update(actor domain.Actor, id string, update domain.TaskViewUpdate) (domain.TaskView, error) {
+	view, err := vu.authorizeChange(actor, id)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+
+	if update.Name != nil {
+		view.Name = *update.Name
+	}
+	if update.Visibility != nil {
+		view.Visibility = *update.Visibility
+	}
+	if update.Query != nil {
+		view.Query = *update.Query
+	}
+	if update.Sort != nil {
+		view.Sort = update.Sort
+	}
+	if update.Columns != nil {
+		view.Columns = update.Columns
+	}
+	if err := vu.validate(actor, view.Name, view.Visibility, view.Query, view.Sort, view.Columns); err != nil {
+		return domain.TaskView{}, err
+	}
+
+	updatedView, err := vu.viewRepo.Update(actor.OrganizationID, id, update)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+	if position, ok := updatedView.Positions[actor.UserID]; ok {
+		updatedView.Position = &position
+	}
+	return updatedView, nil
+}
+
+func (vu *TaskViewUsecase) Remove(actor domain.Actor, id string) error {
+	if _, err := vu.authorizeChange(actor, id); err != nil {
+		return err
+	}
+	return vu.viewRepo.Remove(actor.OrganizationID, id)
+}
+
+func (vu *TaskViewUsecase) Reorder(actor domain.Actor, ids []string) ([]domain.TaskView, error) {
+	views, err := vu.viewRepo.FetchVisible(actor.OrganizationID, actor.UserID)
+	if err != nil {
+		return []domain.TaskView{}, err
+	}
+
+	visible := map[string]bool{}
+	for _, view := range views {
+		visible[view.ID.Hex()] = true
+	}
+
+	ordered := []primitive.ObjectID{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if !visible[id] {
+			return []domain.TaskView{}, errors.New("view not found: " + id)
+		}
+		if seen[id] {
+			return []domain.TaskView{}, errors.New("view listed twice: " + id)
+		}
+		seen[id] = true
+
+		objectID, _ := primitive.ObjectIDFromHex(id)
+		ordered = append(ordered, objectID)
+	}
+
+	if err := vu.viewRepo.UpdatePositions(actor.OrganizationID, actor.UserID, ordered); err != nil {
+		return []domain.TaskView{}, err
+	}
+	return vu.FetchAll(actor)
+}
+
+func (vu *TaskViewUsecase) FetchTasks(actor domain.Actor, id string) ([]domain.Task, domain.TaskView, error) {
+	view, err := vu.Fetch(actor, id)
+	if err != nil {
+		return []domain.Task{}, domain.TaskView{}, err
+	}
+
+	tasks, err := vu.tasks.FetchAll(actor, view.Query)
+	if err != nil {
+		return []domain.Task{}, domain.TaskView{}, err
+	}
+	sortTasks(tasks, view.Sort)
+	return tasks, view, nil
+}
+
+func (vu *TaskViewUsecase) authorizeChange(actor domain.Actor, id string) (domain.TaskView, error) {
+	view, err := vu.Fetch(actor, id)
+	if err != nil {
+		return domain.TaskView{}, err
+	}
+	if view.OwnerID.Hex() != actor.UserID && !isTenantAdmin(actor) {
+		return domain.TaskView{}, errors.New("only the owner can change this view")
+	}
+	return view, nil
+}
+
+func (vu *TaskViewUsecase) validate(actor domain.Actor, name string, visibility string, query domain.TaskQuery, sortKeys []string, columns []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("view name is required")
+	}
+	if len(name) > maxViewNameLength {
+		return errors.New("view name must be at most 100 characters")
+	}
+	if visibility != "private" && visibility != "global" {
+		return errors.New("visibility must be private or global")
+	}
+
+	if err := applyTaskQuery(&domain.TaskFilter{}, query); err != nil {
+		return err
+	}
+	if query.Expression != "" {
+		if _, err := vu.tasks.taskExpression(actor, query.Expression); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortKeys {
+		if !taskViewSortFields[strings.TrimPrefix(key, "-")] {
+			return errors.New("cannot sort by " + key)
+		}
+	}
+	for _, column := range columns {
+		if !taskViewColumns[column] {
+			return errors.New("unknown column " + column)
+		}
+	}
+	return nil
+}
+
+func sortTasks(tasks []domain.Task, keys []string) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, key := range keys {
+			field := strings.TrimPrefix(key, "-")
+			order := compareTasks(tasks[i], tasks[j], field)
+			if order == 0 {
+				continue
+			}
+			if field != key {
+				return order > 0
+			}
+			return order < 0
+		}
+		return false
+	})
+}
+
+func compareTasks(a domain.Task, b domain.Task, field string) int {
+	switch field {
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "status":
+		return statusOrder(a.Status) - statusOrder(b.Status)
+	case "priority":
+		return compareNumbers(taskPriorityPoints(a), taskPriorityPoints(b))
+	case "due":
+		if a.DueDate.IsZero() != b.DueDate.IsZero() {
+			if a.DueDate.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		return a.DueDate.Compare(b.DueDate)
+	case "created":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "rank":
+		if (a.Rank == nil) != (b.Rank == nil) {
+			if a.Rank == nil {
+				return 1
+			}
+			return -1
+		}
+		if a.Rank == nil {
+			return 0
+		}
+		return *a.Rank - *b.Rank
+	}
+	return 0
+}
+
+func statusOrder(status string) int {
+	for i, candidate := range taskStatuses {
+		if candidate == status {
+			return i
+		}
+	}
+	return len(taskStatuses)
+}
+
+func taskPriorityPoints(task domain.Task) float64 {
+	if points, ok := priorityPoints[task.Priority]; ok {
+		return points
+	}
+	return priorityPoints["medium"]
+}
+
+func compareNumbers(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
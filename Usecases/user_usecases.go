@@ -2,28 +2,47 @@ package usecases
 
 import (
 	"errors"
+	"log"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/abeni-al7/task_manager/Domain"
 	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"golang.org/x/text/language"
 )
 
+const recoveryCodeCount = 10
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,32}$`)
+
 type UserUsecase struct {
 	userRepo usecases.IUserRepo
+	organizationRepo usecases.IOrganizationRepo
 	infra usecases.IInfrastructure
+	events usecases.IEventPublisher
 }
 
-func NewUserUsecase(ur usecases.IUserRepo, infra usecases.IInfrastructure) *UserUsecase {
+func NewUserUsecase(ur usecases.IUserRepo, or usecases.IOrganizationRepo, infra usecases.IInfrastructure, events usecases.IEventPublisher) *UserUsecase {
 	return &UserUsecase{
 		userRepo: ur,
+		organizationRepo: or,
 		infra: infra,
+		events: events,
 	}
 }
 
-func (uu *UserUsecase) Register(user *domain.User) (domain.User, error) {
+func (uu *UserUsecase) Register(user *domain.User, organizationName string) (domain.User, error) {
+	user.Username = strings.TrimSpace(user.Username)
 	if user.Username == "" || user.Email == "" || user.Password == "" {
 		return domain.User{}, errors.New("missing required fields")
 	}
+	if !usernamePattern.MatchString(user.Username) {
+		return domain.User{}, errors.New("username must be 3 to 32 letters, digits, dots, dashes or underscores")
+	}
 
 	_, err := uu.userRepo.FetchByUsername(user.Username)
 	if err == nil {
@@ -35,7 +54,8 @@ func (uu *UserUsecase) Register(user *domain.User) (domain.User, error) {
 		return domain.User{}, errors.New("unable to regiter user")
 	}
 
-	if count == 0 {
+	organizationName = strings.TrimSpace(organizationName)
+	if count == 0 && organizationName == "" {
 		user.Role = "admin"
 	} else {
 		user.Role = "regular"
@@ -50,34 +70,249 @@ func (uu *UserUsecase) Register(user *domain.User) (domain.User, error) {
 	}
 	user.Password = hashedPassword
 
-	*user, err = uu.userRepo.Register(user)
+	var organization domain.Organization
+	if organizationName != "" {
+		organization, err = uu.organizationRepo.Create(&domain.Organization{Name: organizationName, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		if err != nil {
+			return domain.User{}, err
+		}
+		user.Memberships = []domain.Membership{{OrganizationID: organization.ID, Role: "admin"}}
+	}
+
+	var registered domain.User
+	var events *eventRecorder
+	err = uu.userRepo.WithOutbox(func(users usecases.IUserRepo, outbox usecases.IOutboxRepo) error {
+		events = newEventRecorder(uu.events)
+		registered, err = users.Register(user)
+		if err != nil {
+			return err
+		}
+		return events.record(outbox, "", domain.EventUserRegistered, registered)
+	})
 	if err != nil {
+		if organizationName != "" {
+			if removeErr := uu.organizationRepo.Remove(organization.ID.Hex()); removeErr != nil {
+				log.Println("removing organization of failed registration:", removeErr)
+			}
+		}
 		return domain.User{}, errors.New(err.Error())
 	}
+	*user = registered
+	events.dispatch()
 	return *user, nil
 }
 
-func (uu *UserUsecase) Login(username string, password string) (string, error) {
+func (uu *UserUsecase) Login(username string, password string) (domain.LoginResult, error) {
 	if username == "" || password == "" {
-		return "", errors.New("missing username or password")
+		return domain.LoginResult{}, errors.New("missing username or password")
 	}
 
 	existingUser, err := uu.userRepo.FetchByUsername(username)
 	if err != nil {
-		return "", errors.New("invalid username or password")
+		return domain.LoginResult{}, errors.New("invalid username or password")
 	}
 
 	err = uu.infra.ComparePassword([]byte(existingUser.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid username or password")
+		return domain.LoginResult{}, errors.New("invalid username or password")
+	}
+
+	return issueLoginResult(uu.infra, &existingUser)
+}
+
+func (uu *UserUsecase) LoginTwoFactor(challenge string, code string) (domain.LoginResult, error) {
+	if challenge == "" || code == "" {
+		return domain.LoginResult{}, errors.New("missing challenge or code")
+	}
+
+	userID, err := uu.infra.ValidateChallengeToken(challenge)
+	if err != nil {
+		return domain.LoginResult{}, errors.New(err.Error())
+	}
+
+	existingUser, err := uu.userRepo.Fetch(userID)
+	if err != nil || !existingUser.TwoFactorEnabled {
+		return domain.LoginResult{}, errors.New("invalid or expired challenge")
+	}
+
+	if !uu.verifySecondFactor(&existingUser, code) {
+		return domain.LoginResult{}, errors.New("invalid two-factor code")
 	}
 
 	jwtToken, err := uu.infra.GenerateJwtToken(&existingUser)
 	if err != nil {
-		return "", errors.New("unable to generate token")
+		return domain.LoginResult{}, errors.New("unable to generate token")
 	}
 
-	return jwtToken, nil
+	return domain.LoginResult{Token: jwtToken}, nil
+}
+
+func (uu *UserUsecase) EnrollTwoFactor(id string) (domain.TwoFactorEnrollment, error) {
+	user, err := uu.userRepo.Fetch(id)
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, errors.New("user does not exist")
+	}
+
+	if user.TwoFactorEnabled {
+		return domain.TwoFactorEnrollment{}, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := uu.infra.GenerateTotpSecret()
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, errors.New(err.Error())
+	}
+
+	err = uu.userRepo.SetPendingTotpSecret(id, secret)
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, errors.New(err.Error())
+	}
+
+	return domain.TwoFactorEnrollment{
+		Secret: secret,
+		ProvisioningURI: uu.infra.TotpProvisioningURI(secret, user.Username),
+	}, nil
+}
+
+func (uu *UserUsecase) ConfirmTwoFactor(id string, code string) ([]string, error) {
+	user, err := uu.userRepo.Fetch(id)
+	if err != nil {
+		return []string{}, errors.New("user does not exist")
+	}
+
+	if user.TwoFactorEnabled {
+		return []string{}, errors.New("two-factor authentication is already enabled")
+	}
+
+	if user.PendingTotpSecret == "" {
+		return []string{}, errors.New("two-factor enrollment has not been started")
+	}
+
+	counter, ok := uu.infra.MatchTotpCode(user.PendingTotpSecret, code)
+	if !ok {
+		return []string{}, errors.New("invalid two-factor code")
+	}
+
+	recoveryCodes, hashedCodes, err := uu.generateRecoveryCodes()
+	if err != nil {
+		return []string{}, errors.New(err.Error())
+	}
+
+	err = uu.userRepo.EnableTwoFactor(id, user.PendingTotpSecret, counter, hashedCodes)
+	if err != nil {
+		return []string{}, errors.New(err.Error())
+	}
+	return recoveryCodes, nil
+}
+
+func (uu *UserUsecase) DisableTwoFactor(id string, password string, code string) error {
+	user, err := uu.userRepo.Fetch(id)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	if !user.TwoFactorEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if uu.infra.ComparePassword([]byte(user.Password), []byte(password)) != nil {
+		return errors.New("incorrect password")
+	}
+
+	if !uu.verifySecondFactor(&user, code) {
+		return errors.New("invalid two-factor code")
+	}
+
+	err = uu.userRepo.DisableTwoFactor(id)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func (uu *UserUsecase) RegenerateRecoveryCodes(id string, code string) ([]string, error) {
+	user, err := uu.userRepo.Fetch(id)
+	if err != nil {
+		return []string{}, errors.New("user does not exist")
+	}
+
+	if !user.TwoFactorEnabled {
+		return []string{}, errors.New("two-factor authentication is not enabled")
+	}
+
+	counter, ok := uu.infra.MatchTotpCode(user.TotpSecret, code)
+	if !ok || !uu.claimTotpCounter(&user, counter) {
+		return []string{}, errors.New("invalid two-factor code")
+	}
+
+	recoveryCodes, hashedCodes, err := uu.generateRecoveryCodes()
+	if err != nil {
+		return []string{}, errors.New(err.Error())
+	}
+
+	err = uu.userRepo.UpdateRecoveryCodes(id, hashedCodes)
+	if err != nil {
+		return []string{}, errors.New(err.Error())
+	}
+	return recoveryCodes, nil
+}
+
+func (uu *UserUsecase) verifySecondFactor(user *domain.User, code string) bool {
+	if counter, ok := uu.infra.MatchTotpCode(user.TotpSecret, code); ok {
+		return uu.claimTotpCounter(user, counter)
+	}
+
+	for _, hashedCode := range user.RecoveryCodes {
+		if uu.infra.ComparePassword([]byte(hashedCode), []byte(code)) != nil {
+			continue
+		}
+
+		consumed, err := uu.userRepo.ConsumeRecoveryCode(user.ID.Hex(), hashedCode)
+		return err == nil && consumed
+	}
+	return false
+}
+
+func (uu *UserUsecase) claimTotpCounter(user *domain.User, counter int64) bool {
+	if counter <= user.TotpLastCounter {
+		return false
+	}
+
+	claimed, err := uu.userRepo.ClaimTotpCounter(user.ID.Hex(), counter)
+	return err == nil && claimed
+}
+
+func issueLoginResult(infra usecases.IInfrastructure, user *domain.User) (domain.LoginResult, error) {
+	if user.TwoFactorEnabled {
+		challenge, err := infra.GenerateChallengeToken(user)
+		if err != nil {
+			return domain.LoginResult{}, errors.New("unable to generate challenge")
+		}
+		return domain.LoginResult{TwoFactorRequired: true, Challenge: challenge}, nil
+	}
+
+	jwtToken, err := infra.GenerateJwtToken(user)
+	if err != nil {
+		return domain.LoginResult{}, errors.New("unable to generate token")
+	}
+
+	return domain.LoginResult{Token: jwtToken}, nil
+}
+
+func (uu *UserUsecase) generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes, err := uu.infra.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashedCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedCode, err := uu.infra.HashPassword(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashedCodes = append(hashedCodes, hashedCode)
+	}
+	return recoveryCodes, hashedCodes, nil
 }
 
 func (uu *UserUsecase) Promote(id string) (domain.User, error) {
@@ -93,8 +328,8 @@ func (uu *UserUsecase) Promote(id string) (domain.User, error) {
 	return user, nil
 }
 
-func (uu *UserUsecase) FetchAll() ([]domain.User, error) {
-	users, err := uu.userRepo.FetchAll()
+func (uu *UserUsecase) FetchAll(actor domain.Actor) ([]domain.User, error) {
+	users, err := uu.userRepo.FetchAll(actor.OrganizationID)
 	if err != nil {
 		return []domain.User{}, errors.New(err.Error())
 	}
@@ -109,14 +344,71 @@ func (uu *UserUsecase) Fetch(id string) (domain.User, error) {
 	return user, nil
 }
 
-func (uu *UserUsecase) Update(id string, updatedUser domain.User) (domain.User, error) {
-	user, err := uu.userRepo.Update(id, updatedUser)
+func (uu *UserUsecase) Update(id string, update domain.ProfileUpdate) (domain.User, error) {
+	if err := validateProfileUpdate(&update); err != nil {
+		return domain.User{}, err
+	}
+
+	if update.Username != nil {
+		existingUser, err := uu.userRepo.FetchByUsername(*update.Username)
+		if err == nil && existingUser.ID.Hex() != id {
+			return domain.User{}, errors.New("user with this username already exists")
+		}
+	}
+
+	user, err := uu.userRepo.Update(id, update)
 	if err != nil {
 		return domain.User{}, errors.New(err.Error())
 	}
 	return user, nil
 }
 
+func validateProfileUpdate(update *domain.ProfileUpdate) error {
+	if update.Username != nil {
+		*update.Username = strings.TrimSpace(*update.Username)
+		if !usernamePattern.MatchString(*update.Username) {
+			return errors.New("username must be 3 to 32 letters, digits, dots, dashes or underscores")
+		}
+	}
+
+	if update.Email != nil {
+		*update.Email = strings.TrimSpace(*update.Email)
+		address, err := mail.ParseAddress(*update.Email)
+		if err != nil || address.Address != *update.Email {
+			return errors.New("invalid email")
+		}
+	}
+
+	if update.DisplayName != nil {
+		*update.DisplayName = strings.TrimSpace(*update.DisplayName)
+		if utf8.RuneCountInString(*update.DisplayName) > 64 {
+			return errors.New("display name must be at most 64 characters")
+		}
+	}
+
+	if update.AvatarURL != nil && *update.AvatarURL != "" {
+		avatarURL, err := url.Parse(*update.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "https" && avatarURL.Scheme != "http") || avatarURL.Host == "" {
+			return errors.New("avatar url must be an absolute http or https url")
+		}
+	}
+
+	if update.Timezone != nil && *update.Timezone != "" {
+		if _, err := time.LoadLocation(*update.Timezone); err != nil {
+			return errors.New("invalid timezone")
+		}
+	}
+
+	if update.Locale != nil && *update.Locale != "" {
+		tag, err := language.Parse(*update.Locale)
+		if err != nil {
+			return errors.New("invalid locale")
+		}
+		*update.Locale = tag.String()
+	}
+	return nil
+}
+
 func (uu *UserUsecase) ChangePassword(id string, prevPassword string, newPassword string) error {
 	existingUser, err := uu.userRepo.Fetch(id)
 	if err != nil {
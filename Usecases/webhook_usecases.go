@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/abeni-al7/task_manager/Domain"
+	"github.com/abeni-al7/task_manager/Usecases/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxWebhookAttempts = 8
+	webhookBaseBackoff = time.Second * 30
+	webhookMaxBackoff = time.Hour * 6
+	webhookClaimLease = time.Minute * 2
+	webhookBatchSize = 100
+	defaultDeliveryLogLimit = 50
+	maxDeliveryLogLimit = 200
+)
+
+var webhookEvents = map[string]bool{
+	domain.EventTaskCreated: true,
+	domain.EventTaskUpdated: true,
+	domain.EventTaskStatusChanged: true,
+	domain.EventTaskDeleted: true,
+	domain.EventUserRegistered: true,
+}
+
+type webhookEnvelope struct {
+	ID string `json:"id"`
+	Event string `json:"event"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Data interface{} `json:"data"`
+}
+
+type WebhookUsecase struct {
+	webhookRepo usecases.IWebhookRepo
+	deliveryRepo usecases.IWebhookDeliveryRepo
+	sender usecases.IWebhookSender
+	infra usecases.IInfrastructure
+}
+
+func NewWebhookUsecase(wr usecases.IWebhookRepo, dr usecases.IWebhookDeliveryRepo, sender usecases.IWebhookSender, infra usecases.IInfrastructure) *WebhookUsecase {
+	return &WebhookUsecase{
+		webhookRepo: wr,
+		deliveryRepo: dr,
+		sender: sender,
+		infra: infra,
+	}
+}
+
+func (wu *WebhookUsecase) Create(actor domain.Actor, webhook *domain.Webhook) (domain.Webhook, error) {
+	if !isTenantAdmin(actor) {
+		return domain.Webhook{}, errors.New("only admins can manage webhooks")
+	}
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := validateWebhookEvents(actor, webhook.Events); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret, err := wu.infra.GenerateWebhookSecret()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	createdBy, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return domain.Webhook{}, errors.New("invalid user")
+	}
+
+	webhook.Secret = secret
+	webhook.Active = true
+	webhook.CreatedBy = createdBy
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+	return wu.webhookRepo.Create(actor.OrganizationID, webhook)
+}
+
+func (wu *WebhookUsecase) FetchAll(actor domain.Actor) ([]domain.Webhook, error) {
+	if !isTenantAdmin(actor) {
+		return []domain.Webhook{}, errors.New("only admins can manage webhooks")
+	}
+
+	webhooks, err := wu.webhookRepo.FetchAll(actor.OrganizationID)
+	if err != nil {
+		return []domain.Webhook{}, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (wu *WebhookUsecase) Fetch(actor domain.Actor, id string) (domain.Webhook, error) {
+	if !isTenantAdmin(actor) {
+		return domain.Webhook{}, errors.New("only admins can manage webhooks")
+	}
+
+	webhook, err := wu.webhookRepo.Fetch(actor.OrganizationID, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (wu *WebhookUsecase) Update(actor domain.Actor, id string, update domain.WebhookUpdate) (domain.Webhook, error) {
+	if !isTenantAdmin(actor) {
+		return domain.Webhook{}, errors.New("only admins can manage webhooks")
+	}
+	if update.URL != nil {
+		if err := validateWebhookURL(*update.URL); err != nil {
+			return domain.Webhook{}, err
+		}
+	}
+	if update.Events != nil {
+		if err := validateWebhookEvents(actor, update.Events); err != nil {
+			return domain.Webhook{}, err
+		}
+	}
+
+	webhook, err := wu.webhookRepo.Update(actor.OrganizationID, id, update)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (wu *WebhookUsecase) Remove(actor domain.Actor, id string) error {
+	if !isTenantAdmin(actor) {
+		return errors.New("only admins can manage webhooks")
+	}
+
+	if err := wu.webhookRepo.Remove(actor.OrganizationID, id); err != nil {
+		return err
+	}
+	return wu.deliveryRepo.RemoveByWebhook(actor.OrganizationID, id)
+}
+
+func (wu *WebhookUsecase) Deliveries(actor domain.Actor, id string, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := wu.Fetch(actor, id); err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryLogLimit
+	}
+	if limit > maxDeliveryLogLimit {
+		limit = maxDeliveryLogLimit
+	}
+	return wu.deliveryRepo.FetchByWebhook(actor.OrganizationID, id, limit)
+}
+
+func (wu *WebhookUsecase) DeadLetters(actor domain.Actor) ([]domain.WebhookDelivery, error) {
+	if !isTenantAdmin(actor) {
+		return []domain.WebhookDelivery{}, errors.New("only admins can manage webhooks")
+	}
+	return wu.deliveryRepo.FetchByStatus(actor.OrganizationID, "dead")
+}
+
+func (wu *WebhookUsecase) Retry(actor domain.Actor, id string, deliveryID string) (domain.WebhookDelivery, error) {
+	if _, err := wu.Fetch(actor, id); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery, err := wu.deliveryRepo.Fetch(actor.OrganizationID, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if delivery.WebhookID.Hex() != id {
+		return domain.WebhookDelivery{}, errors.New("webhook delivery not found")
+	}
+	return wu.deliveryRepo.Requeue(actor.OrganizationID, deliveryID, time.Now())
+}
+
+func (wu *WebhookUsecase) HandleEvent(event domain.Event) error {
+	if !webhookEvents[event.Type] {
+		return nil
+	}
+
+	tenantID := ""
+	if !event.OrganizationID.IsZero() {
+		tenantID = event.OrganizationID.Hex()
+	}
+
+	webhooks, err := wu.webhookRepo.FetchSubscribed(tenantID, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhookEnvelope{
+		ID: event.ID.Hex(),
+		Event: event.Type,
+		OrganizationID: tenantID,
+		CreatedAt: event.OccurredAt,
+		Data: json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return errors.New("cannot encode webhook payload")
+	}
+
+	now := time.Now()
+	var firstErr error
+	for _, webhook := range webhooks {
+		delivery := domain.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event: event.Type,
+			Payload: string(payload),
+			Status: "pending",
+			NextAttemptAt: now,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if _, err := wu.deliveryRepo.Create(webhook.OrganizationID.Hex(), &delivery); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (wu *WebhookUsecase) DeliverDue(now time.Time) error {
+	var firstErr error
+	for i := 0; i < webhookBatchSize; i++ {
+		delivery, ok, err := wu.deliveryRepo.ClaimDue(now, webhookClaimLease)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		if err := wu.deliver(delivery, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (wu *WebhookUsecase) StartDelivery(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second * 5)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := wu.DeliverDue(time.Now()); err != nil {
+				log.Println("webhook delivery failed:", err)
+			}
+		}
+	}
+}
+
+func (wu *WebhookUsecase) deliver(delivery domain.WebhookDelivery, now time.Time) error {
+	tenantID := delivery.OrganizationID.Hex()
+	attempt := domain.WebhookAttempt{At: now}
+
+	webhook, err := wu.webhookRepo.Fetch(tenantID, delivery.WebhookID.Hex())
+	if err != nil {
+		attempt.Error = "webhook no longer exists"
+		return wu.deliveryRepo.RecordAttempt(tenantID, delivery.ID.Hex(), attempt, "dead", now)
+	}
+	if !webhook.Active {
+		attempt.Error = "webhook is inactive"
+		return wu.deliveryRepo.RecordAttempt(tenantID, delivery.ID.Hex(), attempt, "dead", now)
+	}
+
+	timestamp := now.Unix()
+	headers := map[string]string{
+		"X-Webhook-Event": delivery.Event,
+		"X-Webhook-Delivery": delivery.ID.Hex(),
+		"X-Webhook-Timestamp": strconv.FormatInt(timestamp, 10),
+		"X-Webhook-Signature": wu.infra.SignWebhookPayload(webhook.Secret, timestamp, []byte(delivery.Payload)),
+	}
+
+	status, err := wu.sender.Send(webhook.URL, headers, []byte(delivery.Payload))
+	attempt.ResponseStatus = status
+	if err == nil && status >= 200 && status < 300 {
+		return wu.deliveryRepo.RecordAttempt(tenantID, delivery.ID.Hex(), attempt, "delivered", now)
+	}
+
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Error = fmt.Sprintf("unexpected response status %d", status)
+	}
+
+	attempts := len(delivery.Attempts) + 1
+	if attempts >= maxWebhookAttempts {
+		return wu.deliveryRepo.RecordAttempt(tenantID, delivery.ID.Hex(), attempt, "dead", now)
+	}
+	return wu.deliveryRepo.RecordAttempt(tenantID, delivery.ID.Hex(), attempt, "pending", now.Add(exponentialBackoff(webhookBaseBackoff, webhookMaxBackoff, attempts)))
+}
+
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+	return nil
+}
+
+func validateWebhookEvents(actor domain.Actor, events []string) error {
+	if len(events) == 0 {
+		return errors.New("webhook needs at least one event")
+	}
+	for _, event := range events {
+		if !webhookEvents[event] {
+			return errors.New("unknown webhook event " + event)
+		}
+		if event == domain.EventUserRegistered && actor.Role != "admin" {
+			return errors.New("only global admins can subscribe to user.registered")
+		}
+	}
+	return nil
+}